@@ -6,10 +6,16 @@ import (
 	"github.com/enthus-appdev/n8n-cli/internal/cmd"
 )
 
-var version = "dev"
+// Set by release builds with -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+var (
+	version = "dev"
+	commit  string
+	date    string
+)
 
 func main() {
+	cmd.SetBuildInfo(commit, date)
 	if err := cmd.Execute(version); err != nil {
-		os.Exit(1)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
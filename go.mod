@@ -2,7 +2,11 @@ module github.com/enthus-appdev/n8n-cli
 
 go 1.25.4
 
-require github.com/spf13/cobra v1.10.2
+require (
+	github.com/spf13/cobra v1.10.2
+	github.com/theory/jsonpath v0.12.1
+	go.yaml.in/yaml/v3 v3.0.5
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
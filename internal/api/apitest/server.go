@@ -0,0 +1,118 @@
+// Package apitest provides an in-process fake of the n8n public API for
+// exercising the client without a real instance.
+package apitest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Request is a request received by the fake server
+type Request struct {
+	Method string
+	// Path is relative to the server's prefix, e.g. /workflows/abc
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// Response is a canned reply for a route
+type Response struct {
+	Status int
+	// Body is encoded as JSON unless it is a string or []byte, which are
+	// sent verbatim (e.g. to simulate malformed JSON)
+	Body interface{}
+}
+
+// Server is a fake n8n API. Routes are matched by method and path; every
+// request is recorded. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	prefix   string
+	routes   map[string]Response
+	requests []Request
+}
+
+// NewServer starts a fake API server. Call Close when done.
+func NewServer() *Server {
+	s := &Server{prefix: "/api/v1", routes: make(map[string]Response)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// SetPrefix changes the path prefix removed from requests before they are
+// matched, /api/v1 by default. An empty prefix fakes a gateway for
+// clients with SetRawPath.
+func (s *Server) SetPrefix(prefix string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prefix = prefix
+}
+
+// Handle registers the response for method and path (relative to the
+// prefix).
+// Unregistered routes answer 404 with an n8n-style error message.
+func (s *Server) Handle(method, path string, resp Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[method+" "+path] = resp
+}
+
+// Requests returns a copy of all requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest returns the most recent request, if any
+func (s *Server) LastRequest() (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return Request{}, false
+	}
+	return s.requests[len(s.requests)-1], true
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	path := strings.TrimPrefix(r.URL.Path, s.prefix)
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   path,
+		Query:  r.URL.RawQuery,
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	resp, ok := s.routes[r.Method+" "+path]
+	s.mu.Unlock()
+
+	if !ok {
+		resp = Response{Status: http.StatusNotFound, Body: map[string]string{"message": "not found"}}
+	}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.Status)
+	switch b := resp.Body.(type) {
+	case nil:
+	case string:
+		_, _ = io.WriteString(w, b)
+	case []byte:
+		_, _ = w.Write(b)
+	default:
+		_ = json.NewEncoder(w).Encode(b)
+	}
+}
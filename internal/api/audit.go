@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+)
+
+// AuditEntry is one line of the audit log
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	User     string    `json:"user,omitempty"`
+	Instance string    `json:"instance"`
+	Command  string    `json:"command,omitempty"`
+	Method   string    `json:"method"`
+	Path     string    `json:"path"`
+	// Target is the ID of the affected resource, taken from the path or,
+	// for creations, from the response
+	Target  string `json:"target,omitempty"`
+	Status  int    `json:"status,omitempty"`
+	Outcome string `json:"outcome"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Audit outcomes
+const (
+	AuditOK     = "ok"
+	AuditFailed = "failed"
+)
+
+// AuditLog appends an entry for every mutating API request to a JSONL
+// file. Only the method, path and status are recorded; request bodies,
+// query strings and headers are left out so that no secrets end up in
+// the log. Use Transport to instrument an http.RoundTripper.
+type AuditLog struct {
+	path string
+	// User and Command are recorded with every entry
+	User    string
+	Command string
+	// Instances maps API hosts to instance names. Requests to other hosts
+	// are recorded with the host.
+	Instances map[string]string
+
+	mu     sync.Mutex
+	warned bool
+}
+
+// NewAuditLog creates an audit log that appends to the file at path
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path, Instances: make(map[string]string)}
+}
+
+// Transport wraps base so that mutating API requests passing through it
+// are recorded. A nil base uses http.DefaultTransport.
+func (l *AuditLog) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &auditTransport{base: base, log: l}
+}
+
+type auditTransport struct {
+	base http.RoundTripper
+	log  *AuditLog
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	endpoint, isAPI := endpointOf(req)
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || !isAPI {
+		return resp, err
+	}
+
+	entry := AuditEntry{
+		Time:     time.Now().UTC(),
+		User:     t.log.User,
+		Instance: t.log.instance(req.URL.Host),
+		Command:  t.log.Command,
+		Method:   req.Method,
+		Path:     req.URL.Path,
+		Target:   auditTarget(endpoint),
+		Outcome:  AuditOK,
+	}
+	switch {
+	case err != nil:
+		entry.Outcome = AuditFailed
+		entry.Error = err.Error()
+	case resp.StatusCode >= 400:
+		entry.Status = resp.StatusCode
+		entry.Outcome = AuditFailed
+	default:
+		entry.Status = resp.StatusCode
+		if entry.Target == "" && req.Method == http.MethodPost {
+			entry.Target = createdID(resp)
+		}
+	}
+	t.log.write(entry)
+	return resp, err
+}
+
+func (l *AuditLog) instance(host string) string {
+	if name, ok := l.Instances[host]; ok {
+		return name
+	}
+	return host
+}
+
+// write appends entry to the log file. The request was already sent, so
+// failures only produce a warning, once per run.
+func (l *AuditLog) write(entry AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	data, err := json.Marshal(entry)
+	if err == nil {
+		err = appendLine(l.path, data)
+	}
+	if err != nil && !l.warned {
+		l.warned = true
+		logging.Warn(fmt.Sprintf("failed to write audit log: %v", err), "file", l.path, "error", err)
+	}
+}
+
+func appendLine(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// auditTarget returns the ID of the resource an endpoint path refers to,
+// e.g. abc for /workflows/abc/activate
+func auditTarget(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(segments) < 2 {
+		return ""
+	}
+	return segments[1]
+}
+
+// createdID reads the ID of a created resource from the response. The
+// body is restored so the caller can still read it.
+func createdID(resp *http.Response) string {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var created struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if json.Unmarshal(body, &created) != nil {
+		return ""
+	}
+	return strings.Trim(string(created.ID), `"`)
+}
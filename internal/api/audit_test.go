@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+)
+
+func TestAuditLogEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		raw        bool
+		call       func(c *Client) error
+		wantPath   string
+		wantTarget string
+		logged     bool
+	}{
+		{name: "reads are not logged", call: func(c *Client) error { _, err := c.GetWorkflow("abc"); return err }},
+		{name: "update", call: func(c *Client) error { return c.ActivateWorkflow("abc") }, wantPath: "/api/v1/workflows/abc/activate", wantTarget: "abc", logged: true},
+		{name: "create", call: func(c *Client) error { _, err := c.CreateTag("Ops"); return err }, wantPath: "/api/v1/tags", wantTarget: "t1", logged: true},
+		{name: "raw path", raw: true, call: func(c *Client) error { return c.DeleteWorkflow("abc") }, wantPath: "/workflows/abc", wantTarget: "abc", logged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			if tt.raw {
+				srv.SetPrefix("")
+			}
+			srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+			srv.Handle(http.MethodPost, "/workflows/abc/activate", apitest.Response{Body: map[string]string{"id": "abc"}})
+			srv.Handle(http.MethodDelete, "/workflows/abc", apitest.Response{})
+			srv.Handle(http.MethodPost, "/tags", apitest.Response{Body: map[string]string{"id": "t1", "name": "Ops"}})
+
+			path := filepath.Join(t.TempDir(), "audit.log")
+			audit := NewAuditLog(path)
+			client := NewClient(srv.URL, "key")
+			client.SetRawPath(tt.raw)
+			client.httpClient.Transport = audit.Transport(nil)
+			if err := tt.call(client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			data, err := os.ReadFile(path)
+			if !tt.logged {
+				if !os.IsNotExist(err) {
+					t.Errorf("audit log written for a read: %s", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("read audit log: %v", err)
+			}
+			var entry AuditEntry
+			if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &entry); err != nil {
+				t.Fatalf("parse audit log %q: %v", data, err)
+			}
+			if entry.Path != tt.wantPath || entry.Target != tt.wantTarget || entry.Outcome != AuditOK {
+				t.Errorf("entry = %s %s target %q outcome %s, want %s target %q ok", entry.Method, entry.Path, entry.Target, entry.Outcome, tt.wantPath, tt.wantTarget)
+			}
+		})
+	}
+}
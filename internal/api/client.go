@@ -2,44 +2,252 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// apiKeyHeader is the header n8n reads the API key from
+const apiKeyHeader = "X-N8N-API-KEY"
+
+// apiPathPrefix is the path below which the public API is served
+const apiPathPrefix = "/api/v1"
+
+// apiPathKey is the request context key under which send stores the
+// endpoint path, e.g. /workflows/abc?x=1
+type apiPathKey struct{}
+
+// endpointOf returns the endpoint path of an API request without its
+// query, and false for other requests such as webhook calls
+func endpointOf(req *http.Request) (string, bool) {
+	path, ok := req.Context().Value(apiPathKey{}).(string)
+	if !ok {
+		return "", false
+	}
+	path, _, _ = strings.Cut(path, "?")
+	return path, true
+}
+
+// Authentication modes. AuthModeAPIKey sends the key in the X-N8N-API-KEY
+// header; AuthModeBearer sends it as "Authorization: Bearer <key>" for
+// deployments behind an OAuth proxy.
+const (
+	AuthModeAPIKey = "apikey"
+	AuthModeBearer = "bearer"
+)
+
 const defaultListPageSize = 250
 
+// Request timeouts. Requests that execute a workflow and wait for it to
+// finish get DefaultRunTimeout; all others fail after DefaultTimeout.
+const (
+	DefaultTimeout    = time.Minute
+	DefaultRunTimeout = 30 * time.Minute
+)
+
 // Client is the n8n API client
 type Client struct {
-	baseURL    string
+	baseURL string
+	// url is the base URL as given, for SetRawPath
+	url string
+	// pathPrefix goes between baseURL and the endpoint path
+	pathPrefix string
 	apiKey     string
 	httpClient *http.Client
+	headers    http.Header
+	authMode   string
+	timeout    time.Duration
+	runTimeout time.Duration
 }
 
-// NewClient creates a new n8n API client
-func NewClient(baseURL, apiKey string) *Client {
+// Option configures a client in NewClient
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	transport TransportOptions
+	wrappers  []func(http.RoundTripper) http.RoundTripper
+}
+
+// WithTransportOptions tunes the connection handling of the client's
+// transport. Without it, DefaultTransportOptions apply.
+func WithTransportOptions(opts TransportOptions) Option {
+	return func(o *clientOptions) {
+		o.transport = opts
+	}
+}
+
+// WithRoundTripper wraps the client's transport, e.g. in
+// CallStats.Transport. Wrappers given later end up outermost.
+func WithRoundTripper(wrap func(http.RoundTripper) http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.wrappers = append(o.wrappers, wrap)
+	}
+}
+
+// NewClient creates a new n8n API client. Each client has a transport of
+// its own, so options never affect other HTTP requests of the process.
+func NewClient(baseURL, apiKey string, opts ...Option) *Client {
+	o := clientOptions{transport: DefaultTransportOptions()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var transport http.RoundTripper = NewTransport(o.transport)
+	for _, wrap := range o.wrappers {
+		transport = wrap(transport)
+	}
+
 	return &Client{
-		baseURL: baseURL,
-		apiKey:  apiKey,
-		httpClient: &http.Client{
-			Timeout: 5 * time.Minute,
-		},
+		baseURL:    trimBaseURL(baseURL),
+		url:        baseURL,
+		pathPrefix: apiPathPrefix,
+		apiKey:     apiKey,
+		httpClient: &http.Client{Transport: transport},
+		headers:    make(http.Header),
+		authMode:   AuthModeAPIKey,
+		timeout:    DefaultTimeout,
+		runTimeout: DefaultRunTimeout,
+	}
+}
+
+// SetTimeouts changes the request timeouts. runTimeout applies to
+// ExecuteWorkflow and TriggerWebhook, timeout to everything else. A zero
+// duration keeps the current value.
+//
+// Each request gets its own context deadline, which covers connecting,
+// sending the request, and reading the whole response. A run that waits
+// for the execution to finish is a single request, so runTimeout must
+// cover the entire execution.
+func (c *Client) SetTimeouts(timeout, runTimeout time.Duration) {
+	if timeout > 0 {
+		c.timeout = timeout
+	}
+	if runTimeout > 0 {
+		c.runTimeout = runTimeout
+	}
+}
+
+// trimBaseURL removes trailing slashes and an /api/v1 suffix, which the
+// client adds to every request itself
+func trimBaseURL(baseURL string) string {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	baseURL = strings.TrimSuffix(baseURL, apiPathPrefix)
+	return strings.TrimRight(baseURL, "/")
+}
+
+// SetRawPath controls whether requests go to the base URL followed
+// directly by the endpoint path, e.g. <url>/workflows, instead of
+// <url>/api/v1/workflows. This is for API gateways that expose the API
+// under a path of their own and add /api/v1 when forwarding. In raw mode
+// the base URL is used as given, even if it ends in /api/v1.
+func (c *Client) SetRawPath(raw bool) {
+	if raw {
+		c.baseURL = strings.TrimRight(strings.TrimSpace(c.url), "/")
+		c.pathPrefix = ""
+	} else {
+		c.baseURL = trimBaseURL(c.url)
+		c.pathPrefix = apiPathPrefix
+	}
+}
+
+// NormalizeBaseURL validates an instance URL and brings it into the form
+// NewClient expects: http or https, no trailing slash, and no /api/v1
+// suffix (a common copy-paste from the API docs). The returned warnings
+// describe corrections made and paths that look suspicious.
+//
+// With rawPath (see SetRawPath), the path is the one the gateway serves
+// the API under and is kept, including any /api/v1; a URL without a path
+// is reported instead.
+func NormalizeBaseURL(raw string, rawPath bool) (string, []string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid URL %q: %w", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", nil, fmt.Errorf("invalid URL %q: scheme must be http or https", raw)
 	}
+	if u.Host == "" {
+		return "", nil, fmt.Errorf("invalid URL %q: missing host", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", nil, fmt.Errorf("invalid URL %q: query strings and fragments are not supported", raw)
+	}
+
+	var warnings []string
+	path := strings.TrimRight(u.Path, "/")
+	switch {
+	case rawPath:
+		if path == "" {
+			warnings = append(warnings, fmt.Sprintf("URL has no path; with the raw path, requests will go to %s/workflows and so on, without %s", strings.TrimRight(u.String(), "/"), apiPathPrefix))
+		}
+	case strings.HasSuffix(path, apiPathPrefix):
+		path = strings.TrimRight(strings.TrimSuffix(path, apiPathPrefix), "/")
+		warnings = append(warnings, fmt.Sprintf("removed %s from the URL; it is added to every request automatically", apiPathPrefix))
+	case path == "/api" || strings.HasSuffix(path, "/api") || strings.Contains(path, "/api/"):
+		warnings = append(warnings, fmt.Sprintf("URL path %q looks like it includes an API prefix; requests will go to %s%s (see --raw-path for gateways that add it)", path, path, apiPathPrefix))
+	}
+	u.Path = path
+	u.RawPath = ""
+
+	return u.String(), warnings, nil
+}
+
+// SetAuthMode selects how the API key is sent. An empty mode keeps the
+// default X-N8N-API-KEY header.
+func (c *Client) SetAuthMode(mode string) error {
+	switch mode {
+	case "":
+		c.authMode = AuthModeAPIKey
+	case AuthModeAPIKey, AuthModeBearer:
+		c.authMode = mode
+	default:
+		return fmt.Errorf("unknown auth mode %q (expected %s or %s)", mode, AuthModeAPIKey, AuthModeBearer)
+	}
+	return nil
+}
+
+// AuthHeader returns the header that carries the API key in an auth mode
+func AuthHeader(mode string) string {
+	if mode == AuthModeBearer {
+		return "Authorization"
+	}
+	return apiKeyHeader
+}
+
+// authHeader returns the header that carries the credentials
+func (c *Client) authHeader() string {
+	return AuthHeader(c.authMode)
+}
+
+// SetHeader adds a header that is sent with every API request, e.g. for
+// an authenticating proxy in front of n8n. The header carrying the API key
+// cannot be overridden this way.
+func (c *Client) SetHeader(key, value string) error {
+	if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(c.authHeader()) {
+		return fmt.Errorf("header %s is set from the instance API key and cannot be overridden", c.authHeader())
+	}
+	c.headers.Set(key, value)
+	return nil
 }
 
 // Workflow represents an n8n workflow
 type Workflow struct {
-	ID          string                   `json:"id,omitempty"`
-	Name        string                   `json:"name"`
-	Active      bool                     `json:"active"`
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+	// IsArchived is only reported by n8n versions that support archiving
+	IsArchived  bool                     `json:"isArchived,omitempty"`
 	Nodes       []map[string]interface{} `json:"nodes"`
 	Connections map[string]interface{}   `json:"connections"`
 	Settings    map[string]interface{}   `json:"settings,omitempty"`
 	StaticData  interface{}              `json:"staticData,omitempty"`
+	PinData     map[string]interface{}   `json:"pinData,omitempty"`
 	Tags        []Tag                    `json:"tags,omitempty"`
 	Shared      []WorkflowShared         `json:"shared,omitempty"`
 	CreatedAt   *time.Time               `json:"createdAt,omitempty"`
@@ -82,6 +290,14 @@ type Execution struct {
 	Error        string                 `json:"error,omitempty"`
 }
 
+// Duration returns how long the execution ran, if it has finished
+func (e Execution) Duration() (time.Duration, bool) {
+	if e.StartedAt == nil || e.StoppedAt == nil {
+		return 0, false
+	}
+	return e.StoppedAt.Sub(*e.StartedAt), true
+}
+
 // ListWorkflowsOptions contains options for listing workflows
 type ListWorkflowsOptions struct {
 	Active            *bool
@@ -89,8 +305,9 @@ type ListWorkflowsOptions struct {
 	Name              string
 	ProjectID         string
 	ExcludePinnedData bool
-	Limit             int
-	Cursor            string
+	// Limit is the maximum number of workflows to return (0 = all)
+	Limit  int
+	Cursor string
 }
 
 // ListExecutionsOptions contains options for listing executions
@@ -99,8 +316,9 @@ type ListExecutionsOptions struct {
 	Status      string // running, success, error, waiting
 	ProjectID   string
 	IncludeData bool
-	Limit       int
-	Cursor      string
+	// Limit is the maximum number of executions to return (0 = all)
+	Limit  int
+	Cursor string
 }
 
 // Credential represents an n8n credential
@@ -130,6 +348,66 @@ type ListResult[T any] struct {
 
 // request makes an HTTP request to the n8n API
 func (c *Client) request(method, path string, body interface{}) ([]byte, error) {
+	return c.requestWithTimeout(method, path, body, c.timeout)
+}
+
+// requestWithTimeout works like request, but fails once timeout elapses
+func (c *Client) requestWithTimeout(method, path string, body interface{}, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	resp, err := c.send(ctx, method, path, body, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, requestError("failed to read response", err, timeout)
+	}
+
+	return respBody, nil
+}
+
+// requestStream works like request with the run timeout, but returns the
+// response body unread, so that large responses can be decoded while they
+// arrive. Closing the body releases the request.
+func (c *Client) requestStream(method, path string) (io.ReadCloser, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.runTimeout)
+	resp, err := c.send(ctx, method, path, nil, c.runTimeout)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &streamBody{ReadCloser: resp.Body, cancel: cancel, timeout: c.runTimeout}, nil
+}
+
+// streamBody is a response body that cancels its request when closed
+type streamBody struct {
+	io.ReadCloser
+	cancel  context.CancelFunc
+	timeout time.Duration
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = requestError("failed to read response", err, b.timeout)
+	}
+	return n, err
+}
+
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// send makes an HTTP request to the n8n API. Error statuses are returned
+// as *APIError with the body consumed; otherwise the caller must close
+// the response body.
+func (c *Client) send(ctx context.Context, method, path string, body interface{}, timeout time.Duration) (*http.Response, error) {
 	var reqBody io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -139,38 +417,104 @@ func (c *Client) request(method, path string, body interface{}) ([]byte, error)
 		reqBody = bytes.NewReader(jsonBody)
 	}
 
-	reqURL := c.baseURL + "/api/v1" + path
-	req, err := http.NewRequest(method, reqURL, reqBody)
+	// The stats and audit transports read the endpoint path from the
+	// context, since the URL path depends on SetRawPath
+	ctx = context.WithValue(ctx, apiPathKey{}, path)
+	reqURL := c.baseURL + c.pathPrefix + path
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("X-N8N-API-KEY", c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	if c.authMode == AuthModeBearer {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	} else {
+		req.Header.Set(apiKeyHeader, c.apiKey)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, requestError("request failed", err, timeout)
+	}
+	if resp.StatusCode < 400 {
+		return resp, nil
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, requestError("failed to read response", err, timeout)
+	}
+	apiErr := &APIError{
+		Method:     method,
+		URL:        req.URL.Redacted(),
+		StatusCode: resp.StatusCode,
+		Message:    strings.TrimSpace(string(respBody)),
+	}
+	var errBody struct {
+		Message string `json:"message"`
 	}
+	if json.Unmarshal(respBody, &errBody) == nil && errBody.Message != "" {
+		apiErr.Message = errBody.Message
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = strings.ToLower(http.StatusText(resp.StatusCode))
+	}
+	return nil, apiErr
+}
 
-	if resp.StatusCode >= 400 {
-		var apiErr struct {
-			Message string `json:"message"`
-		}
-		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Message != "" {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, apiErr.Message)
-		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+// decodeResponse decodes a JSON response body into v. Endpoints that
+// answer 204 No Content, or 200 without a body, leave v at its zero value
+// instead of failing, so callers don't need to know which ones do.
+func decodeResponse(body []byte, v interface{}) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
+	return nil
+}
 
-	return respBody, nil
+// requestError wraps an error from sending a request or reading its
+// response, pointing out timeouts
+func requestError(what string, err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("request timed out after %s: %w", timeout, err)
+	}
+	return fmt.Errorf("%s: %w", what, err)
+}
+
+// APIError is returned for API responses with an error status. The URL
+// never contains the API key, which is sent in a header; credentials in
+// the base URL are masked.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s -> %d: %s", e.Method, e.URL, e.StatusCode, e.Message)
+}
+
+// IsUnauthorized reports whether err is or wraps an API error with status
+// 401, i.e. the API key was rejected
+func IsUnauthorized(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnauthorized
+}
+
+// IsNotFound reports whether err is or wraps an API error with status 404
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
 }
 
 // listWorkflowsPage fetches a single page of workflows.
@@ -208,62 +552,117 @@ func (c *Client) listWorkflowsPage(opts ListWorkflowsOptions) (*ListResult[Workf
 		return nil, err
 	}
 
-	var resp ListResult[Workflow]
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	return &resp, nil
+	return parseList[Workflow](respBody)
 }
 
-// ListWorkflows returns all workflows, auto-paginating through all pages.
-// If opts.Cursor is set, only that single page is returned (manual pagination).
+// ListWorkflows returns workflows, auto-paginating until opts.Limit
+// workflows were collected (0 = all). If opts.Cursor is set, only that
+// single page is returned (manual pagination).
 func (c *Client) ListWorkflows(opts ListWorkflowsOptions) (*ListResult[Workflow], error) {
-	// Manual pagination: caller provided a cursor, return single page
-	if opts.Cursor != "" {
-		return c.listWorkflowsPage(opts)
+	return paginate(opts.Limit, opts.Cursor, func(pageSize int, cursor string) (*ListResult[Workflow], error) {
+		pageOpts := opts
+		pageOpts.Limit = pageSize
+		pageOpts.Cursor = cursor
+		return c.listWorkflowsPage(pageOpts)
+	})
+}
+
+// parseList decodes a list response. Depending on the n8n version and
+// endpoint, lists come either wrapped as {"data": [...], "nextCursor": ...}
+// or as a bare array; both are accepted. An empty body, as sent with 204
+// No Content, is an empty list; objects without a data field are an error
+// rather than an empty list.
+func parseList[T any](body []byte) (*ListResult[T], error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return &ListResult[T]{}, nil
+	}
+	if trimmed[0] == '[' {
+		var items []T
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %w", err)
+		}
+		return &ListResult[T]{Data: items}, nil
 	}
 
-	pageSize := opts.Limit
-	if pageSize <= 0 {
-		pageSize = defaultListPageSize
+	var envelope struct {
+		Data       json.RawMessage `json:"data"`
+		NextCursor string          `json:"nextCursor"`
+	}
+	if err := json.Unmarshal(trimmed, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if envelope.Data == nil {
+		return nil, fmt.Errorf("failed to parse response: expected a list or an object with a data field")
 	}
 
-	var all []Workflow
-	pageOpts := opts
-	pageOpts.Limit = pageSize
+	result := &ListResult[T]{NextCursor: envelope.NextCursor}
+	if err := json.Unmarshal(envelope.Data, &result.Data); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return result, nil
+}
 
+// paginate collects items from consecutive pages until limit items were
+// gathered (0 = no limit) or the server reports no further pages. Page
+// sizes never exceed the remaining limit, so when paginate stops because
+// of the limit, the returned NextCursor continues exactly after the last
+// returned item. A non-empty cursor fetches only that single page, of
+// limit items if that is a valid page size.
+func paginate[T any](limit int, cursor string, fetch func(pageSize int, cursor string) (*ListResult[T], error)) (*ListResult[T], error) {
+	// Manual pagination: caller provided a cursor, return single page
+	if cursor != "" {
+		pageSize := limit
+		if pageSize <= 0 || pageSize > defaultListPageSize {
+			pageSize = defaultListPageSize
+		}
+		return fetch(pageSize, cursor)
+	}
+
+	all := []T{}
 	for {
-		page, err := c.listWorkflowsPage(pageOpts)
+		pageSize := defaultListPageSize
+		if limit > 0 && limit-len(all) < pageSize {
+			pageSize = limit - len(all)
+		}
+
+		page, err := fetch(pageSize, cursor)
 		if err != nil {
 			return nil, err
 		}
 		all = append(all, page.Data...)
 
 		if page.NextCursor == "" || len(page.Data) == 0 {
-			break
+			return &ListResult[T]{Data: all}, nil
+		}
+		if limit > 0 && len(all) >= limit {
+			return &ListResult[T]{Data: all, NextCursor: page.NextCursor}, nil
 		}
-		pageOpts.Cursor = page.NextCursor
+		cursor = page.NextCursor
 	}
-
-	return &ListResult[Workflow]{Data: all}, nil
 }
 
 // GetWorkflow returns a workflow by ID
 func (c *Client) GetWorkflow(id string) (*Workflow, error) {
-	respBody, err := c.request(http.MethodGet, "/workflows/"+url.PathEscape(id), nil)
+	respBody, err := c.GetWorkflowRaw(id)
 	if err != nil {
 		return nil, err
 	}
 
 	var wf Workflow
-	if err := json.Unmarshal(respBody, &wf); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &wf); err != nil {
+		return nil, err
 	}
 
 	return &wf, nil
 }
 
+// GetWorkflowRaw returns a workflow exactly as the server sent it,
+// including fields Workflow doesn't model
+func (c *Client) GetWorkflowRaw(id string) ([]byte, error) {
+	return c.request(http.MethodGet, "/workflows/"+url.PathEscape(id), nil)
+}
+
 // CreateWorkflow creates a new workflow
 func (c *Client) CreateWorkflow(wf *Workflow) (*Workflow, error) {
 	// Only send fields that the API accepts (id, active, tags are read-only)
@@ -281,14 +680,16 @@ func (c *Client) CreateWorkflow(wf *Workflow) (*Workflow, error) {
 	}
 
 	var created Workflow
-	if err := json.Unmarshal(respBody, &created); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &created); err != nil {
+		return nil, err
 	}
 
 	return &created, nil
 }
 
-// WorkflowUpdateRequest contains only the fields allowed in update requests
+// WorkflowUpdateRequest contains only the fields allowed in update requests.
+// The public API's workflow schema has no pinData and rejects unknown
+// fields, so pinned data is never uploaded and the server keeps its own.
 type WorkflowUpdateRequest struct {
 	Name        string                   `json:"name"`
 	Nodes       []map[string]interface{} `json:"nodes"`
@@ -314,8 +715,8 @@ func (c *Client) UpdateWorkflow(id string, wf *Workflow) (*Workflow, error) {
 	}
 
 	var updated Workflow
-	if err := json.Unmarshal(respBody, &updated); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &updated); err != nil {
+		return nil, err
 	}
 
 	return &updated, nil
@@ -339,6 +740,31 @@ func (c *Client) DeactivateWorkflow(id string) error {
 	return err
 }
 
+// ArchiveWorkflow archives a workflow and returns its new state.
+// Instances without archiving support answer with 404 or 405.
+func (c *Client) ArchiveWorkflow(id string) (*Workflow, error) {
+	return c.setWorkflowArchived(id, "archive")
+}
+
+// UnarchiveWorkflow restores an archived workflow and returns its new state
+func (c *Client) UnarchiveWorkflow(id string) (*Workflow, error) {
+	return c.setWorkflowArchived(id, "unarchive")
+}
+
+func (c *Client) setWorkflowArchived(id, action string) (*Workflow, error) {
+	respBody, err := c.request(http.MethodPost, "/workflows/"+url.PathEscape(id)+"/"+action, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var wf Workflow
+	if err := decodeResponse(respBody, &wf); err != nil {
+		return nil, err
+	}
+
+	return &wf, nil
+}
+
 // GetWorkflowTags returns tags for a workflow
 func (c *Client) GetWorkflowTags(id string) ([]Tag, error) {
 	respBody, err := c.request(http.MethodGet, "/workflows/"+url.PathEscape(id)+"/tags", nil)
@@ -346,12 +772,12 @@ func (c *Client) GetWorkflowTags(id string) ([]Tag, error) {
 		return nil, err
 	}
 
-	var tags []Tag
-	if err := json.Unmarshal(respBody, &tags); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	tags, err := parseList[Tag](respBody)
+	if err != nil {
+		return nil, err
 	}
 
-	return tags, nil
+	return tags.Data, nil
 }
 
 // UpdateWorkflowTags updates tags for a workflow
@@ -365,12 +791,12 @@ func (c *Client) UpdateWorkflowTags(id string, tagIDs []string) ([]Tag, error) {
 		return nil, err
 	}
 
-	var tags []Tag
-	if err := json.Unmarshal(respBody, &tags); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	tags, err := parseList[Tag](respBody)
+	if err != nil {
+		return nil, err
 	}
 
-	return tags, nil
+	return tags.Data, nil
 }
 
 // TransferWorkflow transfers a workflow to another project
@@ -382,8 +808,14 @@ func (c *Client) TransferWorkflow(id, destinationProjectID string) error {
 	return err
 }
 
-// ListProjects returns all projects
+// ListProjects returns up to limit projects (0 = all), auto-paginating.
+// If cursor is set, only that single page is returned.
 func (c *Client) ListProjects(limit int, cursor string) (*ListResult[Project], error) {
+	return paginate(limit, cursor, c.listProjectsPage)
+}
+
+// listProjectsPage fetches a single page of projects.
+func (c *Client) listProjectsPage(limit int, cursor string) (*ListResult[Project], error) {
 	params := url.Values{}
 	if limit > 0 {
 		params.Set("limit", strconv.Itoa(limit))
@@ -402,41 +834,59 @@ func (c *Client) ListProjects(limit int, cursor string) (*ListResult[Project], e
 		return nil, err
 	}
 
-	var resp ListResult[Project]
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	return &resp, nil
+	return parseList[Project](respBody)
 }
 
 // ExecuteWorkflow executes a workflow (requires n8n 1.x with execute endpoint)
 func (c *Client) ExecuteWorkflow(id string, data map[string]interface{}, wait bool) (*Execution, error) {
+	respBody, err := c.requestWithTimeout(http.MethodPost, executePath(id, wait), ExecuteRequestBody(data), c.runTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var exec Execution
+	if err := decodeResponse(respBody, &exec); err != nil {
+		return nil, err
+	}
+
+	return &exec, nil
+}
+
+// ExecuteRequestBody returns the request body ExecuteWorkflow sends
+func ExecuteRequestBody(data map[string]interface{}) map[string]interface{} {
 	body := map[string]interface{}{}
 	if data != nil {
 		body["data"] = data
 	}
+	return body
+}
 
+// ExecuteURL returns the URL ExecuteWorkflow posts to
+func (c *Client) ExecuteURL(id string, wait bool) string {
+	return c.baseURL + c.pathPrefix + executePath(id, wait)
+}
+
+func executePath(id string, wait bool) string {
 	path := "/workflows/" + url.PathEscape(id) + "/execute"
 	if wait {
 		path += "?wait=true"
 	}
-
-	respBody, err := c.request(http.MethodPost, path, body)
-	if err != nil {
-		return nil, err
-	}
-
-	var exec Execution
-	if err := json.Unmarshal(respBody, &exec); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	return &exec, nil
+	return path
 }
 
-// ListExecutions returns workflow executions
+// ListExecutions returns up to opts.Limit executions (0 = all),
+// auto-paginating. If opts.Cursor is set, only that single page is returned.
 func (c *Client) ListExecutions(opts ListExecutionsOptions) (*ListResult[Execution], error) {
+	return paginate(opts.Limit, opts.Cursor, func(pageSize int, cursor string) (*ListResult[Execution], error) {
+		pageOpts := opts
+		pageOpts.Limit = pageSize
+		pageOpts.Cursor = cursor
+		return c.listExecutionsPage(pageOpts)
+	})
+}
+
+// listExecutionsPage fetches a single page of executions.
+func (c *Client) listExecutionsPage(opts ListExecutionsOptions) (*ListResult[Execution], error) {
 	params := url.Values{}
 	if opts.Limit > 0 {
 		params.Set("limit", strconv.Itoa(opts.Limit))
@@ -467,12 +917,7 @@ func (c *Client) ListExecutions(opts ListExecutionsOptions) (*ListResult[Executi
 		return nil, err
 	}
 
-	var resp ListResult[Execution]
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	return &resp, nil
+	return parseList[Execution](respBody)
 }
 
 // GetExecution returns an execution by ID
@@ -487,13 +932,21 @@ func (c *Client) GetExecution(id string, includeData bool) (*Execution, error) {
 	}
 
 	var exec Execution
-	if err := json.Unmarshal(respBody, &exec); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &exec); err != nil {
+		return nil, err
 	}
 
 	return &exec, nil
 }
 
+// OpenExecution requests an execution with its data and returns the
+// response body unread, for DecodeExecution. Data-heavy executions can be
+// hundreds of megabytes, which GetExecution would hold in memory several
+// times over. The run timeout applies. The caller must close the body.
+func (c *Client) OpenExecution(id string) (io.ReadCloser, error) {
+	return c.requestStream(http.MethodGet, "/executions/"+url.PathEscape(id)+"?includeData=true")
+}
+
 // RetryExecution retries a failed execution
 func (c *Client) RetryExecution(id string, loadWorkflow bool) (*Execution, error) {
 	var body interface{}
@@ -507,8 +960,8 @@ func (c *Client) RetryExecution(id string, loadWorkflow bool) (*Execution, error
 	}
 
 	var exec Execution
-	if err := json.Unmarshal(respBody, &exec); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &exec); err != nil {
+		return nil, err
 	}
 
 	return &exec, nil
@@ -522,8 +975,18 @@ func (c *Client) DeleteExecution(id string) error {
 
 // --- Tags ---
 
-// ListTags returns all tags
+// ListTags returns up to limit tags (0 = all), auto-paginating.
+// If cursor is set, only that single page is returned.
 func (c *Client) ListTags(limit int, cursor string) ([]Tag, error) {
+	result, err := paginate(limit, cursor, c.listTagsPage)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// listTagsPage fetches a single page of tags.
+func (c *Client) listTagsPage(limit int, cursor string) (*ListResult[Tag], error) {
 	params := url.Values{}
 	if limit > 0 {
 		params.Set("limit", strconv.Itoa(limit))
@@ -542,14 +1005,7 @@ func (c *Client) ListTags(limit int, cursor string) ([]Tag, error) {
 		return nil, err
 	}
 
-	var resp struct {
-		Data []Tag `json:"data"`
-	}
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	return resp.Data, nil
+	return parseList[Tag](respBody)
 }
 
 // GetTag returns a tag by ID
@@ -560,8 +1016,8 @@ func (c *Client) GetTag(id string) (*Tag, error) {
 	}
 
 	var tag Tag
-	if err := json.Unmarshal(respBody, &tag); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &tag); err != nil {
+		return nil, err
 	}
 
 	return &tag, nil
@@ -576,13 +1032,33 @@ func (c *Client) CreateTag(name string) (*Tag, error) {
 	}
 
 	var tag Tag
-	if err := json.Unmarshal(respBody, &tag); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &tag); err != nil {
+		return nil, err
 	}
 
 	return &tag, nil
 }
 
+// EnsureTag returns the tag with the given name, creating it if it
+// doesn't exist yet. The boolean reports whether the tag was created.
+func (c *Client) EnsureTag(name string) (*Tag, bool, error) {
+	tags, err := c.ListTags(0, "")
+	if err != nil {
+		return nil, false, err
+	}
+	for _, tag := range tags {
+		if tag.Name == name {
+			return &tag, false, nil
+		}
+	}
+
+	tag, err := c.CreateTag(name)
+	if err != nil {
+		return nil, false, err
+	}
+	return tag, true, nil
+}
+
 // UpdateTag updates a tag
 func (c *Client) UpdateTag(id, name string) (*Tag, error) {
 	body := map[string]string{"name": name}
@@ -592,8 +1068,8 @@ func (c *Client) UpdateTag(id, name string) (*Tag, error) {
 	}
 
 	var tag Tag
-	if err := json.Unmarshal(respBody, &tag); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &tag); err != nil {
+		return nil, err
 	}
 
 	return &tag, nil
@@ -607,6 +1083,53 @@ func (c *Client) DeleteTag(id string) error {
 
 // --- Credentials ---
 
+// ListCredentials returns up to limit credentials (0 = all), auto-paginating.
+// If cursor is set, only that single page is returned. Many n8n versions
+// don't expose credential listing in the public API; they answer with a
+// 404 or 405 APIError.
+func (c *Client) ListCredentials(limit int, cursor string) (*ListResult[Credential], error) {
+	return paginate(limit, cursor, c.listCredentialsPage)
+}
+
+// listCredentialsPage fetches a single page of credentials.
+func (c *Client) listCredentialsPage(limit int, cursor string) (*ListResult[Credential], error) {
+	params := url.Values{}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+
+	path := "/credentials"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	respBody, err := c.request(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseList[Credential](respBody)
+}
+
+// GetCredential returns a credential by ID. Like ListCredentials, it fails
+// with a 404 or 405 APIError on instances without the endpoint.
+func (c *Client) GetCredential(id string) (*Credential, error) {
+	respBody, err := c.request(http.MethodGet, "/credentials/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var cred Credential
+	if err := decodeResponse(respBody, &cred); err != nil {
+		return nil, err
+	}
+
+	return &cred, nil
+}
+
 // CreateCredential creates a new credential
 func (c *Client) CreateCredential(cred *Credential) (*Credential, error) {
 	respBody, err := c.request(http.MethodPost, "/credentials", cred)
@@ -615,8 +1138,8 @@ func (c *Client) CreateCredential(cred *Credential) (*Credential, error) {
 	}
 
 	var created Credential
-	if err := json.Unmarshal(respBody, &created); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &created); err != nil {
+		return nil, err
 	}
 
 	return &created, nil
@@ -636,8 +1159,8 @@ func (c *Client) GetCredentialSchema(typeName string) (map[string]interface{}, e
 	}
 
 	var schema map[string]interface{}
-	if err := json.Unmarshal(respBody, &schema); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := decodeResponse(respBody, &schema); err != nil {
+		return nil, err
 	}
 
 	return schema, nil
@@ -654,25 +1177,35 @@ func (c *Client) TransferCredential(id, destinationProjectID string) error {
 
 // TriggerWebhook triggers a workflow via its webhook URL.
 // The path is the webhook path (e.g. a UUID), and method is the HTTP method (GET, POST, etc.).
-// Webhooks are public endpoints, so no API key is sent.
+// Webhooks are public endpoints, so no API key is sent. Extra headers are
+// included, since a proxy in front of n8n typically guards webhooks too.
 func (c *Client) TriggerWebhook(path, method string) ([]byte, error) {
-	reqURL := c.baseURL + "/webhook/" + path
-	req, err := http.NewRequest(method, reqURL, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), c.runTimeout)
+	defer cancel()
+
+	webhookURL, err := c.WebhookURL(path)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, webhookURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, requestError("request failed", err, c.runTimeout)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, requestError("failed to read response", err, c.runTimeout)
 	}
 
 	if resp.StatusCode >= 400 {
@@ -682,10 +1215,50 @@ func (c *Client) TriggerWebhook(path, method string) ([]byte, error) {
 	return respBody, nil
 }
 
+// BaseURL returns the instance URL the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// ErrNoWebhookURL is returned for webhooks of clients with SetRawPath,
+// whose URL points at an API gateway rather than at n8n itself
+var ErrNoWebhookURL = errors.New("webhook URLs are unknown with the raw path: the instance URL points at an API gateway, not at n8n")
+
+// WebhookBaseURL returns the URL production webhooks are served under,
+// the instance URL. It fails in raw path mode, see ErrNoWebhookURL.
+func (c *Client) WebhookBaseURL() (string, error) {
+	if c.pathPrefix == "" {
+		return "", ErrNoWebhookURL
+	}
+	return c.baseURL, nil
+}
+
+// WebhookURL returns the production URL TriggerWebhook calls for path
+func (c *Client) WebhookURL(path string) (string, error) {
+	base, err := c.WebhookBaseURL()
+	if err != nil {
+		return "", err
+	}
+	return base + "/webhook/" + path, nil
+}
+
 // --- Variables ---
 
-// ListVariables returns all variables, optionally filtered by project.
+// ListVariables returns up to limit variables (0 = all), optionally
+// filtered by project, auto-paginating. If cursor is set, only that single
+// page is returned.
 func (c *Client) ListVariables(limit int, cursor string, projectID string) ([]Variable, error) {
+	result, err := paginate(limit, cursor, func(pageSize int, cursor string) (*ListResult[Variable], error) {
+		return c.listVariablesPage(pageSize, cursor, projectID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// listVariablesPage fetches a single page of variables.
+func (c *Client) listVariablesPage(limit int, cursor string, projectID string) (*ListResult[Variable], error) {
 	params := url.Values{}
 	if limit > 0 {
 		params.Set("limit", strconv.Itoa(limit))
@@ -707,14 +1280,7 @@ func (c *Client) ListVariables(limit int, cursor string, projectID string) ([]Va
 		return nil, err
 	}
 
-	var resp struct {
-		Data []Variable `json:"data"`
-	}
-	if err := json.Unmarshal(respBody, &resp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-
-	return resp.Data, nil
+	return parseList[Variable](respBody)
 }
 
 // CreateVariable creates a new variable, optionally scoped to a project.
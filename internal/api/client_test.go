@@ -0,0 +1,597 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+)
+
+func newTestClient(t *testing.T) (*Client, *apitest.Server) {
+	t.Helper()
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, "test-key"), srv
+}
+
+// clientCall is one client method under test, with the route it must use
+type clientCall struct {
+	name   string
+	method string
+	path   string
+	// body is the expected JSON request body ("" = none)
+	body string
+	call func(c *Client) error
+	// listed marks calls that parse a list, for the malformed JSON case
+	listed bool
+}
+
+var clientCalls = []clientCall{
+	{
+		name: "GetWorkflow", method: http.MethodGet, path: "/workflows/abc",
+		call: func(c *Client) error { _, err := c.GetWorkflow("abc"); return err },
+	},
+	{
+		name: "CreateWorkflow", method: http.MethodPost, path: "/workflows",
+		body: `{"name":"New","nodes":[],"connections":{}}`,
+		call: func(c *Client) error {
+			_, err := c.CreateWorkflow(&Workflow{ID: "ignored", Name: "New", Active: true, Nodes: []map[string]interface{}{}, Connections: map[string]interface{}{}})
+			return err
+		},
+	},
+	{
+		name: "UpdateWorkflow", method: http.MethodPut, path: "/workflows/abc",
+		body: `{"name":"Changed","nodes":[],"connections":{}}`,
+		call: func(c *Client) error {
+			_, err := c.UpdateWorkflow("abc", &Workflow{ID: "abc", Name: "Changed", Nodes: []map[string]interface{}{}, Connections: map[string]interface{}{}})
+			return err
+		},
+	},
+	{
+		name: "DeleteWorkflow", method: http.MethodDelete, path: "/workflows/abc",
+		call: func(c *Client) error { return c.DeleteWorkflow("abc") },
+	},
+	{
+		name: "ActivateWorkflow", method: http.MethodPost, path: "/workflows/abc/activate",
+		call: func(c *Client) error { return c.ActivateWorkflow("abc") },
+	},
+	{
+		name: "TransferWorkflow", method: http.MethodPut, path: "/workflows/abc/transfer",
+		body: `{"destinationProjectId":"p1"}`,
+		call: func(c *Client) error { return c.TransferWorkflow("abc", "p1") },
+	},
+	{
+		name: "UpdateWorkflowTags", method: http.MethodPut, path: "/workflows/abc/tags",
+		body: `{"tagIds":["t1"]}`, listed: true,
+		call: func(c *Client) error { _, err := c.UpdateWorkflowTags("abc", []string{"t1"}); return err },
+	},
+	{
+		name: "GetExecution", method: http.MethodGet, path: "/executions/42",
+		call: func(c *Client) error { _, err := c.GetExecution("42", true); return err },
+	},
+	{
+		name: "RetryExecution", method: http.MethodPost, path: "/executions/42/retry",
+		body: `{"loadWorkflow":true}`,
+		call: func(c *Client) error { _, err := c.RetryExecution("42", true); return err },
+	},
+	{
+		name: "CreateTag", method: http.MethodPost, path: "/tags",
+		body: `{"name":"Ops"}`,
+		call: func(c *Client) error { _, err := c.CreateTag("Ops"); return err },
+	},
+	{
+		name: "ListTags", method: http.MethodGet, path: "/tags", listed: true,
+		call: func(c *Client) error { _, err := c.ListTags(0, ""); return err },
+	},
+}
+
+func TestClientSuccess(t *testing.T) {
+	for _, tc := range clientCalls {
+		t.Run(tc.name, func(t *testing.T) {
+			client, srv := newTestClient(t)
+			var body interface{} = map[string]string{"id": "abc"}
+			if tc.listed {
+				body = map[string]interface{}{"data": []interface{}{}}
+			}
+			srv.Handle(tc.method, tc.path, apitest.Response{Body: body})
+
+			if err := tc.call(client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			req, ok := srv.LastRequest()
+			if !ok {
+				t.Fatal("no request received")
+			}
+			if req.Method != tc.method || req.Path != tc.path {
+				t.Errorf("request = %s %s, want %s %s", req.Method, req.Path, tc.method, tc.path)
+			}
+			if got := req.Header.Get("X-N8N-API-KEY"); got != "test-key" {
+				t.Errorf("X-N8N-API-KEY = %q, want test-key", got)
+			}
+			assertJSONBody(t, req.Body, tc.body)
+		})
+	}
+}
+
+func TestClientErrorStatus(t *testing.T) {
+	statuses := []struct {
+		status  int
+		body    interface{}
+		message string
+	}{
+		{http.StatusBadRequest, map[string]string{"message": "request/body must have required property 'name'"}, "request/body must have required property 'name'"},
+		{http.StatusNotFound, map[string]string{"message": "Not Found"}, "Not Found"},
+		{http.StatusInternalServerError, "upstream crashed", "upstream crashed"},
+		{http.StatusBadGateway, "", "bad gateway"},
+	}
+
+	for _, tc := range clientCalls {
+		for _, st := range statuses {
+			t.Run(tc.name+"/"+http.StatusText(st.status), func(t *testing.T) {
+				client, srv := newTestClient(t)
+				srv.Handle(tc.method, tc.path, apitest.Response{Status: st.status, Body: st.body})
+
+				err := tc.call(client)
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("error = %v, want *APIError", err)
+				}
+				if apiErr.StatusCode != st.status {
+					t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, st.status)
+				}
+				if apiErr.Message != st.message {
+					t.Errorf("Message = %q, want %q", apiErr.Message, st.message)
+				}
+				if apiErr.Method != tc.method || !strings.Contains(apiErr.URL, "/api/v1"+tc.path) {
+					t.Errorf("error names %s %s, want %s %s", apiErr.Method, apiErr.URL, tc.method, tc.path)
+				}
+				if strings.Contains(err.Error(), "test-key") {
+					t.Errorf("error %q contains the API key", err)
+				}
+			})
+		}
+	}
+}
+
+func TestClientMalformedJSON(t *testing.T) {
+	for _, tc := range clientCalls {
+		t.Run(tc.name, func(t *testing.T) {
+			client, srv := newTestClient(t)
+			srv.Handle(tc.method, tc.path, apitest.Response{Body: `{"id": "abc",`})
+
+			err := tc.call(client)
+			// Calls that ignore the response body can't notice
+			ignoresBody := tc.method == http.MethodDelete || tc.name == "ActivateWorkflow" || tc.name == "TransferWorkflow"
+			if ignoresBody {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), "failed to parse response") {
+				t.Fatalf("error = %v, want a parse error", err)
+			}
+		})
+	}
+}
+
+func TestClientEmptyResponse(t *testing.T) {
+	for _, tc := range clientCalls {
+		t.Run(tc.name, func(t *testing.T) {
+			client, srv := newTestClient(t)
+			srv.Handle(tc.method, tc.path, apitest.Response{Status: http.StatusNoContent})
+
+			if err := tc.call(client); err != nil {
+				t.Fatalf("unexpected error for an empty 204 response: %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantID  string
+		wantErr bool
+	}{
+		{name: "empty", body: ""},
+		{name: "whitespace", body: " \n\t"},
+		{name: "object", body: `{"id":"abc"}`, wantID: "abc"},
+		{name: "truncated", body: `{"id":`, wantErr: true},
+		{name: "not json", body: "<html>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var wf Workflow
+			err := decodeResponse([]byte(tt.body), &wf)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "failed to parse response") {
+					t.Fatalf("error = %v, want a parse error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if wf.ID != tt.wantID {
+				t.Errorf("ID = %q, want %q", wf.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantNames  string
+		wantCursor string
+		wantErr    bool
+	}{
+		{name: "bare array", body: `[{"name":"a"},{"name":"b"}]`, wantNames: "a,b"},
+		{name: "envelope", body: `{"data":[{"name":"a"}],"nextCursor":"next"}`, wantNames: "a", wantCursor: "next"},
+		{name: "envelope without cursor", body: `{"data":[{"name":"a"},{"name":"b"}]}`, wantNames: "a,b"},
+		{name: "empty envelope", body: `{"data":[]}`},
+		{name: "empty array", body: " []\n"},
+		{name: "empty body", body: ""},
+		{name: "object without data", body: `{"items":[]}`, wantErr: true},
+		{name: "data not a list", body: `{"data":{"name":"a"}}`, wantErr: true},
+		{name: "malformed array", body: `[{"name":`, wantErr: true},
+		{name: "scalar", body: `42`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseList[Tag]([]byte(tt.body))
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "failed to parse response") {
+					t.Fatalf("error = %v, want a parse error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			names := make([]string, len(result.Data))
+			for i, tag := range result.Data {
+				names[i] = tag.Name
+			}
+			if got := strings.Join(names, ","); got != tt.wantNames {
+				t.Errorf("names = %q, want %q", got, tt.wantNames)
+			}
+			if result.NextCursor != tt.wantCursor {
+				t.Errorf("NextCursor = %q, want %q", result.NextCursor, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestClientAuthHeader(t *testing.T) {
+	tests := []struct {
+		mode    string
+		header  string
+		value   string
+		absent  string
+		wantErr bool
+	}{
+		{mode: "", header: "X-N8N-API-KEY", value: "test-key", absent: "Authorization"},
+		{mode: AuthModeAPIKey, header: "X-N8N-API-KEY", value: "test-key", absent: "Authorization"},
+		{mode: AuthModeBearer, header: "Authorization", value: "Bearer test-key", absent: "X-N8N-API-KEY"},
+		{mode: "basic", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run("mode="+tt.mode, func(t *testing.T) {
+			client, srv := newTestClient(t)
+			err := client.SetAuthMode(tt.mode)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error for an unknown mode")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetAuthMode: %v", err)
+			}
+			srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+			if _, err := client.GetWorkflow("abc"); err != nil {
+				t.Fatalf("GetWorkflow: %v", err)
+			}
+
+			req, _ := srv.LastRequest()
+			if got := req.Header.Get(tt.header); got != tt.value {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.value)
+			}
+			if got := req.Header.Get(tt.absent); got != "" {
+				t.Errorf("%s = %q, want it unset", tt.absent, got)
+			}
+		})
+	}
+}
+
+func TestClientExtraHeaders(t *testing.T) {
+	client, srv := newTestClient(t)
+	if err := client.SetHeader("CF-Access-Client-Id", "abc"); err != nil {
+		t.Fatalf("SetHeader: %v", err)
+	}
+	if err := client.SetHeader("X-N8N-API-KEY", "other"); err == nil {
+		t.Error("SetHeader accepted the API key header")
+	}
+	srv.Handle(http.MethodDelete, "/workflows/abc", apitest.Response{})
+	if err := client.DeleteWorkflow("abc"); err != nil {
+		t.Fatalf("DeleteWorkflow: %v", err)
+	}
+
+	req, _ := srv.LastRequest()
+	if got := req.Header.Get("CF-Access-Client-Id"); got != "abc" {
+		t.Errorf("CF-Access-Client-Id = %q, want abc", got)
+	}
+	if got := req.Header.Get("X-N8N-API-KEY"); got != "test-key" {
+		t.Errorf("X-N8N-API-KEY = %q, want test-key", got)
+	}
+}
+
+func TestClientPagination(t *testing.T) {
+	// The fake server answers by path alone, so page through five
+	// workflows with a handler honouring limit and using offsets as cursors
+	var limits []string
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const total = 5
+		q := r.URL.Query()
+		limits = append(limits, q.Get("limit"))
+		offset, _ := strconv.Atoi(q.Get("cursor"))
+		size, _ := strconv.Atoi(q.Get("limit"))
+		end := min(offset+size, total)
+		page := map[string]interface{}{}
+		data := []map[string]string{}
+		for i := offset; i < end; i++ {
+			data = append(data, map[string]string{"id": strconv.Itoa(i + 1)})
+		}
+		page["data"] = data
+		if end < total {
+			page["nextCursor"] = strconv.Itoa(end)
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	})
+	client := NewClient(srv.URL, "test-key")
+
+	tests := []struct {
+		name       string
+		opts       ListWorkflowsOptions
+		wantIDs    string
+		wantCursor string
+		wantLimits string
+	}{
+		{name: "all pages", wantIDs: "1,2,3,4,5", wantLimits: "250"},
+		{name: "limit within first page", opts: ListWorkflowsOptions{Limit: 2}, wantIDs: "1,2", wantCursor: "2", wantLimits: "2"},
+		{name: "limit beyond end", opts: ListWorkflowsOptions{Limit: 10}, wantIDs: "1,2,3,4,5", wantLimits: "10"},
+		{name: "single page from cursor", opts: ListWorkflowsOptions{Limit: 2, Cursor: "2"}, wantIDs: "3,4", wantCursor: "4", wantLimits: "2"},
+		{name: "cursor without limit", opts: ListWorkflowsOptions{Cursor: "3"}, wantIDs: "4,5", wantLimits: "250"},
+		{name: "cursor with limit above page size", opts: ListWorkflowsOptions{Limit: 1000, Cursor: "3"}, wantIDs: "4,5", wantLimits: "250"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits = nil
+			result, err := client.ListWorkflows(tt.opts)
+			if err != nil {
+				t.Fatalf("ListWorkflows: %v", err)
+			}
+			ids := make([]string, len(result.Data))
+			for i, wf := range result.Data {
+				ids[i] = wf.ID
+			}
+			if got := strings.Join(ids, ","); got != tt.wantIDs {
+				t.Errorf("IDs = %s, want %s", got, tt.wantIDs)
+			}
+			if result.NextCursor != tt.wantCursor {
+				t.Errorf("NextCursor = %q, want %q", result.NextCursor, tt.wantCursor)
+			}
+			if got := strings.Join(limits, ","); got != tt.wantLimits {
+				t.Errorf("page sizes requested = %s, want %s", got, tt.wantLimits)
+			}
+		})
+	}
+}
+
+func TestClientPaginationAcrossPages(t *testing.T) {
+	// A server capping pages below the requested size makes the client
+	// follow nextCursor until the limit is reached
+	pages := map[string]string{
+		"":   `{"data":[{"id":"1"},{"id":"2"}],"nextCursor":"c2"}`,
+		"c2": `{"data":[{"id":"3"},{"id":"4"}],"nextCursor":"c3"}`,
+		"c3": `{"data":[{"id":"5"}]}`,
+	}
+	var limits []string
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limits = append(limits, r.URL.Query().Get("limit"))
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("cursor")]))
+	})
+	client := NewClient(srv.URL, "test-key")
+
+	result, err := client.ListWorkflows(ListWorkflowsOptions{Limit: 3})
+	if err != nil {
+		t.Fatalf("ListWorkflows: %v", err)
+	}
+	if len(result.Data) != 4 || result.NextCursor != "c3" {
+		t.Errorf("got %d workflows and cursor %q, want 4 and c3", len(result.Data), result.NextCursor)
+	}
+	if got := strings.Join(limits, ","); got != "3,1" {
+		t.Errorf("page sizes requested = %s, want 3,1", got)
+	}
+}
+
+func TestListWorkflowsQuery(t *testing.T) {
+	client, srv := newTestClient(t)
+	srv.Handle(http.MethodGet, "/workflows", apitest.Response{Body: map[string]interface{}{"data": []interface{}{}}})
+
+	active := true
+	_, err := client.ListWorkflows(ListWorkflowsOptions{Active: &active, Name: "Order sync", Tags: []string{"a", "b"}, ProjectID: "p1"})
+	if err != nil {
+		t.Fatalf("ListWorkflows: %v", err)
+	}
+
+	req, _ := srv.LastRequest()
+	for _, want := range []string{"active=true", "name=Order+sync", "tags=a", "tags=b", "projectId=p1", "limit=250"} {
+		if !strings.Contains(req.Query, want) {
+			t.Errorf("query %q lacks %s", req.Query, want)
+		}
+	}
+}
+
+// assertJSONBody compares a request body with the expected JSON,
+// ignoring formatting
+func assertJSONBody(t *testing.T, body []byte, want string) {
+	t.Helper()
+	if want == "" {
+		if len(body) != 0 {
+			t.Errorf("body = %s, want none", body)
+		}
+		return
+	}
+	var got, expected interface{}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("body %q is not JSON: %v", body, err)
+	}
+	if err := json.Unmarshal([]byte(want), &expected); err != nil {
+		t.Fatalf("bad expectation %q: %v", want, err)
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(expected)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("body = %s, want %s", gotJSON, wantJSON)
+	}
+}
+
+func TestWorkflowRequestPinData(t *testing.T) {
+	tests := []struct {
+		name    string
+		pinData map[string]interface{}
+		want    string
+	}{
+		{name: "no pinned data", pinData: nil, want: `{"name":"WF","nodes":[],"connections":{}}`},
+		{name: "empty pinned data is not uploaded", pinData: map[string]interface{}{}, want: `{"name":"WF","nodes":[],"connections":{}}`},
+		{name: "pinned data is not uploaded", pinData: map[string]interface{}{"Webhook": []interface{}{map[string]interface{}{"json": map[string]interface{}{}}}}, want: `{"name":"WF","nodes":[],"connections":{}}`},
+	}
+
+	for _, tt := range tests {
+		for _, create := range []bool{true, false} {
+			name := tt.name + "/update"
+			if create {
+				name = tt.name + "/create"
+			}
+			t.Run(name, func(t *testing.T) {
+				client, srv := newTestClient(t)
+				srv.Handle(http.MethodPost, "/workflows", apitest.Response{Body: map[string]string{"id": "abc"}})
+				srv.Handle(http.MethodPut, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+
+				wf := &Workflow{ID: "abc", Name: "WF", Nodes: []map[string]interface{}{}, Connections: map[string]interface{}{}, PinData: tt.pinData}
+				var err error
+				if create {
+					_, err = client.CreateWorkflow(wf)
+				} else {
+					_, err = client.UpdateWorkflow("abc", wf)
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				req, _ := srv.LastRequest()
+				assertJSONBody(t, req.Body, tt.want)
+			})
+		}
+	}
+}
+
+func TestClientPathModes(t *testing.T) {
+	tests := []struct {
+		name      string
+		serverPfx string
+		urlSuffix string
+		raw       bool
+		wantPath  string
+	}{
+		{name: "default", serverPfx: "/api/v1", wantPath: "/api/v1/workflows/abc"},
+		{name: "default strips /api/v1 from the URL", serverPfx: "/api/v1", urlSuffix: "/api/v1/", wantPath: "/api/v1/workflows/abc"},
+		{name: "raw", serverPfx: "/gw", urlSuffix: "/gw", raw: true, wantPath: "/gw/workflows/abc"},
+		{name: "raw keeps /api/v1 in the URL", serverPfx: "/api/v1", urlSuffix: "/api/v1", raw: true, wantPath: "/api/v1/workflows/abc"},
+		{name: "raw without a path", serverPfx: "", raw: true, wantPath: "/workflows/abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			srv.SetPrefix(tt.serverPfx)
+			srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+
+			// Record the full path, which the fake server trims
+			var gotPath string
+			client := NewClient(srv.URL+tt.urlSuffix, "test-key")
+			client.SetRawPath(tt.raw)
+			client.httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				gotPath = req.URL.Path
+				return http.DefaultTransport.RoundTrip(req)
+			})
+
+			if _, err := client.GetWorkflow("abc"); err != nil {
+				t.Fatalf("GetWorkflow: %v", err)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("path = %s, want %s", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestClientWebhookURL(t *testing.T) {
+	client := NewClient("https://n8n.example.com/api/v1", "key")
+	got, err := client.WebhookURL("order")
+	if err != nil || got != "https://n8n.example.com/webhook/order" {
+		t.Errorf("WebhookURL() = %q, %v; want the instance's webhook URL", got, err)
+	}
+
+	// A gateway URL says nothing about where webhooks are served
+	client.SetRawPath(true)
+	if got, err := client.WebhookURL("order"); !errors.Is(err, ErrNoWebhookURL) {
+		t.Errorf("raw WebhookURL() = %q, %v; want ErrNoWebhookURL", got, err)
+	}
+	if _, err := client.TriggerWebhook("order", http.MethodPost); !errors.Is(err, ErrNoWebhookURL) {
+		t.Errorf("raw TriggerWebhook() error = %v, want ErrNoWebhookURL", err)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestNewClientTransport(t *testing.T) {
+	defaults := NewClient("http://n8n.test", "key").httpClient.Transport.(*http.Transport)
+	if defaults == http.DefaultTransport {
+		t.Fatal("client uses http.DefaultTransport, want a transport of its own")
+	}
+	if defaults.MaxIdleConnsPerHost != DefaultTransportOptions().MaxIdleConnsPerHost || defaults.DisableKeepAlives {
+		t.Errorf("default transport: MaxIdleConnsPerHost = %d, DisableKeepAlives = %v", defaults.MaxIdleConnsPerHost, defaults.DisableKeepAlives)
+	}
+
+	opts := DefaultTransportOptions()
+	opts.DisableKeepAlives = true
+	tuned := NewClient("http://n8n.test", "key", WithTransportOptions(opts)).httpClient.Transport.(*http.Transport)
+	if !tuned.DisableKeepAlives {
+		t.Error("DisableKeepAlives not applied")
+	}
+	if http.DefaultTransport.(*http.Transport).DisableKeepAlives {
+		t.Error("http.DefaultTransport was changed")
+	}
+}
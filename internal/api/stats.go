@@ -0,0 +1,127 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// endpointStats holds the tally for a single endpoint
+type endpointStats struct {
+	calls  int
+	errors int
+	total  time.Duration
+}
+
+// CallStats tallies HTTP requests by endpoint. Use Transport to instrument
+// an http.RoundTripper and Print to write the summary.
+type CallStats struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointStats
+}
+
+// NewCallStats creates an empty call tally
+func NewCallStats() *CallStats {
+	return &CallStats{endpoints: make(map[string]*endpointStats)}
+}
+
+// Transport wraps base so that every request passing through it is
+// recorded. A nil base uses http.DefaultTransport.
+func (s *CallStats) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &statsTransport{base: base, stats: s}
+}
+
+type statsTransport struct {
+	base  http.RoundTripper
+	stats *CallStats
+}
+
+func (t *statsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	failed := err != nil || resp.StatusCode >= 400
+	t.stats.record(req.Method+" "+endpointPath(req), time.Since(start), failed)
+	return resp, err
+}
+
+func (s *CallStats) record(endpoint string, d time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.endpoints[endpoint]
+	if !ok {
+		e = &endpointStats{}
+		s.endpoints[endpoint] = e
+	}
+	e.calls++
+	e.total += d
+	if failed {
+		e.errors++
+	}
+}
+
+// endpointPath collapses resource IDs in API paths so that requests to
+// the same endpoint are grouped, e.g. /api/v1/workflows/abc/activate
+// becomes /workflows/{id}/activate. Other paths (webhooks) are kept as is.
+func endpointPath(req *http.Request) string {
+	path, ok := endpointOf(req)
+	if !ok {
+		return req.URL.Path
+	}
+
+	// API paths alternate between collection names and IDs:
+	// /workflows/{id}/activate, /projects/{id}/users/{id}
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := 1; i < len(segments); i += 2 {
+		if segments[0] == "credentials" && segments[i] == "schema" {
+			continue
+		}
+		segments[i] = "{id}"
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// Print writes a table of calls per endpoint, slowest total first,
+// followed by the overall totals
+func (s *CallStats) Print(w io.Writer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.endpoints))
+	var calls int
+	var total time.Duration
+	for name, e := range s.endpoints {
+		names = append(names, name)
+		calls += e.calls
+		total += e.total
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := s.endpoints[names[i]], s.endpoints[names[j]]
+		if a.total != b.total {
+			return a.total > b.total
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Fprintf(w, "\n%-6s  %-6s  %-10s  %-10s  %s\n", "CALLS", "ERRORS", "TOTAL", "AVG", "ENDPOINT")
+	fmt.Fprintf(w, "%-6s  %-6s  %-10s  %-10s  %s\n",
+		strings.Repeat("-", 6),
+		strings.Repeat("-", 6),
+		strings.Repeat("-", 10),
+		strings.Repeat("-", 10),
+		strings.Repeat("-", 40))
+	for _, name := range names {
+		e := s.endpoints[name]
+		avg := e.total / time.Duration(e.calls)
+		fmt.Fprintf(w, "%-6d  %-6d  %-10s  %-10s  %s\n",
+			e.calls, e.errors, e.total.Round(time.Millisecond), avg.Round(time.Millisecond), name)
+	}
+	fmt.Fprintf(w, "%d API call(s), %s total\n", calls, total.Round(time.Millisecond))
+}
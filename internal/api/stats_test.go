@@ -0,0 +1,51 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+)
+
+func TestCallStatsEndpoints(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  bool
+		call func(c *Client) error
+		want string
+	}{
+		{name: "collection", call: func(c *Client) error { _, err := c.ListTags(0, ""); return err }, want: "GET /tags"},
+		{name: "resource", call: func(c *Client) error { _, err := c.GetWorkflow("abc"); return err }, want: "GET /workflows/{id}"},
+		{name: "action", call: func(c *Client) error { return c.ActivateWorkflow("abc") }, want: "POST /workflows/{id}/activate"},
+		{name: "raw path", raw: true, call: func(c *Client) error { _, err := c.GetWorkflow("abc"); return err }, want: "GET /workflows/{id}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			if tt.raw {
+				srv.SetPrefix("")
+			}
+			srv.Handle(http.MethodGet, "/tags", apitest.Response{Body: map[string]interface{}{"data": []interface{}{}}})
+			srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+			srv.Handle(http.MethodPost, "/workflows/abc/activate", apitest.Response{Body: map[string]string{"id": "abc"}})
+
+			stats := NewCallStats()
+			client := NewClient(srv.URL, "key")
+			client.SetRawPath(tt.raw)
+			client.httpClient.Transport = stats.Transport(nil)
+			if err := tt.call(client); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var out bytes.Buffer
+			stats.Print(&out)
+			if !strings.Contains(out.String(), "  "+tt.want+"\n") {
+				t.Errorf("stats lack %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DecodeExecution reads an execution response from r, as returned by
+// OpenExecution, without holding all of its run data in memory. The runs
+// of each node in data.resultData.runData are decoded on their own and
+// passed to handle, then dropped. Nodes for which want returns false are
+// skipped without decoding them; a nil want selects all nodes.
+//
+// The returned execution's Data holds the rest of the execution data,
+// except executionData, which n8n keeps to resume waiting executions and
+// which repeats the input of pending nodes.
+func DecodeExecution(r io.Reader, want func(node string) bool, handle func(node string, runs []interface{}) error) (*Execution, error) {
+	dec := json.NewDecoder(r)
+	s := &executionStream{dec: dec, want: want, handle: handle}
+	exec, err := s.decode()
+	if s.handleErr != nil {
+		return nil, s.handleErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return exec, nil
+}
+
+type executionStream struct {
+	dec    *json.Decoder
+	want   func(node string) bool
+	handle func(node string, runs []interface{}) error
+	// handleErr is the error handle failed with, which ends decoding but
+	// isn't a parse error
+	handleErr error
+}
+
+func (s *executionStream) decode() (*Execution, error) {
+	fields := make(map[string]json.RawMessage)
+	var data map[string]interface{}
+	err := s.object(func(key string) error {
+		if key == "data" {
+			var err error
+			data, err = s.executionData()
+			return err
+		}
+		var raw json.RawMessage
+		if err := s.dec.Decode(&raw); err != nil {
+			return err
+		}
+		fields[key] = raw
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The remaining fields are small, so decode them the usual way
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var exec Execution
+	if err := json.Unmarshal(encoded, &exec); err != nil {
+		return nil, err
+	}
+	exec.Data = data
+	return &exec, nil
+}
+
+// executionData decodes the data object of an execution. A null data
+// object yields nil.
+func (s *executionStream) executionData() (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	err := s.object(func(key string) error {
+		switch key {
+		case "resultData":
+			resultData, err := s.resultData()
+			if err != nil {
+				return err
+			}
+			data[key] = resultData
+			return nil
+		case "executionData":
+			return s.skip()
+		}
+		var v interface{}
+		if err := s.dec.Decode(&v); err != nil {
+			return err
+		}
+		data[key] = v
+		return nil
+	})
+	if err == errNull {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (s *executionStream) resultData() (map[string]interface{}, error) {
+	resultData := make(map[string]interface{})
+	err := s.object(func(key string) error {
+		if key == "runData" {
+			return s.runData()
+		}
+		var v interface{}
+		if err := s.dec.Decode(&v); err != nil {
+			return err
+		}
+		resultData[key] = v
+		return nil
+	})
+	if err == errNull {
+		return nil, nil
+	}
+	return resultData, err
+}
+
+func (s *executionStream) runData() error {
+	err := s.object(func(node string) error {
+		if s.want != nil && !s.want(node) {
+			return s.skip()
+		}
+		var runs []interface{}
+		if err := s.dec.Decode(&runs); err != nil {
+			return err
+		}
+		s.handleErr = s.handle(node, runs)
+		return s.handleErr
+	})
+	if err == errNull {
+		return nil
+	}
+	return err
+}
+
+// errNull is returned by object for a null value
+var errNull = errors.New("null")
+
+// object reads a JSON object and calls field for each key, with the
+// decoder positioned at the value, which field must consume. It returns
+// errNull if the value is null instead.
+func (s *executionStream) object(field func(key string) error) error {
+	tok, err := s.dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return errNull
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected an object, got %v", tok)
+	}
+	for s.dec.More() {
+		tok, err := s.dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if err := field(key); err != nil {
+			return err
+		}
+	}
+	_, err = s.dec.Token()
+	return err
+}
+
+// skip reads past the next value without keeping it
+func (s *executionStream) skip() error {
+	depth := 0
+	for {
+		tok, err := s.dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			if delim == '{' || delim == '[' {
+				depth++
+			} else {
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDecodeExecution(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      func(node string) bool
+		wantNodes []string
+		wantData  map[string]interface{}
+	}{
+		{
+			name:     "null data",
+			body:     `{"id":"1","status":"success","data":null}`,
+			wantData: nil,
+		},
+		{
+			name:      "all nodes",
+			body:      `{"id":"1","data":{"resultData":{"runData":{"A":[{"x":1}],"B":[{"x":2}]},"lastNodeExecuted":"B"}}}`,
+			wantNodes: []string{"A", "B"},
+			wantData:  map[string]interface{}{"resultData": map[string]interface{}{"lastNodeExecuted": "B"}},
+		},
+		{
+			name:      "skipped nodes",
+			body:      `{"id":"1","data":{"resultData":{"runData":{"A":[{"x":{"deep":[1,2]}}],"B":[{"x":2}]}}}}`,
+			want:      func(node string) bool { return node == "B" },
+			wantNodes: []string{"B"},
+			wantData:  map[string]interface{}{"resultData": map[string]interface{}{}},
+		},
+		{
+			name:     "executionData dropped",
+			body:     `{"id":"1","data":{"startData":{},"executionData":{"nodeExecutionStack":[{"a":1}]}}}`,
+			wantData: map[string]interface{}{"startData": map[string]interface{}{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []string
+			exec, err := DecodeExecution(strings.NewReader(tt.body), tt.want, func(node string, runs []interface{}) error {
+				if len(runs) != 1 {
+					t.Errorf("node %s: got %d runs, want 1", node, len(runs))
+				}
+				nodes = append(nodes, node)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("DecodeExecution() error = %v", err)
+			}
+			if exec.ID != "1" {
+				t.Errorf("ID = %q, want 1", exec.ID)
+			}
+			sort.Strings(nodes)
+			if !reflect.DeepEqual(nodes, tt.wantNodes) {
+				t.Errorf("handled nodes = %v, want %v", nodes, tt.wantNodes)
+			}
+			if !reflect.DeepEqual(exec.Data, tt.wantData) {
+				t.Errorf("Data = %#v, want %#v", exec.Data, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestDecodeExecutionHandlerError(t *testing.T) {
+	handlerErr := errors.New("disk full")
+	body := `{"id":"1","data":{"resultData":{"runData":{"A":[{}],"B":[{}]}}}}`
+
+	calls := 0
+	_, err := DecodeExecution(strings.NewReader(body), nil, func(string, []interface{}) error {
+		calls++
+		return handlerErr
+	})
+	if err != handlerErr {
+		t.Errorf("error = %v, want the handler's error unwrapped", err)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want decoding to stop after the first error", calls)
+	}
+}
+
+func TestDecodeExecutionMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"truncated in run data", `{"id":"1","data":{"resultData":{"runData":{"A":[{"x":`},
+		{"truncated in skipped node", `{"id":"1","data":{"resultData":{"runData":{"B":[{"x":`},
+		{"truncated after data", `{"id":"1","data":{}`},
+		{"not an object", `[1,2]`},
+		{"empty", ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := func(node string) bool { return node == "A" }
+			_, err := DecodeExecution(strings.NewReader(tt.body), want, func(string, []interface{}) error {
+				return nil
+			})
+			if err == nil {
+				t.Fatal("DecodeExecution() succeeded, want a parse error")
+			}
+			if !strings.Contains(err.Error(), "failed to parse response") {
+				t.Errorf("error = %q, want it to mention the parse failure", err)
+			}
+		})
+	}
+}
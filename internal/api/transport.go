@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// TransportOptions tunes how HTTP connections to the API are reused
+type TransportOptions struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// DisableKeepAlives opens a new connection for every request, for
+	// proxies that mishandle reused connections
+	DisableKeepAlives bool
+}
+
+// DefaultTransportOptions favor connection reuse. Commands talk to one or
+// two hosts, but Go's default of 2 idle connections per host makes
+// parallel requests (run --csv --concurrency, bulk pulls) reconnect, and
+// pay for a TLS handshake, far more often than needed. 16 per host covers
+// the usual concurrency; 90s keeps connections across short pauses such
+// as status polling while staying below common proxy idle timeouts.
+func DefaultTransportOptions() TransportOptions {
+	return TransportOptions{
+		MaxIdleConns:        64,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// NewTransport returns a transport with Go's default settings, such as
+// proxy support and dial timeouts, tuned with opts
+func NewTransport(opts TransportOptions) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = opts.MaxIdleConns
+	t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	t.IdleConnTimeout = opts.IdleConnTimeout
+	t.DisableKeepAlives = opts.DisableKeepAlives
+	return t
+}
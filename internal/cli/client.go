@@ -0,0 +1,123 @@
+// Package cli builds API clients from the configuration and the global
+// command line flags
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/config"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+)
+
+// NewClient creates a client for the instance given with the global
+// --instance flag, or for the current instance
+func NewClient(cmd *cobra.Command) (*api.Client, error) {
+	name, _ := cmd.Flags().GetString("instance")
+	return NewInstanceClient(cmd, name)
+}
+
+// callStats and auditLog record the requests of the clients built
+// afterwards, see RecordStats and RecordAudit
+var (
+	callStats *api.CallStats
+	auditLog  *api.AuditLog
+)
+
+// RecordStats makes clients built from now on record their requests in
+// stats, for --stats
+func RecordStats(stats *api.CallStats) {
+	callStats = stats
+}
+
+// RecordAudit makes clients built from now on append their mutating API
+// requests to log, for --audit-log
+func RecordAudit(log *api.AuditLog) {
+	auditLog = log
+}
+
+// Environment variables overriding the current instance, e.g. in CI or
+// loaded with --env-file
+const (
+	EnvURL    = "N8N_URL"
+	EnvAPIKey = "N8N_API_KEY"
+)
+
+// NewInstanceClient creates a client for the named instance, or for the
+// current one if name is empty. The other global flags, such as --header,
+// apply as with NewClient.
+//
+// Without a name, N8N_URL and N8N_API_KEY take precedence over the current
+// instance: N8N_URL replaces it altogether (no config file is needed
+// then), and N8N_API_KEY its key. Named instances ignore them, so a
+// command addressing two instances never sends both to the same one.
+func NewInstanceClient(cmd *cobra.Command, name string) (*api.Client, error) {
+	current := name == ""
+	envURL := os.Getenv(EnvURL)
+	cfg, err := config.Load()
+	if err != nil {
+		if !current || envURL == "" {
+			return nil, fmt.Errorf("not configured. Run 'n8nctl config init' first")
+		}
+		cfg, err = &config.Config{}, nil
+	}
+
+	var instance *config.Instance
+	switch {
+	case current && envURL != "":
+		instance = &config.Instance{Name: EnvURL, URL: envURL}
+	case current:
+		instance, err = cfg.GetCurrentInstance()
+		name = cfg.CurrentInstance
+	default:
+		instance, err = cfg.GetInstance(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key := os.Getenv(EnvAPIKey); current && key != "" {
+		instance.APIKey = key
+	}
+	if current && envURL != "" && instance.APIKey == "" {
+		return nil, fmt.Errorf("%s is set, but %s is not", EnvURL, EnvAPIKey)
+	}
+
+	transport := api.DefaultTransportOptions()
+	transport.DisableKeepAlives, _ = cmd.Flags().GetBool("no-keepalive")
+	opts := []api.Option{api.WithTransportOptions(transport)}
+	if callStats != nil {
+		opts = append(opts, api.WithRoundTripper(callStats.Transport))
+	}
+	if auditLog != nil {
+		opts = append(opts, api.WithRoundTripper(auditLog.Transport))
+	}
+	client := api.NewClient(instance.URL, instance.APIKey, opts...)
+	if err := client.SetAuthMode(instance.AuthMode); err != nil {
+		return nil, fmt.Errorf("instance '%s': %w", instance.Name, err)
+	}
+	client.SetRawPath(instance.NoVersionPrefix)
+	timeout, runTimeout, err := cfg.InstanceTimeouts(name)
+	if err != nil {
+		return nil, err
+	}
+	client.SetTimeouts(timeout, runTimeout)
+
+	// Instance headers first, so --header flags take precedence
+	headerFlags, _ := cmd.Flags().GetStringArray("header")
+	flagHeaders, err := config.ParseHeaders(headerFlags)
+	if err != nil {
+		return nil, err
+	}
+	for _, headers := range []map[string]string{instance.Headers, flagHeaders} {
+		for key, value := range headers {
+			if err := client.SetHeader(key, value); err != nil {
+				logging.Warn(fmt.Sprintf("ignoring header: %v", err), "header", key, "error", err)
+			}
+		}
+	}
+
+	return client, nil
+}
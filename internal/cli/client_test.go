@@ -0,0 +1,315 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+	"github.com/enthus-appdev/n8n-cli/internal/config"
+)
+
+func TestNewInstanceClientEnvOverrides(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   bool
+		instance string
+		envURL   bool
+		envKey   string
+		wantKey  string
+		wantErr  string
+	}{
+		{name: "config only", config: true, wantKey: "config-key"},
+		{name: "key override", config: true, envKey: "env-key", wantKey: "env-key"},
+		{name: "URL and key without config", envURL: true, envKey: "env-key", wantKey: "env-key"},
+		{name: "URL and key replace the current instance", config: true, envURL: true, envKey: "env-key", wantKey: "env-key"},
+		{name: "URL without key", envURL: true, wantErr: "N8N_URL is set, but N8N_API_KEY is not"},
+		{name: "named instance ignores the environment", config: true, instance: "prod", envURL: true, envKey: "env-key", wantKey: "config-key"},
+		{name: "no config, no environment", wantErr: "not configured"},
+		{name: "named instance needs the config", instance: "prod", envURL: true, envKey: "env-key", wantErr: "not configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv(EnvURL, "")
+			t.Setenv(EnvAPIKey, tt.envKey)
+
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+			if tt.envURL {
+				t.Setenv(EnvURL, srv.URL)
+			}
+			if tt.config {
+				// With N8N_URL set, requests must not reach the configured URL
+				configURL := srv.URL
+				if tt.envURL {
+					configURL = "http://127.0.0.1:1"
+				}
+				cfg := &config.Config{
+					CurrentInstance: "prod",
+					Instances:       map[string]config.Instance{"prod": {Name: "prod", URL: configURL, APIKey: "config-key"}},
+				}
+				if tt.instance != "" {
+					cfg.Instances["prod"] = config.Instance{Name: "prod", URL: srv.URL, APIKey: "config-key"}
+				}
+				if err := config.Save(cfg); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cmd := &cobra.Command{}
+			cmd.Flags().StringArray("header", nil, "")
+			client, err := NewInstanceClient(cmd, tt.instance)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewInstanceClient: %v", err)
+			}
+
+			if _, err := client.GetWorkflow("abc"); err != nil {
+				t.Fatalf("GetWorkflow: %v", err)
+			}
+			req, _ := srv.LastRequest()
+			if got := req.Header.Get("X-N8N-API-KEY"); got != tt.wantKey {
+				t.Errorf("API key = %q, want %q", got, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestNewInstanceClientAuthMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		header  string
+		value   string
+		absent  string
+		wantErr string
+	}{
+		{mode: "", header: "X-N8N-API-KEY", value: "config-key", absent: "Authorization"},
+		{mode: "apikey", header: "X-N8N-API-KEY", value: "config-key", absent: "Authorization"},
+		{mode: "bearer", header: "Authorization", value: "Bearer config-key", absent: "X-N8N-API-KEY"},
+		{mode: "basic", wantErr: "basic"},
+	}
+
+	for _, tt := range tests {
+		t.Run("mode="+tt.mode, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv(EnvURL, "")
+			t.Setenv(EnvAPIKey, "")
+
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+			cfg := &config.Config{
+				CurrentInstance: "prod",
+				Instances:       map[string]config.Instance{"prod": {Name: "prod", URL: srv.URL, APIKey: "config-key", AuthMode: tt.mode}},
+			}
+			if err := config.Save(cfg); err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := &cobra.Command{}
+			cmd.Flags().StringArray("header", nil, "")
+			client, err := NewInstanceClient(cmd, "")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewInstanceClient: %v", err)
+			}
+			if _, err := client.GetWorkflow("abc"); err != nil {
+				t.Fatalf("GetWorkflow: %v", err)
+			}
+
+			req, _ := srv.LastRequest()
+			if got := req.Header.Get(tt.header); got != tt.value {
+				t.Errorf("%s = %q, want %q", tt.header, got, tt.value)
+			}
+			if got := req.Header.Get(tt.absent); got != "" {
+				t.Errorf("%s = %q, want it unset", tt.absent, got)
+			}
+		})
+	}
+}
+
+func TestNewInstanceClientHeaders(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvURL, "")
+	t.Setenv(EnvAPIKey, "")
+
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+	cfg := &config.Config{
+		CurrentInstance: "prod",
+		Instances: map[string]config.Instance{"prod": {
+			Name:    "prod",
+			URL:     srv.URL,
+			APIKey:  "config-key",
+			Headers: map[string]string{"X-Team": "instance", "X-Tenant": "acme", "X-N8N-API-KEY": "instance-key"},
+		}},
+	}
+	if err := config.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("header", nil, "")
+	if err := cmd.Flags().Set("header", "X-Team=flag"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Flags().Set("header", "X-N8N-API-KEY=flag-key"); err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewInstanceClient(cmd, "")
+	if err != nil {
+		t.Fatalf("NewInstanceClient: %v", err)
+	}
+	if _, err := client.GetWorkflow("abc"); err != nil {
+		t.Fatalf("GetWorkflow: %v", err)
+	}
+
+	req, _ := srv.LastRequest()
+	for header, want := range map[string]string{
+		"X-Team":        "flag",
+		"X-Tenant":      "acme",
+		"X-N8N-API-KEY": "config-key",
+	} {
+		if got := req.Header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestNewInstanceClientNoKeepAlive(t *testing.T) {
+	for _, noKeepAlive := range []bool{false, true} {
+		t.Run(fmt.Sprintf("no-keepalive=%v", noKeepAlive), func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+			t.Setenv(EnvURL, srv.URL)
+			t.Setenv(EnvAPIKey, "env-key")
+
+			cmd := &cobra.Command{}
+			cmd.Flags().StringArray("header", nil, "")
+			cmd.Flags().Bool("no-keepalive", false, "")
+			if noKeepAlive {
+				if err := cmd.Flags().Set("no-keepalive", "true"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			client, err := NewInstanceClient(cmd, "")
+			if err != nil {
+				t.Fatalf("NewInstanceClient: %v", err)
+			}
+			if _, err := client.GetWorkflow("abc"); err != nil {
+				t.Fatalf("GetWorkflow: %v", err)
+			}
+
+			req, _ := srv.LastRequest()
+			if closed := req.Header.Get("Connection") == "close"; closed != noKeepAlive {
+				t.Errorf("Connection: close sent = %v, want %v", closed, noKeepAlive)
+			}
+		})
+	}
+}
+
+func TestNewInstanceClientStats(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Handle(http.MethodGet, "/workflows/abc", apitest.Response{Body: map[string]string{"id": "abc"}})
+	t.Setenv(EnvURL, srv.URL)
+	t.Setenv(EnvAPIKey, "env-key")
+
+	stats := api.NewCallStats()
+	RecordStats(stats)
+	t.Cleanup(func() { RecordStats(nil) })
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("header", nil, "")
+	client, err := NewInstanceClient(cmd, "")
+	if err != nil {
+		t.Fatalf("NewInstanceClient: %v", err)
+	}
+	if _, err := client.GetWorkflow("abc"); err != nil {
+		t.Fatalf("GetWorkflow: %v", err)
+	}
+	// Other HTTP clients of the process are not recorded
+	resp, err := http.Get(srv.URL + "/api/v1/workflows/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	var out strings.Builder
+	stats.Print(&out)
+	if !strings.Contains(out.String(), "GET /workflows/{id}") || !strings.Contains(out.String(), "\n1 API call(s)") {
+		t.Errorf("stats = %q, want one recorded GET /workflows/{id}", out.String())
+	}
+}
+
+func TestNewInstanceClientAudit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Handle(http.MethodPost, "/workflows/abc/activate", apitest.Response{Body: map[string]string{"id": "abc"}})
+	t.Setenv(EnvURL, srv.URL)
+	t.Setenv(EnvAPIKey, "env-key")
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	RecordAudit(api.NewAuditLog(path))
+	t.Cleanup(func() { RecordAudit(nil) })
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("header", nil, "")
+	client, err := NewInstanceClient(cmd, "")
+	if err != nil {
+		t.Fatalf("NewInstanceClient: %v", err)
+	}
+	if err := client.ActivateWorkflow("abc"); err != nil {
+		t.Fatalf("ActivateWorkflow: %v", err)
+	}
+	// Neither webhook calls nor other HTTP clients of the process are
+	// API mutations
+	_, _ = client.TriggerWebhook("workflows/abc/activate", http.MethodPost)
+	resp, err := http.Post(srv.URL+"/api/v1/workflows/abc/activate", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.Contains(lines[0], `"path":"/api/v1/workflows/abc/activate"`) {
+		t.Errorf("audit log = %s, want the one activation", data)
+	}
+}
@@ -5,11 +5,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/enthus-appdev/n8n-cli/internal/api"
 	"github.com/enthus-appdev/n8n-cli/internal/config"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+	"github.com/enthus-appdev/n8n-cli/internal/output"
+	"github.com/enthus-appdev/n8n-cli/internal/prompt"
 )
 
 func NewConfigCmd() *cobra.Command {
@@ -21,18 +27,27 @@ func NewConfigCmd() *cobra.Command {
 
 	cmd.AddCommand(newInitCmd())
 	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newCurrentCmd())
 	cmd.AddCommand(newUseCmd())
+	cmd.AddCommand(newSetCmd())
 	cmd.AddCommand(newRemoveCmd())
+	cmd.AddCommand(newImportCmd())
 
 	return cmd
 }
 
 func newInitCmd() *cobra.Command {
 	var (
-		name    string
-		url     string
-		apiKey  string
-		setDefault bool
+		name          string
+		url           string
+		apiKey        string
+		apiKeyFile    string
+		apiKeyCommand string
+		headers       []string
+		authMode      string
+		rawPath       bool
+		setDefault    bool
+		force         bool
 	)
 
 	cmd := &cobra.Command{
@@ -41,8 +56,55 @@ func newInitCmd() *cobra.Command {
 		Long: `Interactively configure a new n8n instance connection.
 
 You can also provide flags for non-interactive setup:
-  n8n config init --name prod --url https://n8n.example.com --api-key YOUR_KEY`,
+  n8n config init --name prod --url https://n8n.example.com --api-key YOUR_KEY
+
+Instead of storing the key in the config file, it can be read from a file
+or from the output of a command (e.g. a secret manager) each time it is used:
+  n8n config init --name prod --url ... --api-key-file ~/.secrets/n8n
+  n8n config init --name prod --url ... --api-key-command "op read op://vault/n8n/key"
+
+Use --auth-mode bearer when n8n is fronted by an OAuth proxy that expects
+an "Authorization: Bearer" token instead of the X-N8N-API-KEY header.
+
+Requests go to <url>/api/v1/<endpoint>. Behind an API gateway that
+serves the API under a path of its own and adds /api/v1 when forwarding,
+use --raw-path with the gateway's full API URL, so requests go to
+<url>/<endpoint> instead:
+  n8n config init --name prod --url https://gateway.example.com/n8n --raw-path
+The gateway URL says nothing about where n8n serves webhooks, so webhook
+commands (workflow webhooks, workflow run --webhook) are refused for such
+instances.
+
+Re-running init for an existing instance asks for a confirmation, which
+--force or the global --yes skip. Only the values given are updated;
+everything else, including the API key, is kept:
+  n8n config init --name prod --url https://new.example.com --force`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			sources := 0
+			for _, v := range []string{apiKey, apiKeyFile, apiKeyCommand} {
+				if v != "" {
+					sources++
+				}
+			}
+			if sources > 1 {
+				return fmt.Errorf("only one of --api-key, --api-key-file, and --api-key-command may be given")
+			}
+
+			if authMode != api.AuthModeAPIKey && authMode != api.AuthModeBearer {
+				return fmt.Errorf("invalid --auth-mode %q (expected %s or %s)", authMode, api.AuthModeAPIKey, api.AuthModeBearer)
+			}
+
+			switch {
+			case apiKeyFile != "":
+				path, err := filepath.Abs(apiKeyFile)
+				if err != nil {
+					return fmt.Errorf("invalid API key file path: %w", err)
+				}
+				apiKey = config.APIKeyFilePrefix + path
+			case apiKeyCommand != "":
+				apiKey = config.APIKeyCommandPrefix + apiKeyCommand
+			}
+
 			reader := bufio.NewReader(os.Stdin)
 
 			// Interactive prompts for missing values
@@ -52,6 +114,36 @@ You can also provide flags for non-interactive setup:
 				name = strings.TrimSpace(name)
 			}
 
+			// An existing instance is only updated with --force or after a
+			// confirmation; values not given are taken over from it instead
+			// of being prompted for
+			var existing *config.Instance
+			if cfg, err := config.Load(); err == nil {
+				if inst, ok := cfg.Instances[name]; ok {
+					if !force {
+						if err := prompt.Confirm(cmd, fmt.Sprintf("Instance '%s' already exists. Update it?", name)); err != nil {
+							return fmt.Errorf("instance '%s' already exists: %w", name, err)
+						}
+						force = true
+					}
+					existing = &inst
+				}
+			}
+			if existing != nil {
+				if url == "" {
+					url = existing.URL
+				}
+				if apiKey == "" {
+					apiKey = existing.APIKey
+				}
+				if !cmd.Flags().Changed("auth-mode") && existing.AuthMode != "" {
+					authMode = existing.AuthMode
+				}
+				if !cmd.Flags().Changed("raw-path") {
+					rawPath = existing.NoVersionPrefix
+				}
+			}
+
 			if url == "" {
 				fmt.Print("n8n URL (e.g., 'http://localhost:5678'): ")
 				url, _ = reader.ReadString('\n')
@@ -69,36 +161,75 @@ You can also provide flags for non-interactive setup:
 				return fmt.Errorf("name, URL, and API key are required")
 			}
 
-			// Normalize URL (remove trailing slash)
-			url = strings.TrimSuffix(url, "/")
-
-			instance := config.Instance{
-				Name:   name,
-				URL:    url,
-				APIKey: apiKey,
+			// Normalize URL (scheme check, no trailing slash or /api/v1
+			// unless the path is used as is)
+			normalized, warnings, err := api.NormalizeBaseURL(url, rawPath)
+			if err != nil {
+				return err
+			}
+			for _, w := range warnings {
+				logging.Warn(w)
 			}
+			url = normalized
 
-			cfg, err := config.Load()
+			instanceHeaders, err := config.ParseHeaders(headers)
 			if err != nil {
-				// Create new config if doesn't exist
-				cfg = &config.Config{
-					Instances: make(map[string]config.Instance),
+				return err
+			}
+			if len(instanceHeaders) == 0 {
+				instanceHeaders = nil
+				if existing != nil {
+					instanceHeaders = existing.Headers
+				}
+			}
+			authHeader := api.AuthHeader(authMode)
+			for key := range instanceHeaders {
+				if strings.EqualFold(key, authHeader) {
+					return fmt.Errorf("header %s is set from the API key and cannot be configured as an extra header", key)
 				}
 			}
 
-			cfg.Instances[name] = instance
-
-			// Set as default if it's the first instance or explicitly requested
-			if len(cfg.Instances) == 1 || setDefault {
-				cfg.CurrentInstance = name
+			// Start from the existing instance so settings init doesn't
+			// manage (e.g. aliases) survive an update
+			var instance config.Instance
+			if existing != nil {
+				instance = *existing
+			}
+			instance.Name = name
+			instance.URL = url
+			instance.APIKey = apiKey
+			instance.Headers = instanceHeaders
+			// Only persist non-default modes so existing configs stay unchanged
+			instance.AuthMode = ""
+			if authMode == api.AuthModeBearer {
+				instance.AuthMode = authMode
 			}
+			instance.NoVersionPrefix = rawPath
 
-			if err := config.Save(cfg); err != nil {
+			var current string
+			err = config.Update(func(cfg *config.Config) error {
+				if _, exists := cfg.Instances[name]; exists && !force {
+					return fmt.Errorf("instance '%s' already exists. Use --force to update it", name)
+				}
+				cfg.Instances[name] = instance
+
+				// Set as default if it's the first instance or explicitly requested
+				if len(cfg.Instances) == 1 || setDefault {
+					cfg.CurrentInstance = name
+				}
+				current = cfg.CurrentInstance
+				return nil
+			})
+			if err != nil {
 				return fmt.Errorf("failed to save config: %w", err)
 			}
 
-			fmt.Printf("Instance '%s' configured successfully.\n", name)
-			if cfg.CurrentInstance == name {
+			if existing != nil {
+				fmt.Printf("Instance '%s' updated successfully.\n", name)
+			} else {
+				fmt.Printf("Instance '%s' configured successfully.\n", name)
+			}
+			if current == name {
 				fmt.Printf("Set as active instance.\n")
 			}
 
@@ -109,15 +240,27 @@ You can also provide flags for non-interactive setup:
 	cmd.Flags().StringVar(&name, "name", "", "Instance name")
 	cmd.Flags().StringVar(&url, "url", "", "n8n instance URL")
 	cmd.Flags().StringVar(&apiKey, "api-key", "", "API key for authentication")
+	cmd.Flags().StringVar(&apiKeyFile, "api-key-file", "", "Read the API key from this file on each use")
+	cmd.Flags().StringVar(&apiKeyCommand, "api-key-command", "", "Run this command to obtain the API key on each use")
+	cmd.Flags().StringVar(&authMode, "auth-mode", api.AuthModeAPIKey, "How to send the API key: apikey (X-N8N-API-KEY header) or bearer (Authorization header)")
+	cmd.Flags().BoolVar(&rawPath, "raw-path", false, "Send requests to the URL plus the endpoint path, without adding /api/v1 (for API gateways)")
+	cmd.Flags().StringArrayVar(&headers, "header", nil, "Extra HTTP header sent with every request as key=value (can be repeated)")
 	cmd.Flags().BoolVar(&setDefault, "default", false, "Set as default instance")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Update an existing instance without asking, keeping values that are not given")
 
 	return cmd
 }
 
 func newListCmd() *cobra.Command {
-	return &cobra.Command{
+	var showKeys bool
+
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List configured n8n instances",
+		Long: `List configured n8n instances with a masked preview of each API key.
+
+Use --show-keys to reveal the full API key of the active instance. In table
+mode this asks for a confirmation first, which the global --yes skips.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg, err := config.Load()
 			if err != nil {
@@ -129,40 +272,149 @@ func newListCmd() *cobra.Command {
 				return nil
 			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
-			if jsonFlag {
-				// Output JSON (hide API keys for security)
+			names := make([]string, 0, len(cfg.Instances))
+			for name := range cfg.Instances {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			var revealed string
+			if showKeys {
+				if !output.IsStructured(cmd) {
+					if err := prompt.Confirm(cmd, fmt.Sprintf("Reveal the API key of instance '%s'?", cfg.CurrentInstance)); err != nil {
+						return err
+					}
+				}
+				instance, err := cfg.GetCurrentInstance()
+				if err != nil {
+					return err
+				}
+				revealed = instance.APIKey
+			}
+
+			if output.IsStructured(cmd) {
+				// Structured output only carries a masked key preview unless
+				// --show-keys was given explicitly
 				type instanceInfo struct {
-					Name   string `json:"name"`
-					URL    string `json:"url"`
-					Active bool   `json:"active"`
-				}
-				instances := make([]instanceInfo, 0, len(cfg.Instances))
-				for name, inst := range cfg.Instances {
-					instances = append(instances, instanceInfo{
-						Name:   name,
-						URL:    inst.URL,
-						Active: name == cfg.CurrentInstance,
-					})
-				}
-				return printJSON(map[string]interface{}{
+					Name           string `json:"name"`
+					URL            string `json:"url"`
+					Active         bool   `json:"active"`
+					APIKeyPreview  string `json:"apiKeyPreview"`
+					APIKey         string `json:"apiKey,omitempty"`
+					DefaultPullDir string `json:"defaultPullDir,omitempty"`
+					Timeout        string `json:"timeout,omitempty"`
+					RunTimeout     string `json:"runTimeout,omitempty"`
+				}
+				instances := make([]instanceInfo, 0, len(names))
+				for _, name := range names {
+					inst := cfg.Instances[name]
+					info := instanceInfo{
+						Name:           name,
+						URL:            inst.URL,
+						Active:         name == cfg.CurrentInstance,
+						APIKeyPreview:  config.MaskAPIKey(inst.APIKey),
+						DefaultPullDir: inst.DefaultPullDir,
+						Timeout:        inst.Timeout,
+						RunTimeout:     inst.RunTimeout,
+					}
+					if info.Active {
+						info.APIKey = revealed
+					}
+					instances = append(instances, info)
+				}
+				result := map[string]interface{}{
 					"instances": instances,
 					"current":   cfg.CurrentInstance,
-				})
+				}
+				if cfg.DefaultPullDir != "" {
+					result["defaultPullDir"] = cfg.DefaultPullDir
+				}
+				if cfg.Timeout != "" {
+					result["timeout"] = cfg.Timeout
+				}
+				if cfg.RunTimeout != "" {
+					result["runTimeout"] = cfg.RunTimeout
+				}
+				return output.Print(cmd, result)
 			}
 
 			fmt.Println("Configured instances:")
-			for name, inst := range cfg.Instances {
+			for _, name := range names {
+				inst := cfg.Instances[name]
 				marker := "  "
+				key := config.MaskAPIKey(inst.APIKey)
 				if name == cfg.CurrentInstance {
 					marker = "* "
+					if revealed != "" {
+						key = revealed
+					}
+				}
+				fmt.Printf("%s%s (%s)  key: %s\n", marker, name, inst.URL, key)
+				if inst.DefaultPullDir != "" {
+					fmt.Printf("    default pull dir: %s\n", inst.DefaultPullDir)
 				}
-				fmt.Printf("%s%s (%s)\n", marker, name, inst.URL)
+				if inst.Timeout != "" {
+					fmt.Printf("    timeout: %s\n", inst.Timeout)
+				}
+				if inst.RunTimeout != "" {
+					fmt.Printf("    run timeout: %s\n", inst.RunTimeout)
+				}
+			}
+			if cfg.DefaultPullDir != "" || cfg.Timeout != "" || cfg.RunTimeout != "" {
+				fmt.Println()
+			}
+			if cfg.DefaultPullDir != "" {
+				fmt.Printf("Default pull dir: %s\n", cfg.DefaultPullDir)
+			}
+			if cfg.Timeout != "" {
+				fmt.Printf("Timeout: %s\n", cfg.Timeout)
+			}
+			if cfg.RunTimeout != "" {
+				fmt.Printf("Run timeout: %s\n", cfg.RunTimeout)
 			}
 
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&showKeys, "show-keys", false, "Reveal the full API key of the active instance")
+
+	return cmd
+}
+
+func newCurrentCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "current",
+		Short: "Print the active n8n instance",
+		Long: `Print the name of the active instance, e.g. for use in scripts.
+Structured output also includes its URL. Exits with an error if no
+instance is selected.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("no configuration found. Run 'n8n config init' first")
+			}
+
+			if cfg.CurrentInstance == "" {
+				return fmt.Errorf("no instance selected. Run 'n8n config use <name>'")
+			}
+			inst, exists := cfg.Instances[cfg.CurrentInstance]
+			if !exists {
+				return fmt.Errorf("instance '%s' not found", cfg.CurrentInstance)
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, map[string]string{
+					"name": cfg.CurrentInstance,
+					"url":  inst.URL,
+				})
+			}
+
+			fmt.Println(cfg.CurrentInstance)
+			return nil
+		},
+	}
 }
 
 func newUseCmd() *cobra.Command {
@@ -173,18 +425,20 @@ func newUseCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
-			cfg, err := config.Load()
-			if err != nil {
+			if !config.Exists() {
 				return fmt.Errorf("no configuration found. Run 'n8n config init' first")
 			}
 
-			if _, exists := cfg.Instances[name]; !exists {
-				return fmt.Errorf("instance '%s' not found", name)
-			}
+			err := config.Update(func(cfg *config.Config) error {
+				if _, exists := cfg.Instances[name]; !exists {
+					return fmt.Errorf("instance '%s' not found", name)
+				}
 
-			cfg.CurrentInstance = name
-			if err := config.Save(cfg); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
+				cfg.CurrentInstance = name
+				return nil
+			})
+			if err != nil {
+				return err
 			}
 
 			fmt.Printf("Switched to instance '%s'\n", name)
@@ -193,37 +447,147 @@ func newUseCmd() *cobra.Command {
 	}
 }
 
+// settings lists the keys accepted by 'config set'
+var settings = []string{"default-pull-dir", "timeout", "run-timeout", "audit-log"}
+
+func newSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a CLI setting",
+		Long: `Change a CLI setting, globally or with the global --instance flag for a
+single instance. Instance settings take precedence over global ones. An empty value removes
+the setting.
+
+Settings:
+  default-pull-dir  Directory 'workflow pull' writes to when --dir is not
+                    given, relative to the working directory
+  timeout           Maximum duration of an API request, e.g. 30s
+                    (default: 1m)
+  run-timeout       Maximum duration of a request that executes a workflow,
+                    such as 'workflow run --wait' (default: 30m)
+  audit-log         File that mutating API requests are appended to as
+                    JSON lines, relative to the config directory unless
+                    absolute (global only)`,
+		Example: `  n8nctl config set default-pull-dir ./workflows
+  n8nctl config set default-pull-dir ./prod-workflows --instance prod
+  n8nctl config set default-pull-dir ""
+  n8nctl config set run-timeout 2h --instance prod
+  n8nctl config set audit-log audit.log`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, value := args[0], args[1]
+			instanceName, _ := cmd.Flags().GetString("instance")
+
+			var set func(global *config.Config, instance *config.Instance)
+			switch key {
+			case "default-pull-dir":
+				set = func(global *config.Config, instance *config.Instance) {
+					if instance != nil {
+						instance.DefaultPullDir = value
+					} else {
+						global.DefaultPullDir = value
+					}
+				}
+			case "timeout", "run-timeout":
+				if _, err := config.ParseTimeout(value); err != nil {
+					return fmt.Errorf("invalid %s: %w", key, err)
+				}
+				set = func(global *config.Config, instance *config.Instance) {
+					timeout, runTimeout := &global.Timeout, &global.RunTimeout
+					if instance != nil {
+						timeout, runTimeout = &instance.Timeout, &instance.RunTimeout
+					}
+					if key == "timeout" {
+						*timeout = value
+					} else {
+						*runTimeout = value
+					}
+				}
+			case "audit-log":
+				if instanceName != "" {
+					return fmt.Errorf("audit-log can only be set globally")
+				}
+				set = func(global *config.Config, instance *config.Instance) {
+					global.AuditLog = value
+				}
+			default:
+				return fmt.Errorf("unknown setting %q (available: %s)", key, strings.Join(settings, ", "))
+			}
+
+			if !config.Exists() {
+				return fmt.Errorf("no configuration found. Run 'n8n config init' first")
+			}
+
+			err := config.Update(func(cfg *config.Config) error {
+				if instanceName == "" {
+					set(cfg, nil)
+					return nil
+				}
+				inst, exists := cfg.Instances[instanceName]
+				if !exists {
+					return fmt.Errorf("instance '%s' not found", instanceName)
+				}
+				set(cfg, &inst)
+				cfg.Instances[instanceName] = inst
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			scope := "globally"
+			if instanceName != "" {
+				scope = fmt.Sprintf("for instance '%s'", instanceName)
+			}
+			if value == "" {
+				fmt.Printf("Removed %s %s\n", key, scope)
+			} else {
+				fmt.Printf("Set %s to %s %s\n", key, value, scope)
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
 func newRemoveCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "remove <instance-name>",
 		Short: "Remove a configured n8n instance",
+		Long:  `Remove a configured instance. Asks for confirmation unless --yes is given.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			name := args[0]
 
-			cfg, err := config.Load()
-			if err != nil {
+			if !config.Exists() {
 				return fmt.Errorf("no configuration found")
 			}
 
-			if _, exists := cfg.Instances[name]; !exists {
-				return fmt.Errorf("instance '%s' not found", name)
+			if err := prompt.Confirm(cmd, fmt.Sprintf("Remove instance '%s'?", name)); err != nil {
+				return err
 			}
 
-			delete(cfg.Instances, name)
-
-			// Clear current if it was the removed instance
-			if cfg.CurrentInstance == name {
-				cfg.CurrentInstance = ""
-				// Set first available as current
-				for n := range cfg.Instances {
-					cfg.CurrentInstance = n
-					break
+			err := config.Update(func(cfg *config.Config) error {
+				if _, exists := cfg.Instances[name]; !exists {
+					return fmt.Errorf("instance '%s' not found", name)
 				}
-			}
 
-			if err := config.Save(cfg); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
+				delete(cfg.Instances, name)
+
+				// Clear current if it was the removed instance
+				if cfg.CurrentInstance == name {
+					cfg.CurrentInstance = ""
+					// Set first available as current
+					for n := range cfg.Instances {
+						cfg.CurrentInstance = n
+						break
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
 			}
 
 			fmt.Printf("Instance '%s' removed.\n", name)
@@ -232,8 +596,115 @@ func newRemoveCmd() *cobra.Command {
 	}
 }
 
-func printJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(v)
+func newImportCmd() *cobra.Command {
+	var (
+		onConflict    string
+		noActivate    bool
+		allowCommands bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Merge the instances of another config file",
+		Long: `Merge the instances of another config file, e.g. one shared by a
+teammate, into your configuration. Other settings of the file are ignored.
+
+--on-conflict decides what happens to an instance whose name already
+exists: skip keeps yours (default), overwrite replaces it, and rename
+stores the imported one as <name>-2 (or the next free number).
+
+The file's current instance becomes yours unless --no-activate is given
+or it was skipped.
+
+API keys of the form "cmd:<command>" run that command every time the
+instance is used. Files containing any are refused unless
+--allow-commands is given; the commands are printed either way.`,
+		Example: `  n8nctl config import team.json
+  n8nctl config import team.json --on-conflict rename --no-activate`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode := config.ConflictMode(onConflict)
+			switch mode {
+			case config.ConflictSkip, config.ConflictOverwrite, config.ConflictRename:
+			default:
+				return fmt.Errorf("invalid --on-conflict %q (use skip, overwrite, or rename)", onConflict)
+			}
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read file: %w", err)
+			}
+			var imported config.Config
+			if err := json.Unmarshal(data, &imported); err != nil {
+				return fmt.Errorf("failed to parse %s: %w", args[0], err)
+			}
+			if len(imported.Instances) == 0 {
+				return fmt.Errorf("%s contains no instances", args[0])
+			}
+			for name, instance := range imported.Instances {
+				if instance.URL == "" {
+					return fmt.Errorf("instance '%s' in %s has no URL", name, args[0])
+				}
+			}
+			// A "cmd:" API key runs a shell command whenever the instance
+			// is used, so a shared file must not bring one in unnoticed
+			if commands := imported.APIKeyCommands(); len(commands) > 0 {
+				names := make([]string, 0, len(commands))
+				for name := range commands {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					logging.Warn(fmt.Sprintf("instance '%s' gets its API key by running: %s", name, commands[name]),
+						"instance", name, "command", commands[name])
+				}
+				if !allowCommands {
+					return fmt.Errorf("%s runs commands to get API keys; check them and use --allow-commands to import it anyway", args[0])
+				}
+			}
+
+			var result *config.ImportResult
+			err = config.Update(func(cfg *config.Config) error {
+				result = cfg.Import(&imported, mode, !noActivate)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, result)
+			}
+
+			for _, name := range result.Added {
+				fmt.Printf("Added instance '%s'\n", name)
+			}
+			for _, name := range result.Overwritten {
+				fmt.Printf("Overwrote instance '%s'\n", name)
+			}
+			renamed := make([]string, 0, len(result.Renamed))
+			for name := range result.Renamed {
+				renamed = append(renamed, name)
+			}
+			sort.Strings(renamed)
+			for _, name := range renamed {
+				fmt.Printf("Added instance '%s' as '%s'\n", name, result.Renamed[name])
+			}
+			for _, name := range result.Skipped {
+				fmt.Printf("Skipped instance '%s' (already exists)\n", name)
+			}
+			fmt.Printf("\n%d added, %d overwritten, %d renamed, %d skipped.\n",
+				len(result.Added), len(result.Overwritten), len(result.Renamed), len(result.Skipped))
+			if result.Activated != "" {
+				fmt.Printf("Switched to instance '%s'\n", result.Activated)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&onConflict, "on-conflict", string(config.ConflictSkip), "What to do with existing instance names: skip, overwrite, or rename")
+	cmd.Flags().BoolVar(&noActivate, "no-activate", false, "Keep the current instance")
+	cmd.Flags().BoolVar(&allowCommands, "allow-commands", false, "Import instances whose API key is a cmd: helper command")
+
+	return cmd
 }
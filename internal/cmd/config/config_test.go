@@ -0,0 +1,49 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/config"
+)
+
+func runInit(t *testing.T, args ...string) error {
+	t.Helper()
+	cmd := newInitCmd()
+	cmd.SetArgs(args)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return cmd.Execute()
+}
+
+func TestInitExistingInstance(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	if err := runInit(t, "--name", "prod", "--url", "https://n8n.example.com", "--api-key", "first-key"); err != nil {
+		t.Fatalf("first init: %v", err)
+	}
+
+	err := runInit(t, "--name", "prod", "--url", "https://other.example.com", "--api-key", "second-key")
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("second init without --force: error = %v, want it refused", err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Instances["prod"]; got.URL != "https://n8n.example.com" || got.APIKey != "first-key" {
+		t.Errorf("refused init changed the instance to %+v", got)
+	}
+
+	if err := runInit(t, "--name", "prod", "--url", "https://other.example.com", "--force"); err != nil {
+		t.Fatalf("init with --force: %v", err)
+	}
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Instances["prod"]; got.URL != "https://other.example.com" || got.APIKey != "first-key" {
+		t.Errorf("after --force instance = %+v, want the new URL and the kept API key", got)
+	}
+}
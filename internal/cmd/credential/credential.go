@@ -0,0 +1,280 @@
+package credential
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/cli"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+	"github.com/enthus-appdev/n8n-cli/internal/output"
+	"github.com/enthus-appdev/n8n-cli/internal/pagestate"
+	"github.com/enthus-appdev/n8n-cli/internal/prompt"
+	"github.com/enthus-appdev/n8n-cli/internal/workflow"
+)
+
+func NewCredentialCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "credential",
+		Aliases: []string{"cred"},
+		Short:   "Manage n8n credentials",
+		Long: `List, inspect, and transfer n8n credentials. Secret values are never
+returned by the API.
+
+Many n8n versions don't offer credential listing in the public API. On
+those instances the commands report that instead of failing with a raw
+API error.`,
+	}
+
+	cmd.AddCommand(output.Pageable(newListCmd()))
+	cmd.AddCommand(newGetCmd())
+	cmd.AddCommand(newTransferCmd())
+
+	return cmd
+}
+
+// errListingUnavailable is reported instead of the API error on instances
+// without the credential read endpoints
+var errListingUnavailable = errors.New("credential listing is not available via the n8n API on this instance")
+
+// unavailableStatus returns the status code of err if it is an API error
+// that instances without the credential read endpoints answer with, or 0
+func unavailableStatus(err error) int {
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+func newListCmd() *cobra.Command {
+	var (
+		limit  int
+		cursor string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List credentials",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := pagestate.Flags{Limit: limit, LimitSet: cmd.Flags().Changed("limit"), Cursor: cursor}
+			if err := flags.Check(); err != nil {
+				return err
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			result, err := client.ListCredentials(limit, cursor)
+			if err != nil {
+				if unavailableStatus(err) != 0 {
+					return errListingUnavailable
+				}
+				return fmt.Errorf("failed to list credentials: %w", err)
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, result)
+			}
+
+			if len(result.Data) == 0 {
+				fmt.Println("No credentials found.")
+				return nil
+			}
+
+			table := output.NewTable(cmd, "ID", "TYPE", "NAME")
+			for _, c := range result.Data {
+				table.AddRow(c.ID, c.Type, c.Name)
+			}
+			table.Print()
+
+			if result.NextCursor != "" {
+				table.Footer("More results available. Use --cursor %s to continue.", result.NextCursor)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of credentials to return (0 = unlimited)")
+	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor for next page")
+
+	return cmd
+}
+
+func newGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <credential-id>",
+		Short: "Show a credential",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			cred, err := client.GetCredential(args[0])
+			if err != nil {
+				switch unavailableStatus(err) {
+				case http.StatusMethodNotAllowed:
+					return errListingUnavailable
+				case http.StatusNotFound:
+					// Missing credentials and a missing endpoint look the same
+					return fmt.Errorf("credential %s not found (or %v)", args[0], errListingUnavailable)
+				}
+				return fmt.Errorf("failed to get credential: %w", err)
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, cred)
+			}
+
+			fmt.Printf("Credential: %s (%s)\n", cred.Name, cred.ID)
+			fmt.Printf("Type: %s\n", cred.Type)
+			if cred.CreatedAt != nil {
+				fmt.Printf("Created: %s\n", cred.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			if cred.UpdatedAt != nil {
+				fmt.Printf("Updated: %s\n", cred.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// transferResult is the outcome of transferring one credential
+type transferResult struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	Transferred bool   `json:"transferred"`
+	Error       string `json:"error,omitempty"`
+}
+
+func newTransferCmd() *cobra.Command {
+	var (
+		project string
+		all     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "transfer [credential-id...] --project <project>",
+		Short: "Transfer credentials to another project",
+		Long: `Transfer credentials to another project, given by ID or name.
+
+Without credential IDs, they are read from stdin, one per line; blank
+lines and lines starting with # are skipped. --all transfers every
+credential the API lists, after asking for confirmation (skip it with
+--yes). Many n8n versions can't list credentials, so --all only works
+where 'n8nctl credential list' does.
+
+Each credential is reported as it is transferred, and failures don't
+stop the rest.`,
+		Example: `  n8nctl credential transfer 12 --project Marketing
+  jq -r '.credentials[].id' manifest.json | n8nctl credential transfer --project Marketing
+  n8nctl credential transfer --all --project Archive --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if project == "" {
+				return fmt.Errorf("--project is required")
+			}
+			if all && len(args) > 0 {
+				return fmt.Errorf("--all cannot be combined with credential IDs")
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			target, err := workflow.ResolveProject(client, project)
+			if err != nil {
+				return err
+			}
+
+			var creds []api.Credential
+			switch {
+			case all:
+				result, err := client.ListCredentials(0, "")
+				if err != nil {
+					if unavailableStatus(err) != 0 {
+						return fmt.Errorf("%w; pass the credential IDs instead of --all", errListingUnavailable)
+					}
+					return fmt.Errorf("failed to list credentials: %w", err)
+				}
+				creds = result.Data
+				if len(creds) == 0 {
+					fmt.Println("No credentials found.")
+					return nil
+				}
+				if err := prompt.Confirm(cmd, fmt.Sprintf("Transfer all %d credential(s) to project %s?", len(creds), target.Name)); err != nil {
+					return err
+				}
+			case len(args) > 0:
+				for _, id := range args {
+					creds = append(creds, api.Credential{ID: id})
+				}
+			default:
+				ids, err := prompt.StdinIDs()
+				if err != nil {
+					return err
+				}
+				for _, id := range ids {
+					creds = append(creds, api.Credential{ID: id})
+				}
+			}
+
+			structured := output.IsStructured(cmd)
+			results := make([]transferResult, 0, len(creds))
+			failed := 0
+			for i, cred := range creds {
+				result := transferResult{ID: cred.ID, Name: cred.Name}
+				label := cred.ID
+				if cred.Name != "" {
+					label = fmt.Sprintf("%s (%s)", cred.Name, cred.ID)
+				}
+				counter := ""
+				if len(creds) > 1 {
+					counter = fmt.Sprintf("[%d/%d] ", i+1, len(creds))
+				}
+
+				if err := client.TransferCredential(cred.ID, target.ID); err != nil {
+					result.Error = err.Error()
+					failed++
+					if !structured {
+						logging.Error(fmt.Sprintf("%sfailed to transfer credential %s: %v", counter, label, err), "credential", cred.ID, "error", err)
+					}
+				} else {
+					result.Transferred = true
+					if !structured {
+						fmt.Printf("%sCredential %s transferred to project %s.\n", counter, label, target.Name)
+					}
+				}
+				results = append(results, result)
+			}
+
+			if structured {
+				if err := output.Print(cmd, results); err != nil {
+					return err
+				}
+			} else if len(creds) > 1 {
+				fmt.Printf("\n%d credential(s) transferred, %d failed.\n", len(creds)-failed, failed)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d credential(s) could not be transferred", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Destination project ID or name (required)")
+	cmd.Flags().BoolVar(&all, "all", false, "Transfer every credential the API lists")
+
+	return cmd
+}
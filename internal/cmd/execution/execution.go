@@ -1,16 +1,30 @@
 package execution
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/theory/jsonpath"
 
 	"github.com/enthus-appdev/n8n-cli/internal/api"
-	"github.com/enthus-appdev/n8n-cli/internal/config"
+	"github.com/enthus-appdev/n8n-cli/internal/cli"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+	"github.com/enthus-appdev/n8n-cli/internal/output"
+	"github.com/enthus-appdev/n8n-cli/internal/pagestate"
+	"github.com/enthus-appdev/n8n-cli/internal/progress"
+	"github.com/enthus-appdev/n8n-cli/internal/prompt"
+	"github.com/enthus-appdev/n8n-cli/internal/redact"
+	"github.com/enthus-appdev/n8n-cli/internal/workflow"
 )
 
 func NewExecutionCmd() *cobra.Command {
@@ -21,58 +35,135 @@ func NewExecutionCmd() *cobra.Command {
 		Long:    `List, view, and manage workflow execution history.`,
 	}
 
-	cmd.AddCommand(newListCmd())
-	cmd.AddCommand(newViewCmd())
+	cmd.AddCommand(output.Pageable(newListCmd()))
+	cmd.AddCommand(output.Pageable(newViewCmd()))
+	cmd.AddCommand(newExportCmd())
+	cmd.AddCommand(newExportAllCmd())
 	cmd.AddCommand(newRetryCmd())
 	cmd.AddCommand(newDeleteCmd())
 
 	return cmd
 }
 
-func getClient() (*api.Client, error) {
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, fmt.Errorf("not configured. Run 'n8n config init' first")
-	}
-
-	instance, err := cfg.GetCurrentInstance()
-	if err != nil {
-		return nil, err
-	}
-
-	return api.NewClient(instance.URL, instance.APIKey), nil
-}
-
 func newListCmd() *cobra.Command {
 	var (
 		workflowID   string
+		projectID    string
 		status       string
 		limit        int
 		cursor       string
+		resumeFile   string
+		saveCursor   string
 		resolveNames bool
+		minDuration  time.Duration
+		maxDuration  time.Duration
+		sinceFlag    string
+		groupBy      string
+		selectID     bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List workflow executions",
+		Long: `List workflow executions.
+
+--min-duration and --max-duration filter the fetched executions by run
+time (e.g. 30s, 5m). Executions that haven't stopped yet are measured up
+to now, so --min-duration also finds hung executions. The filters are
+applied after --limit, so fewer executions than the limit may be shown.
+
+--since only lists executions started after a point in time, given as a
+duration (24h, 7d) or a date (2006-01-02, or RFC 3339 with a time).
+Pages are fetched until an older execution is reached.
+
+--group-by workflow|status|day prints counts per group instead of
+individual executions; grouping by workflow also shows success rates.
+Unless --limit is given, all matching executions are counted, so
+combine it with --since or --workflow on busy instances.
+
+--workflow and --project take an ID or a name; a name used more than
+once is an error.
+
+--select lists the executions on the terminal for picking one, and prints
+only the chosen ID.`,
+		Example: `  n8nctl exec list --since 24h --group-by workflow --resolve-names
+  n8nctl exec list --since 7d --group-by day -o json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			switch groupBy {
+			case "", groupByWorkflow, groupByStatus, groupByDay:
+			default:
+				return fmt.Errorf("invalid --group-by %q (expected workflow, status, or day)", groupBy)
+			}
+			if groupBy != "" && !cmd.Flags().Changed("limit") {
+				limit = 0
+			}
+
+			flags := pagestate.Flags{Limit: limit, LimitSet: cmd.Flags().Changed("limit"), Cursor: cursor, Resume: resumeFile, SaveCursor: saveCursor}
+			if err := flags.Check(); err != nil {
+				return err
+			}
+
+			var since time.Time
+			if sinceFlag != "" {
+				var err error
+				since, err = parseSince(sinceFlag)
+				if err != nil {
+					return err
+				}
+			}
+
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
+			if workflowID, projectID, err = resolveFilters(client, workflowID, projectID); err != nil {
+				return err
+			}
+
+			filterHash := pagestate.FilterHash([]interface{}{workflowID, status, limit, minDuration, maxDuration, sinceFlag, projectID})
+			if resumeFile != "" {
+				saved, complete, err := pagestate.Resume(resumeFile, "execution list", filterHash)
+				if err != nil {
+					return err
+				}
+				if complete {
+					logging.Info(fmt.Sprintf("All results were already listed. Delete %s to start over.", resumeFile), "resumeFile", resumeFile)
+					return nil
+				}
+				cursor = saved
+			}
+
 			opts := api.ListExecutionsOptions{
 				WorkflowID: workflowID,
+				ProjectID:  projectID,
 				Status:     status,
 				Limit:      limit,
 				Cursor:     cursor,
 			}
 
-			result, err := client.ListExecutions(opts)
+			result, err := listExecutionsSince(client, opts, since)
 			if err != nil {
 				return fmt.Errorf("failed to list executions: %w", err)
 			}
 
+			if saveCursor != "" {
+				state := &pagestate.State{Command: "execution list", FilterHash: filterHash, Cursor: result.NextCursor}
+				if err := pagestate.Save(saveCursor, state); err != nil {
+					return err
+				}
+			}
+
+			if minDuration > 0 || maxDuration > 0 {
+				filtered := result.Data[:0]
+				for _, exec := range result.Data {
+					if d, ok := elapsed(exec); ok && d >= minDuration && (maxDuration == 0 || d <= maxDuration) {
+						filtered = append(filtered, exec)
+					}
+				}
+				result.Data = filtered
+			}
+
 			executions := result.Data
 
 			// Optionally resolve workflow names
@@ -92,8 +183,31 @@ func newListCmd() *cobra.Command {
 				}
 			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
-			if jsonFlag {
+			if groupBy != "" {
+				return printGroups(cmd, groupExecutions(executions, groupBy, workflowNames))
+			}
+
+			if selectID {
+				options := make([]prompt.Option, len(executions))
+				for i, exec := range executions {
+					workflow := workflowNames[exec.WorkflowID]
+					if workflow == "" {
+						workflow = exec.WorkflowID
+					}
+					options[i] = prompt.Option{
+						ID:    exec.ID,
+						Label: fmt.Sprintf("%-10s  %-10s  %-20s  %s", exec.ID, exec.Status, formatTime(exec.StartedAt), workflow),
+					}
+				}
+				id, err := prompt.Select(options)
+				if err != nil {
+					return err
+				}
+				fmt.Println(id)
+				return nil
+			}
+
+			if output.IsStructured(cmd) {
 				// Enrich with workflow names if resolved
 				if resolveNames {
 					type enrichedExecution struct {
@@ -107,16 +221,16 @@ func newListCmd() *cobra.Command {
 							WorkflowName: workflowNames[exec.WorkflowID],
 						}
 					}
-					output := struct {
+					enrichedResult := struct {
 						Data       []enrichedExecution `json:"data"`
 						NextCursor string              `json:"nextCursor,omitempty"`
 					}{
 						Data:       enriched,
 						NextCursor: result.NextCursor,
 					}
-					return printJSON(output)
+					return output.Print(cmd, enrichedResult)
 				}
-				return printJSON(result)
+				return output.Print(cmd, result)
 			}
 
 			if len(executions) == 0 {
@@ -124,82 +238,339 @@ func newListCmd() *cobra.Command {
 				return nil
 			}
 
-			// Table output
+			var table *output.TableWriter
 			if resolveNames {
-				fmt.Printf("%-10s  %-10s  %-20s  %s\n", "ID", "STATUS", "STARTED", "WORKFLOW")
-				fmt.Printf("%-10s  %-10s  %-20s  %s\n",
-					strings.Repeat("-", 10),
-					strings.Repeat("-", 10),
-					strings.Repeat("-", 20),
-					strings.Repeat("-", 40))
-
+				table = output.NewTable(cmd, "ID", "STATUS", "STARTED", "DURATION", "WORKFLOW")
 				for _, exec := range executions {
 					startedAt := formatTime(exec.StartedAt)
 					name := workflowNames[exec.WorkflowID]
 					if name == "" {
 						name = exec.WorkflowID
 					}
-					fmt.Printf("%-10s  %-10s  %-20s  %s\n",
-						exec.ID,
-						exec.Status,
-						startedAt,
-						truncate(name, 40))
+					table.AddRow(exec.ID, string(exec.Status), startedAt, formatDuration(exec), output.Truncate(name, 40))
 				}
 			} else {
-				fmt.Printf("%-10s  %-18s  %-10s  %s\n", "ID", "WORKFLOW ID", "STATUS", "STARTED")
-				fmt.Printf("%-10s  %-18s  %-10s  %s\n",
-					strings.Repeat("-", 10),
-					strings.Repeat("-", 18),
-					strings.Repeat("-", 10),
-					strings.Repeat("-", 20))
-
+				table = output.NewTable(cmd, "ID", "WORKFLOW ID", "STATUS", "STARTED", "DURATION")
 				for _, exec := range executions {
-					startedAt := formatTime(exec.StartedAt)
-					fmt.Printf("%-10s  %-18s  %-10s  %s\n",
-						exec.ID,
-						exec.WorkflowID,
-						exec.Status,
-						startedAt)
+					table.AddRow(exec.ID, exec.WorkflowID, string(exec.Status), formatTime(exec.StartedAt), formatDuration(exec))
 				}
 			}
+			table.Print()
 
 			if result.NextCursor != "" {
-				fmt.Printf("\nMore results available. Use --cursor %s to continue.\n", result.NextCursor)
+				table.Footer("More results available. Use --cursor %s to continue.", result.NextCursor)
 			}
 
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&workflowID, "workflow", "", "Filter by workflow ID")
+	cmd.Flags().StringVar(&workflowID, "workflow", "", "Filter by workflow ID or name")
+	cmd.Flags().StringVar(&projectID, "project", "", "Filter by project ID or name")
 	cmd.Flags().StringVar(&status, "status", "", "Filter by status (running, success, error, waiting)")
-	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of executions to return")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of executions to return (0 = unlimited)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor for next page")
+	cmd.Flags().StringVar(&resumeFile, "resume", "", "Continue from the cursor saved in this file by --save-cursor")
+	cmd.Flags().StringVar(&saveCursor, "save-cursor", "", "Save the next cursor to this file for a later --resume")
 	cmd.Flags().BoolVar(&resolveNames, "resolve-names", false, "Fetch workflow names (slower, extra API calls)")
+	cmd.Flags().DurationVar(&minDuration, "min-duration", 0, "Only show executions that ran at least this long")
+	cmd.Flags().DurationVar(&maxDuration, "max-duration", 0, "Only show executions that ran at most this long")
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "Only show executions started after this duration ago (24h, 7d) or date")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Print counts per workflow, status, or day instead of executions")
+	cmd.Flags().BoolVar(&selectID, "select", false, "Pick an execution interactively and print its ID")
 
 	return cmd
 }
 
+// parseSince parses a --since value: a duration before now, with d for
+// days allowed, or an absolute date or time
+func parseSince(value string) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Now().AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (expected a duration like 24h or 7d, or a date like 2006-01-02)", value)
+}
+
+// sincePageSize is the page size used while paging back to --since
+const sincePageSize = 250
+
+// listExecutionsSince lists executions like ListExecutions, but stops at
+// the first execution that started before since. n8n returns the newest
+// executions first, so everything after it is older as well.
+func listExecutionsSince(client *api.Client, opts api.ListExecutionsOptions, since time.Time) (*api.ListResult[api.Execution], error) {
+	if since.IsZero() {
+		return client.ListExecutions(opts)
+	}
+
+	result := &api.ListResult[api.Execution]{}
+	page := opts
+	for {
+		page.Limit = sincePageSize
+		if opts.Limit > 0 {
+			page.Limit = min(opts.Limit-len(result.Data), sincePageSize)
+		}
+
+		batch, err := client.ListExecutions(page)
+		if err != nil {
+			return nil, err
+		}
+		for _, exec := range batch.Data {
+			if exec.StartedAt != nil && exec.StartedAt.Before(since) {
+				return result, nil
+			}
+			result.Data = append(result.Data, exec)
+		}
+
+		if batch.NextCursor == "" {
+			return result, nil
+		}
+		if opts.Limit > 0 && len(result.Data) >= opts.Limit {
+			result.NextCursor = batch.NextCursor
+			return result, nil
+		}
+		page.Cursor = batch.NextCursor
+	}
+}
+
+const (
+	groupByWorkflow = "workflow"
+	groupByStatus   = "status"
+	groupByDay      = "day"
+)
+
+// executionGroup is one row of 'execution list --group-by'
+type executionGroup struct {
+	GroupBy  string         `json:"groupBy"`
+	Key      string         `json:"key"`
+	Name     string         `json:"name,omitempty"`
+	Total    int            `json:"total"`
+	Statuses map[string]int `json:"statuses"`
+	// SuccessRate is the share of finished executions that succeeded,
+	// only set when grouping by workflow
+	SuccessRate *float64 `json:"successRate,omitempty"`
+}
+
+// groupExecutions counts executions per group. Workflows are ordered by
+// total, most first; days chronologically; statuses by count.
+func groupExecutions(executions []api.Execution, groupBy string, workflowNames map[string]string) []*executionGroup {
+	byKey := make(map[string]*executionGroup)
+	var groups []*executionGroup
+	for _, exec := range executions {
+		var key string
+		switch groupBy {
+		case groupByWorkflow:
+			key = exec.WorkflowID
+		case groupByStatus:
+			key = exec.Status
+		case groupByDay:
+			key = "unknown"
+			if exec.StartedAt != nil {
+				key = exec.StartedAt.Local().Format("2006-01-02")
+			}
+		}
+
+		g, ok := byKey[key]
+		if !ok {
+			g = &executionGroup{GroupBy: groupBy, Key: key, Statuses: make(map[string]int)}
+			if groupBy == groupByWorkflow {
+				g.Name = workflowNames[key]
+			}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.Total++
+		g.Statuses[exec.Status]++
+	}
+
+	for _, g := range groups {
+		if groupBy != groupByWorkflow {
+			continue
+		}
+		finished := g.Statuses["success"] + g.Statuses["error"] + g.Statuses["crashed"] + g.Statuses["canceled"]
+		if finished > 0 {
+			rate := float64(g.Statuses["success"]) / float64(finished)
+			g.SuccessRate = &rate
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groupBy == groupByDay {
+			return groups[i].Key < groups[j].Key
+		}
+		if groups[i].Total != groups[j].Total {
+			return groups[i].Total > groups[j].Total
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+func printGroups(cmd *cobra.Command, groups []*executionGroup) error {
+	if output.IsStructured(cmd) {
+		if groups == nil {
+			groups = []*executionGroup{}
+		}
+		return output.Print(cmd, groups)
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("No executions found.")
+		return nil
+	}
+
+	total := 0
+	if groups[0].GroupBy == groupByStatus {
+		table := output.NewTable(cmd, "STATUS", "COUNT")
+		for _, g := range groups {
+			table.AddRow(g.Key, strconv.Itoa(g.Total))
+			total += g.Total
+		}
+		table.Print()
+		table.Footer("%d execution(s)", total)
+		return nil
+	}
+
+	header := "DAY"
+	if groups[0].GroupBy == groupByWorkflow {
+		header = "WORKFLOW"
+	}
+	table := output.NewTable(cmd, "TOTAL", "SUCCESS", "ERROR", "OTHER", "RATE", header)
+	for _, g := range groups {
+		success, errors := g.Statuses["success"], g.Statuses["error"]
+		rate := "-"
+		if g.SuccessRate != nil {
+			rate = fmt.Sprintf("%.0f%%", *g.SuccessRate*100)
+		}
+		label := g.Key
+		if g.Name != "" {
+			label = fmt.Sprintf("%s (%s)", output.Truncate(g.Name, 40), g.Key)
+		}
+		table.AddRow(strconv.Itoa(g.Total), strconv.Itoa(success), strconv.Itoa(errors), strconv.Itoa(g.Total-success-errors), rate, label)
+		total += g.Total
+	}
+	table.Print()
+	table.Footer("%d execution(s) in %d group(s)", total, len(groups))
+	return nil
+}
+
 func newViewCmd() *cobra.Command {
-	var showData bool
+	var (
+		showData bool
+		top      int
+		nodeName string
+		query    string
+		children bool
+
+		redactOut     bool
+		redactProfile string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "view <execution-id>",
 		Short: "View execution details",
-		Args:  cobra.ExactArgs(1),
+		Long: `View execution details.
+
+With --node, only the output items of that node's last run are printed.
+Adding --jsonpath applies an RFC 9535 JSONPath query to those items and
+prints only the matches. The query runs against the array of item JSON
+objects, e.g.:
+
+  n8nctl exec view 123 --node "HTTP Request" --jsonpath '$[*].email'
+  n8nctl exec view 123 --node Filter --jsonpath '$[?@.status == "failed"].id'
+
+--node reads the execution while it downloads and skips the data of the
+other nodes, so it also works on executions too large to load at once.
+--data, --top, --children and the JSON output read the run data while it
+downloads too, leaving out data.executionData, which only repeats the
+input of pending nodes.
+
+With --children, executions of sub-workflows started by Execute Workflow
+nodes are fetched recursively and shown as a tree below the parent.
+
+--redact masks values stored under sensitive keys in the execution data;
+--redact-profile selects a profile from the config file's redactProfiles.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			var path *jsonpath.Path
+			if query != "" {
+				if nodeName == "" {
+					return fmt.Errorf("--jsonpath requires --node to select the node whose output is queried")
+				}
+				var err error
+				path, err = jsonpath.Parse(query)
+				if err != nil {
+					return fmt.Errorf("invalid JSONPath expression: %w", err)
+				}
+			}
+
+			var r *redact.Redactor
+			if redactOut || redactProfile != "" {
+				var err error
+				r, err = redact.ForConfiguredProfile(redactProfile)
+				if err != nil {
+					return err
+				}
+			}
+
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
+			structured := output.IsStructured(cmd)
+			if nodeName != "" {
+				items, err := streamNodeItems(client, args[0], nodeName)
+				if err != nil {
+					return err
+				}
+				if r != nil {
+					r.Apply(items)
+				}
+				if path == nil {
+					return output.Print(cmd, items)
+				}
+				matches := path.Select(items)
+				if structured {
+					return output.Print(cmd, matches)
+				}
+				for _, match := range matches {
+					if err := output.Print(cmd, match); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			showTimings := showData || cmd.Flags().Changed("top")
+			if top < 0 {
+				return fmt.Errorf("--top must not be negative")
+			}
+
 			// Auto-include data in JSON mode
-			includeData := showData || jsonFlag
-			exec, err := client.GetExecution(args[0], includeData)
+			includeData := showTimings || structured || children
+			var exec *api.Execution
+			if includeData {
+				exec, err = streamExecution(client, args[0])
+			} else {
+				exec, err = client.GetExecution(args[0], false)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to get execution: %w", err)
 			}
+			if r != nil {
+				r.Apply(exec.Data)
+			}
 
 			// Fetch workflow name
 			workflowName := ""
@@ -207,16 +578,28 @@ func newViewCmd() *cobra.Command {
 				workflowName = wf.Name
 			}
 
-			if jsonFlag {
+			var tree []*childExecution
+			if children {
+				names := map[string]string{exec.WorkflowID: workflowName}
+				tree = fetchChildExecutions(client, exec, names, map[string]bool{exec.ID: true}, 0)
+			}
+
+			if structured {
 				// Enrich JSON with workflow name
 				enriched := struct {
 					api.Execution
-					WorkflowName string `json:"workflowName,omitempty"`
+					WorkflowName string            `json:"workflowName,omitempty"`
+					Children     []*childExecution `json:"children,omitempty"`
+					SlowestNodes []nodeTiming      `json:"slowestNodes,omitempty"`
 				}{
 					Execution:    *exec,
 					WorkflowName: workflowName,
+					Children:     tree,
+				}
+				if showTimings {
+					enriched.SlowestNodes = topTimings(nodeTimings(exec.Data), top)
 				}
-				return printJSON(enriched)
+				return output.Print(cmd, enriched)
 			}
 
 			// Human-readable output
@@ -235,9 +618,8 @@ func newViewCmd() *cobra.Command {
 			if exec.StoppedAt != nil {
 				fmt.Printf("Stopped: %s\n", formatTime(exec.StoppedAt))
 			}
-			if exec.StartedAt != nil && exec.StoppedAt != nil {
-				duration := exec.StoppedAt.Sub(*exec.StartedAt)
-				fmt.Printf("Duration: %s\n", duration.Round(time.Millisecond))
+			if duration := formatDuration(*exec); duration != "" {
+				fmt.Printf("Duration: %s\n", duration)
 			}
 
 			if exec.Error != "" {
@@ -252,16 +634,114 @@ func newViewCmd() *cobra.Command {
 			if showData && exec.Data != nil {
 				printNodeData(exec.Data)
 			}
+			if showTimings && exec.Data != nil {
+				printSlowestNodes(nodeTimings(exec.Data), top)
+			}
+
+			if children {
+				fmt.Printf("\nSub-executions:\n")
+				if len(tree) == 0 {
+					fmt.Println("  (none)")
+				}
+				printChildTree(tree, "  ")
+			}
 
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&showData, "data", false, "Include per-node execution data")
+	cmd.Flags().IntVar(&top, "top", 10, "Number of nodes in the slowest nodes summary (0 = all); shows it without --data too")
+	cmd.Flags().BoolVar(&children, "children", false, "Show sub-workflow executions as a tree")
+	cmd.Flags().BoolVar(&redactOut, "redact", false, "Mask sensitive values in the execution data")
+	cmd.Flags().StringVar(&redactProfile, "redact-profile", "", "Redaction profile from the config file (implies --redact)")
+	cmd.Flags().StringVar(&nodeName, "node", "", "Print the output items of this node")
+	cmd.Flags().StringVar(&query, "jsonpath", "", "JSONPath query applied to the --node output items")
 
 	return cmd
 }
 
+// nodeTiming is the time a node spent across all its runs
+type nodeTiming struct {
+	Node    string  `json:"node"`
+	Runs    int     `json:"runs"`
+	TimeMs  float64 `json:"timeMs"`
+	Percent float64 `json:"percent"`
+}
+
+// nodeTimings adds up the executionTime of every run of each node and
+// returns the nodes sorted by time, slowest first. Percent is the share
+// of the time of all nodes together.
+func nodeTimings(data map[string]interface{}) []nodeTiming {
+	resultData, _ := data["resultData"].(map[string]interface{})
+	runData, _ := resultData["runData"].(map[string]interface{})
+
+	var timings []nodeTiming
+	total := 0.0
+	for name, nodeRuns := range runData {
+		runs, _ := nodeRuns.([]interface{})
+		if len(runs) == 0 {
+			continue
+		}
+		timing := nodeTiming{Node: name, Runs: len(runs)}
+		for _, r := range runs {
+			run, _ := r.(map[string]interface{})
+			if et, ok := run["executionTime"].(float64); ok {
+				timing.TimeMs += et
+			}
+		}
+		total += timing.TimeMs
+		timings = append(timings, timing)
+	}
+
+	for i := range timings {
+		if total > 0 {
+			timings[i].Percent = timings[i].TimeMs / total * 100
+		}
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].TimeMs != timings[j].TimeMs {
+			return timings[i].TimeMs > timings[j].TimeMs
+		}
+		return timings[i].Node < timings[j].Node
+	})
+	return timings
+}
+
+// topTimings returns the first n timings, or all for n = 0
+func topTimings(timings []nodeTiming, n int) []nodeTiming {
+	if n > 0 && len(timings) > n {
+		return timings[:n]
+	}
+	return timings
+}
+
+// printSlowestNodes prints the n slowest nodes with their share of the
+// total node time
+func printSlowestNodes(timings []nodeTiming, n int) {
+	if len(timings) == 0 {
+		return
+	}
+	total := 0.0
+	for _, t := range timings {
+		total += t.TimeMs
+	}
+
+	fmt.Printf("\nSlowest Nodes:\n")
+	fmt.Printf("──────────────\n")
+	for _, t := range topTimings(timings, n) {
+		runs := ""
+		if t.Runs > 1 {
+			runs = fmt.Sprintf(" (%d runs)", t.Runs)
+		}
+		fmt.Printf("  %8dms  %5.1f%%  %s%s\n", int(t.TimeMs), t.Percent, t.Node, runs)
+	}
+	if shown := len(topTimings(timings, n)); shown < len(timings) {
+		fmt.Printf("  ... %d more node(s)\n", len(timings)-shown)
+	}
+	fmt.Printf("  Total node time: %dms\n", int(total))
+}
+
 // printErrorDetails extracts and prints the last executed node and its error
 // from the execution data. The expected structure is:
 //
@@ -294,15 +774,14 @@ func nodeErrorMessage(resultData map[string]interface{}, nodeName string) string
 	if !ok {
 		return ""
 	}
-	nodeRuns, ok := runData[nodeName].([]interface{})
-	if !ok || len(nodeRuns) == 0 {
-		return ""
-	}
-	lastRun, ok := nodeRuns[len(nodeRuns)-1].(map[string]interface{})
-	if !ok {
-		return ""
-	}
-	errObj, ok := lastRun["error"].(map[string]interface{})
+	nodeRuns, _ := runData[nodeName].([]interface{})
+	return runErrorMessage(nodeRuns)
+}
+
+// runErrorMessage extracts the error message from the last of a node's
+// runs
+func runErrorMessage(runs []interface{}) string {
+	errObj, ok := lastRun(runs)["error"].(map[string]interface{})
 	if !ok {
 		return ""
 	}
@@ -310,6 +789,253 @@ func nodeErrorMessage(resultData map[string]interface{}, nodeName string) string
 	return msg
 }
 
+// lastRun returns the last of a node's runs, or nil
+func lastRun(runs []interface{}) map[string]interface{} {
+	if len(runs) == 0 {
+		return nil
+	}
+	run, _ := runs[len(runs)-1].(map[string]interface{})
+	return run
+}
+
+// streamNodeItems fetches the output items of one node's last run. The
+// execution is decoded while it downloads, skipping the other nodes' run
+// data, so large executions don't have to fit in memory.
+func streamNodeItems(client *api.Client, id, nodeName string) ([]interface{}, error) {
+	body, err := client.OpenExecution(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution: %w", err)
+	}
+	defer body.Close()
+
+	var items []interface{}
+	wanted := func(node string) bool { return node == nodeName }
+	_, err = api.DecodeExecution(body, wanted, func(_ string, runs []interface{}) error {
+		if len(runs) > 0 {
+			items = lastRunItems(runs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution: %w", err)
+	}
+	if items == nil {
+		return nil, fmt.Errorf("node %q has no run data in this execution", nodeName)
+	}
+	return items, nil
+}
+
+// streamExecution fetches an execution with its data, decoding the run
+// data node by node while it downloads instead of reading the whole
+// response first. The result leaves out data.executionData, which only
+// repeats the input of pending nodes.
+func streamExecution(client *api.Client, id string) (*api.Execution, error) {
+	body, err := client.OpenExecution(id)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	runData := make(map[string]interface{})
+	exec, err := api.DecodeExecution(body, nil, func(node string, runs []interface{}) error {
+		runData[node] = runs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if exec.Data != nil {
+		resultData, _ := exec.Data["resultData"].(map[string]interface{})
+		if resultData == nil {
+			resultData = make(map[string]interface{})
+			exec.Data["resultData"] = resultData
+		}
+		resultData["runData"] = runData
+	}
+	return exec, nil
+}
+
+// lastRunItems returns the JSON payload of every output item produced by
+// the last of a node's runs, across all output branches:
+//
+//	runs[last].data.main[branch][i].json
+func lastRunItems(runs []interface{}) []interface{} {
+	output, _ := lastRun(runs)["data"].(map[string]interface{})
+	main, _ := output["main"].([]interface{})
+
+	items := []interface{}{}
+	for _, branch := range main {
+		branchItems, ok := branch.([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range branchItems {
+			itemMap, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if j, ok := itemMap["json"]; ok {
+				items = append(items, j)
+			}
+		}
+	}
+
+	return items
+}
+
+// maxChildDepth bounds how deep --children follows nested sub-executions
+const maxChildDepth = 10
+
+// childExecution is a sub-workflow execution started by a node of its
+// parent execution
+type childExecution struct {
+	ID           string            `json:"id"`
+	Node         string            `json:"node"`
+	WorkflowID   string            `json:"workflowId"`
+	WorkflowName string            `json:"workflowName,omitempty"`
+	Status       string            `json:"status"`
+	StartedAt    *time.Time        `json:"startedAt,omitempty"`
+	StoppedAt    *time.Time        `json:"stoppedAt,omitempty"`
+	Error        string            `json:"error,omitempty"`
+	Children     []*childExecution `json:"children,omitempty"`
+}
+
+// childExecutionRefs collects the sub-executions recorded in an execution's
+// run data. Execute Workflow nodes store them on the run and, in newer n8n
+// versions, on each output item:
+//
+//	data.resultData.runData[node][i].metadata.subExecution
+//	data.resultData.runData[node][i].data.main[branch][j].metadata.subExecution
+//
+// Each reference carries an executionId and workflowId. Results are ordered
+// by node name and deduplicated by execution ID.
+func childExecutionRefs(data map[string]interface{}) []*childExecution {
+	resultData, _ := data["resultData"].(map[string]interface{})
+	runData, _ := resultData["runData"].(map[string]interface{})
+
+	nodes := make([]string, 0, len(runData))
+	for name := range runData {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+
+	var refs []*childExecution
+	seen := make(map[string]bool)
+	add := func(node string, metadata interface{}) {
+		meta, _ := metadata.(map[string]interface{})
+		sub, _ := meta["subExecution"].(map[string]interface{})
+		var id string
+		switch v := sub["executionId"].(type) {
+		case string:
+			id = v
+		case float64:
+			id = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		if id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+		wfID, _ := sub["workflowId"].(string)
+		refs = append(refs, &childExecution{ID: id, Node: node, WorkflowID: wfID})
+	}
+
+	for _, node := range nodes {
+		runs, _ := runData[node].([]interface{})
+		for _, r := range runs {
+			run, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			add(node, run["metadata"])
+
+			output, _ := run["data"].(map[string]interface{})
+			main, _ := output["main"].([]interface{})
+			for _, branch := range main {
+				items, _ := branch.([]interface{})
+				for _, item := range items {
+					if itemMap, ok := item.(map[string]interface{}); ok {
+						add(node, itemMap["metadata"])
+					}
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+// fetchChildExecutions resolves the sub-executions of exec recursively.
+// Workflow names are cached in names; visited guards against loops.
+// Children that cannot be fetched are kept with status "unknown".
+func fetchChildExecutions(client *api.Client, exec *api.Execution, names map[string]string, visited map[string]bool, depth int) []*childExecution {
+	if depth >= maxChildDepth || exec.Data == nil {
+		return nil
+	}
+
+	refs := childExecutionRefs(exec.Data)
+	for _, child := range refs {
+		if visited[child.ID] {
+			child.Status = "unknown"
+			continue
+		}
+		visited[child.ID] = true
+
+		childExec, err := client.GetExecution(child.ID, true)
+		if err != nil {
+			child.Status = "unknown"
+			child.Error = err.Error()
+			continue
+		}
+		child.WorkflowID = childExec.WorkflowID
+		child.Status = childExec.Status
+		child.StartedAt = childExec.StartedAt
+		child.StoppedAt = childExec.StoppedAt
+		if childExec.Data != nil {
+			resultData, _ := childExec.Data["resultData"].(map[string]interface{})
+			lastNode, _ := resultData["lastNodeExecuted"].(string)
+			child.Error = nodeErrorMessage(resultData, lastNode)
+		}
+
+		if _, ok := names[child.WorkflowID]; !ok {
+			names[child.WorkflowID] = ""
+			if wf, err := client.GetWorkflow(child.WorkflowID); err == nil {
+				names[child.WorkflowID] = wf.Name
+			}
+		}
+		child.WorkflowName = names[child.WorkflowID]
+
+		child.Children = fetchChildExecutions(client, childExec, names, visited, depth+1)
+	}
+
+	return refs
+}
+
+// printChildTree prints sub-executions as an indented tree
+func printChildTree(children []*childExecution, indent string) {
+	for i, child := range children {
+		branch, next := "├─ ", "│  "
+		if i == len(children)-1 {
+			branch, next = "└─ ", "   "
+		}
+
+		workflow := child.WorkflowName
+		if workflow == "" {
+			workflow = child.WorkflowID
+		}
+		line := fmt.Sprintf("%s%s%s  %s  %s", indent, branch, child.ID, child.Status, workflow)
+		if child.StartedAt != nil && child.StoppedAt != nil {
+			line += fmt.Sprintf("  %s", child.StoppedAt.Sub(*child.StartedAt).Round(time.Millisecond))
+		}
+		line += fmt.Sprintf("  (via %q)", child.Node)
+		fmt.Println(line)
+		if child.Error != "" {
+			fmt.Printf("%s%s   Error: %s\n", indent, next, child.Error)
+		}
+
+		printChildTree(child.Children, indent+next)
+	}
+}
+
 func printNodeData(data map[string]interface{}) {
 	resultData, ok := data["resultData"].(map[string]interface{})
 	if !ok {
@@ -396,65 +1122,618 @@ func countItems(run map[string]interface{}) (input, output int) {
 	return
 }
 
-func newRetryCmd() *cobra.Command {
-	var loadWorkflow bool
+// exportSummary is written to summary.json by 'execution export'
+type exportSummary struct {
+	ExecutionID string       `json:"executionId"`
+	WorkflowID  string       `json:"workflowId"`
+	Status      string       `json:"status"`
+	Mode        string       `json:"mode"`
+	StartedAt   *time.Time   `json:"startedAt,omitempty"`
+	StoppedAt   *time.Time   `json:"stoppedAt,omitempty"`
+	DurationMs  *int64       `json:"durationMs,omitempty"`
+	Redacted    int          `json:"redacted,omitempty"`
+	Nodes       []exportNode `json:"nodes"`
+}
+
+// exportNode describes the last run of a node and the file its output
+// items were written to
+type exportNode struct {
+	Name            string   `json:"name"`
+	File            string   `json:"file"`
+	Status          string   `json:"status"`
+	Runs            int      `json:"runs"`
+	Items           int      `json:"items"`
+	ExecutionTimeMs *float64 `json:"executionTimeMs,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+func newExportCmd() *cobra.Command {
+	var (
+		dir       string
+		nodes     []string
+		redactOut bool
+		force     bool
+		raw       string
+
+		redactProfile string
+	)
 
 	cmd := &cobra.Command{
-		Use:   "retry <execution-id>",
-		Short: "Retry a failed execution",
-		Args:  cobra.ExactArgs(1),
+		Use:   "export <execution-id>",
+		Short: "Export node output items to files",
+		Long: `Write the output items of each node's last run to a JSON file named
+after the node, plus a summary.json with the execution's status and
+per-node statuses, item counts, and timings.
+
+The directory defaults to execution-<id>. Use --node to export only some
+nodes, and --redact to mask values stored under sensitive keys
+(passwords, tokens, API keys, ...). --redact-profile uses a profile from
+the config file's redactProfiles instead of the built-in key list.
+
+The execution is decoded while it downloads and each node is written
+before the next one is read, so executions with hundreds of megabytes of
+data don't need that much memory. --raw saves the API response as it is,
+without decoding it at all.`,
+		Example: `  n8nctl exec export 123 --dir out/
+  n8nctl exec export 123 --node "HTTP Request" --node Merge --redact
+  n8nctl exec export 123 --raw execution-123.json`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			if raw != "" {
+				for _, name := range []string{"dir", "node", "redact", "redact-profile"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--raw cannot be combined with --%s", name)
+					}
+				}
+			}
+
+			var r *redact.Redactor
+			if redactOut || redactProfile != "" {
+				var err error
+				r, err = redact.ForConfiguredProfile(redactProfile)
+				if err != nil {
+					return err
+				}
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			if raw != "" {
+				return exportRaw(client, args[0], raw, force)
+			}
+
+			body, err := client.OpenExecution(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get execution: %w", err)
+			}
+			defer body.Close()
+
+			if dir == "" {
+				dir = "execution-" + args[0]
+			}
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+
+			// Nodes are written as they are decoded, so only one node's
+			// run data is in memory at a time
+			var wanted func(string) bool
+			if len(nodes) > 0 {
+				requested := make(map[string]bool, len(nodes))
+				for _, name := range nodes {
+					requested[name] = true
+				}
+				wanted = func(node string) bool { return requested[node] }
+			}
+			summary := exportSummary{Nodes: []exportNode{}}
+			used := make(map[string]bool)
+			exported := make(map[string]bool)
+			exec, err := api.DecodeExecution(body, wanted, func(name string, runs []interface{}) error {
+				if len(runs) == 0 {
+					return nil
+				}
+				items := lastRunItems(runs)
+				if r != nil {
+					summary.Redacted += r.Apply(items)
+				}
+
+				file := exportFilename(name, used)
+				if err := writeExportFile(filepath.Join(dir, file), items, force); err != nil {
+					return err
+				}
+
+				run := lastRun(runs)
+				node := exportNode{
+					Name:   name,
+					File:   file,
+					Status: "success",
+					Runs:   len(runs),
+					Items:  len(items),
+					Error:  runErrorMessage(runs),
+				}
+				if _, hasError := run["error"]; hasError {
+					node.Status = "error"
+				}
+				if et, ok := run["executionTime"].(float64); ok {
+					node.ExecutionTimeMs = &et
+				}
+				summary.Nodes = append(summary.Nodes, node)
+				exported[name] = true
+				return nil
+			})
 			if err != nil {
+				return fmt.Errorf("failed to export execution: %w", err)
+			}
+
+			for _, name := range nodes {
+				if !exported[name] {
+					return fmt.Errorf("node %q has no run data in this execution", name)
+				}
+			}
+			sort.Slice(summary.Nodes, func(i, j int) bool { return summary.Nodes[i].Name < summary.Nodes[j].Name })
+
+			summary.ExecutionID = exec.ID
+			summary.WorkflowID = exec.WorkflowID
+			summary.Status = exec.Status
+			summary.Mode = exec.Mode
+			summary.StartedAt = exec.StartedAt
+			summary.StoppedAt = exec.StoppedAt
+			if d, ok := exec.Duration(); ok {
+				ms := d.Milliseconds()
+				summary.DurationMs = &ms
+			}
+
+			if err := writeExportFile(filepath.Join(dir, "summary.json"), summary, force); err != nil {
 				return err
 			}
 
-			exec, err := client.RetryExecution(args[0], loadWorkflow)
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, summary)
+			}
+
+			fmt.Printf("Exported %d node(s) to %s\n", len(summary.Nodes), dir)
+			if summary.Redacted > 0 {
+				fmt.Printf("Redacted %d sensitive value(s).\n", summary.Redacted)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Output directory (default: execution-<id>)")
+	cmd.Flags().StringArrayVar(&nodes, "node", nil, "Only export this node (can be repeated)")
+	cmd.Flags().BoolVar(&redactOut, "redact", false, "Mask sensitive values in the exported items")
+	cmd.Flags().StringVar(&redactProfile, "redact-profile", "", "Redaction profile from the config file (implies --redact)")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing files")
+	cmd.Flags().StringVar(&raw, "raw", "", "Save the API response unchanged to this file (- for stdout) instead of per-node files")
+
+	return cmd
+}
+
+// exportRaw copies an execution response with its data to path, or to
+// stdout for "-", without decoding it
+func exportRaw(client *api.Client, id, path string, force bool) error {
+	body, err := client.OpenExecution(id)
+	if err != nil {
+		return fmt.Errorf("failed to get execution: %w", err)
+	}
+	defer body.Close()
+
+	if path == "-" {
+		if _, err := io.Copy(os.Stdout, body); err != nil {
+			return fmt.Errorf("failed to download execution: %w", err)
+		}
+		return nil
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return fmt.Errorf("file %s already exists. Use --force to overwrite", path)
+		}
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	n, err := io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a truncated response behind that looks complete
+		os.Remove(path)
+		return fmt.Errorf("failed to download execution: %w", err)
+	}
+	logging.Info(fmt.Sprintf("Saved execution %s to %s (%d bytes)", id, path, n), "execution", id, "file", path, "bytes", n)
+	return nil
+}
+
+// exportFilename returns a unique file name for a node's output. Node
+// names that sanitize to the same name get a numeric suffix.
+func exportFilename(nodeName string, used map[string]bool) string {
+	base := workflow.SanitizeFilename(nodeName)
+	if base == "summary" {
+		base = "summary_node"
+	}
+	name := base
+	for i := 2; used[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[strings.ToLower(name)] = true
+	return name + ".json"
+}
+
+func writeExportFile(path string, v interface{}, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("file %s already exists. Use --force to overwrite", path)
+		}
+	}
+	data, err := workflow.MarshalFile(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+func newRetryCmd() *cobra.Command {
+	var (
+		loadWorkflow   bool
+		wait           bool
+		timeout        time.Duration
+		waitingTimeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retry <execution-id>...",
+		Short: "Retry failed executions",
+		Long: `Retry one or more failed executions.
+
+With --wait, a status table is shown that refreshes until every
+retried execution has finished or --timeout elapses. Executions paused
+at a Wait node end the wait after --waiting-timeout; the command then
+exits with code 3.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := cli.NewClient(cmd)
 			if err != nil {
-				return fmt.Errorf("failed to retry execution: %w", err)
+				return err
+			}
+
+			structured := output.IsStructured(cmd)
+
+			var (
+				retried []*api.Execution
+				failed  int
+			)
+			for _, id := range args {
+				exec, err := client.RetryExecution(id, loadWorkflow)
+				if err != nil {
+					if len(args) == 1 {
+						return fmt.Errorf("failed to retry execution: %w", err)
+					}
+					logging.Warn(fmt.Sprintf("failed to retry execution %s: %v", id, err), "execution", id, "error", err)
+					failed++
+					continue
+				}
+				retried = append(retried, exec)
+				if !structured {
+					fmt.Printf("Retry started for %s. New execution ID: %s\n", id, exec.ID)
+				}
 			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
-			if jsonFlag {
-				return printJSON(exec)
+			if wait && len(retried) > 0 {
+				ids := make([]string, len(retried))
+				for i, exec := range retried {
+					ids[i] = exec.ID
+				}
+				if !structured {
+					fmt.Println()
+				}
+				tracker := progress.NewTracker(client, ids)
+				if structured {
+					tracker.Quiet()
+				}
+				tracker.SetWaitingTimeout(waitingTimeout)
+				retried, err = tracker.Wait(timeout)
+				if err != nil {
+					var waiting *progress.WaitingError
+					if errors.As(err, &waiting) {
+						logging.Info(fmt.Sprintf("Paused awaiting external input. Check on it later with: n8nctl execution view %s", waiting.IDs[0]),
+							"execution", waiting.IDs[0])
+					}
+					if structured {
+						_ = output.Print(cmd, retried)
+					}
+					return err
+				}
+			}
+
+			if structured {
+				if len(args) == 1 && len(retried) == 1 {
+					return output.Print(cmd, retried[0])
+				}
+				return output.Print(cmd, retried)
 			}
 
-			fmt.Printf("Retry started. New execution ID: %s\n", exec.ID)
+			if failed > 0 {
+				return fmt.Errorf("%d of %d retries failed", failed, len(args))
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().BoolVar(&loadWorkflow, "load-workflow", false, "Load the latest workflow version instead of the version at execution time")
+	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait for the retried executions to finish")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Maximum time to wait with --wait (0 = no limit)")
+	cmd.Flags().DurationVar(&waitingTimeout, "waiting-timeout", 30*time.Second, "With --wait, stop waiting for executions paused at a Wait node after this long (0 = no limit)")
 
 	return cmd
 }
 
 func newDeleteCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "delete <execution-id>",
-		Short: "Delete an execution",
-		Args:  cobra.ExactArgs(1),
+		Use:   "delete [execution-id...]",
+		Short: "Delete executions",
+		Long: `Delete one or more executions. Asks for confirmation unless --yes is
+given.
+
+Without arguments, the IDs are read from stdin, one per line; blank lines
+and lines starting with # are skipped. Stdin then can't answer the
+confirmation, so --yes is required. Failures are reported and skipped, so
+the remaining executions are still deleted.`,
+		Example: `  n8nctl execution delete 123
+  n8nctl execution list --status error -o json | jq -r '.data[].id' | n8nctl execution delete --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids := args
+			if len(ids) == 0 {
+				var err error
+				if ids, err = prompt.StdinIDs(); err != nil {
+					return err
+				}
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			question := fmt.Sprintf("Delete execution %s?", ids[0])
+			if len(ids) > 1 {
+				question = fmt.Sprintf("Delete %d executions?", len(ids))
+			}
+			if err := prompt.Confirm(cmd, question); err != nil {
+				return err
+			}
+
+			if len(ids) == 1 {
+				if err := client.DeleteExecution(ids[0]); err != nil {
+					return fmt.Errorf("failed to delete execution: %w", err)
+				}
+				fmt.Println("Execution deleted.")
+				return nil
+			}
+
+			failed := 0
+			for i, id := range ids {
+				counter := fmt.Sprintf("[%d/%d]", i+1, len(ids))
+				if err := client.DeleteExecution(id); err != nil {
+					logging.Error(fmt.Sprintf("%s failed to delete execution %s: %v", counter, id, err), "execution", id, "error", err)
+					failed++
+					continue
+				}
+				fmt.Printf("%s Execution %s deleted.\n", counter, id)
+			}
+
+			fmt.Printf("\n%d execution(s) deleted, %d failed.\n", len(ids)-failed, failed)
+			if failed > 0 {
+				return fmt.Errorf("%d execution(s) could not be deleted", failed)
+			}
+			return nil
+		},
+	}
+}
+
+// exportRecord is one line of 'execution export-all'
+type exportRecord struct {
+	ID           string     `json:"id"`
+	WorkflowID   string     `json:"workflowId"`
+	WorkflowName string     `json:"workflowName,omitempty"`
+	Status       string     `json:"status"`
+	Mode         string     `json:"mode,omitempty"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	StoppedAt    *time.Time `json:"stoppedAt,omitempty"`
+	DurationMs   *int64     `json:"durationMs,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+func newExportAllCmd() *cobra.Command {
+	var (
+		sinceFlag    string
+		format       string
+		workflowID   string
+		projectID    string
+		status       string
+		out          string
+		resolveNames bool
+		errorDetails bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export-all",
+		Short: "Stream execution metadata as NDJSON for log ingestion",
+		Long: `Write one JSON record per execution and line (NDJSON): ID, workflow,
+status, mode, start and stop time, duration in milliseconds, and error.
+Pages are fetched and written one at a time, newest first, so memory use
+stays flat however many executions there are.
+
+--since stops at the first execution started before the given point in
+time, as a duration (24h, 7d) or a date (2006-01-02, or RFC 3339).
+Without it, all executions are exported.
+
+The execution list doesn't carry error messages. --error-details fetches
+each failed execution to add the message of the node that failed, which
+costs an API call per failure; --resolve-names costs one per workflow.`,
+		Example: `  n8nctl exec export-all --since 24h --format ndjson > executions.ndjson
+  n8nctl exec export-all --since 1h --status error --error-details --out /var/log/n8n/executions.ndjson`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			if format != "ndjson" {
+				return fmt.Errorf("unsupported --format %q (only ndjson is supported)", format)
+			}
+
+			var since time.Time
+			if sinceFlag != "" {
+				var err error
+				if since, err = parseSince(sinceFlag); err != nil {
+					return err
+				}
+			}
+
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
+			if workflowID, projectID, err = resolveFilters(client, workflowID, projectID); err != nil {
+				return err
+			}
 
-			if err := client.DeleteExecution(args[0]); err != nil {
-				return fmt.Errorf("failed to delete execution: %w", err)
+			w := os.Stdout
+			if out != "" {
+				f, err := os.Create(out)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", out, err)
+				}
+				defer f.Close()
+				w = f
 			}
+			buf := bufio.NewWriter(w)
+			enc := json.NewEncoder(buf)
+
+			workflowNames := make(map[string]string)
+			page := api.ListExecutionsOptions{WorkflowID: workflowID, ProjectID: projectID, Status: status, Limit: sincePageSize}
+			written := 0
+			for {
+				batch, err := client.ListExecutions(page)
+				if err != nil {
+					return fmt.Errorf("failed to list executions: %w", err)
+				}
 
-			fmt.Println("Execution deleted.")
+				done := batch.NextCursor == ""
+				for _, exec := range batch.Data {
+					if !since.IsZero() && exec.StartedAt != nil && exec.StartedAt.Before(since) {
+						done = true
+						break
+					}
+
+					record := exportRecord{
+						ID:         exec.ID,
+						WorkflowID: exec.WorkflowID,
+						Status:     exec.Status,
+						Mode:       exec.Mode,
+						StartedAt:  exec.StartedAt,
+						StoppedAt:  exec.StoppedAt,
+						Error:      exec.Error,
+					}
+					if d, ok := exec.Duration(); ok {
+						ms := d.Milliseconds()
+						record.DurationMs = &ms
+					}
+					if resolveNames {
+						name, ok := workflowNames[exec.WorkflowID]
+						if !ok {
+							if wf, err := client.GetWorkflow(exec.WorkflowID); err == nil {
+								name = wf.Name
+							}
+							workflowNames[exec.WorkflowID] = name
+						}
+						record.WorkflowName = name
+					}
+					if errorDetails && record.Error == "" && (exec.Status == "error" || exec.Status == "crashed") {
+						if full, err := client.GetExecution(exec.ID, true); err == nil {
+							record.Error = executionErrorMessage(full.Data)
+						}
+					}
+
+					if err := enc.Encode(record); err != nil {
+						return fmt.Errorf("failed to write record: %w", err)
+					}
+					written++
+				}
+				// Hand each page to the consumer before fetching the next
+				if err := buf.Flush(); err != nil {
+					return fmt.Errorf("failed to write records: %w", err)
+				}
+
+				if done {
+					break
+				}
+				page.Cursor = batch.NextCursor
+			}
+
+			if out != "" {
+				if err := w.Close(); err != nil {
+					return fmt.Errorf("failed to write %s: %w", out, err)
+				}
+				logging.Info(fmt.Sprintf("Exported %d execution(s) to %s", written, out), "executions", written, "file", out)
+			}
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&sinceFlag, "since", "", "Only export executions started after this (e.g. 24h, 7d, 2006-01-02)")
+	cmd.Flags().StringVar(&format, "format", "ndjson", "Record format (ndjson)")
+	cmd.Flags().StringVar(&workflowID, "workflow", "", "Filter by workflow ID or name")
+	cmd.Flags().StringVar(&projectID, "project", "", "Filter by project ID or name")
+	cmd.Flags().StringVar(&status, "status", "", "Filter by status (running, success, error, waiting)")
+	cmd.Flags().StringVar(&out, "out", "", "Write to this file instead of stdout")
+	cmd.Flags().BoolVar(&resolveNames, "resolve-names", false, "Add workflow names (one API call per workflow)")
+	cmd.Flags().BoolVar(&errorDetails, "error-details", false, "Fetch failed executions for their error message (one API call each)")
+
+	return cmd
 }
 
-func truncate(s string, max int) string {
-	if len(s) <= max {
-		return s
+// resolveFilters resolves the --workflow and --project filters, each an
+// ID, name, or (for workflows) ID prefix, to IDs. Empty filters stay empty.
+func resolveFilters(client *api.Client, workflowRef, projectRef string) (string, string, error) {
+	var workflowID, projectID string
+	if workflowRef != "" {
+		id, err := workflow.ResolveID(client, workflowRef)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to find workflow: %w", err)
+		}
+		workflowID = id
+	}
+	if projectRef != "" {
+		project, err := workflow.ResolveProject(client, projectRef)
+		if err != nil {
+			return "", "", err
+		}
+		projectID = project.ID
 	}
-	return s[:max-3] + "..."
+	return workflowID, projectID, nil
+}
+
+// executionErrorMessage returns the error of a failed execution from its
+// data: the workflow-level error, or else that of the last executed node
+func executionErrorMessage(data map[string]interface{}) string {
+	resultData, ok := data["resultData"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if errObj, ok := resultData["error"].(map[string]interface{}); ok {
+		if msg, _ := errObj["message"].(string); msg != "" {
+			return msg
+		}
+	}
+	lastNode, _ := resultData["lastNodeExecuted"].(string)
+	return nodeErrorMessage(resultData, lastNode)
 }
 
 func formatTime(t *time.Time) string {
@@ -464,8 +1743,24 @@ func formatTime(t *time.Time) string {
 	return t.Local().Format("2006-01-02 15:04:05")
 }
 
-func printJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(v)
+// formatDuration returns the run time of a finished execution, or an empty
+// string if it is unknown
+func formatDuration(exec api.Execution) string {
+	d, ok := exec.Duration()
+	if !ok {
+		return ""
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+// elapsed returns how long an execution ran, measuring unfinished
+// executions up to now
+func elapsed(exec api.Execution) (time.Duration, bool) {
+	if d, ok := exec.Duration(); ok {
+		return d, true
+	}
+	if exec.StartedAt == nil {
+		return 0, false
+	}
+	return time.Since(*exec.StartedAt), true
 }
@@ -1,15 +1,15 @@
 package project
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/spf13/cobra"
 
-	"github.com/enthus-appdev/n8n-cli/internal/api"
-	"github.com/enthus-appdev/n8n-cli/internal/config"
+	"github.com/enthus-appdev/n8n-cli/internal/cli"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+	"github.com/enthus-appdev/n8n-cli/internal/output"
+	"github.com/enthus-appdev/n8n-cli/internal/pagestate"
+	"github.com/enthus-appdev/n8n-cli/internal/prompt"
 )
 
 func NewProjectCmd() *cobra.Command {
@@ -19,48 +19,74 @@ func NewProjectCmd() *cobra.Command {
 		Long:  `List and manage n8n projects.`,
 	}
 
-	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(output.Pageable(newListCmd()))
 
 	return cmd
 }
 
-func getClient() (*api.Client, error) {
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, fmt.Errorf("not configured. Run 'n8n config init' first")
-	}
-
-	instance, err := cfg.GetCurrentInstance()
-	if err != nil {
-		return nil, err
-	}
-
-	return api.NewClient(instance.URL, instance.APIKey), nil
-}
-
 func newListCmd() *cobra.Command {
 	var (
-		limit  int
-		cursor string
+		limit      int
+		cursor     string
+		resumeFile string
+		saveCursor string
+		selectID   bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all projects",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			flags := pagestate.Flags{Limit: limit, LimitSet: cmd.Flags().Changed("limit"), Cursor: cursor, Resume: resumeFile, SaveCursor: saveCursor}
+			if err := flags.Check(); err != nil {
+				return err
+			}
+
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
+			filterHash := pagestate.FilterHash([]interface{}{limit})
+			if resumeFile != "" {
+				saved, complete, err := pagestate.Resume(resumeFile, "project list", filterHash)
+				if err != nil {
+					return err
+				}
+				if complete {
+					logging.Info(fmt.Sprintf("All results were already listed. Delete %s to start over.", resumeFile), "resumeFile", resumeFile)
+					return nil
+				}
+				cursor = saved
+			}
+
 			result, err := client.ListProjects(limit, cursor)
 			if err != nil {
 				return fmt.Errorf("failed to list projects: %w", err)
 			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
-			if jsonFlag {
-				return printJSON(result)
+			if saveCursor != "" {
+				state := &pagestate.State{Command: "project list", FilterHash: filterHash, Cursor: result.NextCursor}
+				if err := pagestate.Save(saveCursor, state); err != nil {
+					return err
+				}
+			}
+
+			if selectID {
+				options := make([]prompt.Option, len(result.Data))
+				for i, p := range result.Data {
+					options[i] = prompt.Option{ID: p.ID, Label: fmt.Sprintf("%-18s  %s", p.ID, p.Name)}
+				}
+				id, err := prompt.Select(options)
+				if err != nil {
+					return err
+				}
+				fmt.Println(id)
+				return nil
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, result)
 			}
 
 			if len(result.Data) == 0 {
@@ -68,28 +94,25 @@ func newListCmd() *cobra.Command {
 				return nil
 			}
 
-			fmt.Printf("%-18s  %-10s  %s\n", "ID", "TYPE", "NAME")
-			fmt.Printf("%-18s  %-10s  %s\n", strings.Repeat("-", 18), strings.Repeat("-", 10), strings.Repeat("-", 40))
+			table := output.NewTable(cmd, "ID", "TYPE", "NAME")
 			for _, p := range result.Data {
-				fmt.Printf("%-18s  %-10s  %s\n", p.ID, p.Type, p.Name)
+				table.AddRow(p.ID, p.Type, p.Name)
 			}
+			table.Print()
 
 			if result.NextCursor != "" {
-				fmt.Printf("\nMore results available. Use --cursor %s to continue.\n", result.NextCursor)
+				table.Footer("More results available. Use --cursor %s to continue.", result.NextCursor)
 			}
 
 			return nil
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of projects to return")
+	cmd.Flags().IntVar(&limit, "limit", 100, "Maximum number of projects to return (0 = unlimited)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor for next page")
+	cmd.Flags().StringVar(&resumeFile, "resume", "", "Continue from the cursor saved in this file by --save-cursor")
+	cmd.Flags().StringVar(&saveCursor, "save-cursor", "", "Save the next cursor to this file for a later --resume")
+	cmd.Flags().BoolVar(&selectID, "select", false, "Pick a project interactively and print its ID")
 
 	return cmd
 }
-
-func printJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(v)
-}
@@ -2,22 +2,50 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"os/user"
+	"runtime"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/cli"
 	configcmd "github.com/enthus-appdev/n8n-cli/internal/cmd/config"
+	credentialcmd "github.com/enthus-appdev/n8n-cli/internal/cmd/credential"
 	executioncmd "github.com/enthus-appdev/n8n-cli/internal/cmd/execution"
 	projectcmd "github.com/enthus-appdev/n8n-cli/internal/cmd/project"
 	variablecmd "github.com/enthus-appdev/n8n-cli/internal/cmd/variable"
 	workflowcmd "github.com/enthus-appdev/n8n-cli/internal/cmd/workflow"
+	"github.com/enthus-appdev/n8n-cli/internal/config"
+	"github.com/enthus-appdev/n8n-cli/internal/dotenv"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+	"github.com/enthus-appdev/n8n-cli/internal/output"
+	"github.com/enthus-appdev/n8n-cli/internal/progress"
 )
 
 var (
-	version    string
-	jsonOutput bool
+	version      string
+	jsonOutput   bool
+	outputFormat string
+	showStats    bool
+	callStats    *api.CallStats
+	auditPath    string
+	auditLog     *api.AuditLog
+	pager        *output.Pager
+
+	envFile         string
+	envFileOverride bool
+
+	logFormat string
+	logLevel  string
 )
 
 var rootCmd = &cobra.Command{
@@ -29,38 +57,390 @@ It allows you to list, view, pull, push, and execute workflows
 directly from your terminal - perfect for version control,
 automation, and LLM-assisted workflow development.`,
 	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := output.Parse(outputFormat); err != nil {
+			return err
+		}
+		if err := logging.Setup(os.Stderr, logFormat, logLevel); err != nil {
+			return err
+		}
+		if shadowingAlias != "" {
+			logging.Warn(fmt.Sprintf("alias %q conflicts with a built-in command and is ignored", shadowingAlias), "alias", shadowingAlias)
+			shadowingAlias = ""
+		}
+		// Before anything reads the environment. Built-in aliases run
+		// this hook twice, which is harmless.
+		if envFile != "" {
+			if err := dotenv.Load(envFile, envFileOverride); err != nil {
+				return err
+			}
+		} else if envFileOverride {
+			return fmt.Errorf("--env-file-override requires --env-file")
+		}
+		if showStats {
+			callStats = api.NewCallStats()
+			cli.RecordStats(callStats)
+		}
+		if auditLog == nil {
+			if err := startAuditLog(); err != nil {
+				return err
+			}
+		}
+		if auditLog != nil {
+			auditLog.Command = cmd.CommandPath()
+		}
+		if pager == nil {
+			pager = output.StartPager(cmd)
+		}
+		return nil
+	},
+}
+
+// startAuditLog makes API clients record mutating requests if --audit-log
+// or the auditLog setting names a file
+func startAuditLog() error {
+	// A broken config is reported by the commands that need it
+	var cfg *config.Config
+	if config.Exists() {
+		if loaded, err := config.Load(); err == nil {
+			cfg = loaded
+		}
+	}
+
+	path := auditPath
+	if path == "" && cfg != nil {
+		var err error
+		if path, err = cfg.AuditLogPath(); err != nil {
+			return err
+		}
+	}
+	if path == "" {
+		return nil
+	}
+
+	auditLog = api.NewAuditLog(path)
+	if u, err := user.Current(); err == nil {
+		auditLog.User = u.Username
+	}
+	if cfg != nil {
+		for name, instance := range cfg.Instances {
+			if u, err := url.Parse(instance.URL); err == nil {
+				auditLog.Instances[u.Host] = name
+			}
+		}
+	}
+	cli.RecordAudit(auditLog)
+	return nil
+}
+
+// builtinAliases are hidden top-level shortcuts for frequently used commands
+var builtinAliases = map[string][]string{
+	"ls":  {"workflow", "list"},
+	"run": {"workflow", "run"},
 }
 
 func Execute(ver string) error {
 	version = ver
-	return rootCmd.Execute()
+	rootCmd.SetArgs(expandAliases(os.Args[1:]))
+	err := rootCmd.Execute()
+	pager.Close()
+	if callStats != nil {
+		callStats.Print(os.Stderr)
+	}
+	if api.IsUnauthorized(err) {
+		printAuthHint()
+	}
+	return err
+}
+
+// ExitCode maps an error returned by Execute to the process exit code.
+// Executions left paused at a Wait node by run or retry --wait exit with
+// 3 so that scripts can tell them apart from failures.
+func ExitCode(err error) int {
+	var waiting *progress.WaitingError
+	if errors.As(err, &waiting) {
+		return 3
+	}
+	return 1
+}
+
+// printAuthHint explains a rejected API key and how to replace it. Without
+// --instance, keys from N8N_API_KEY take precedence over the config file,
+// so the hint points at the environment then.
+func printAuthHint() {
+	name, _ := rootCmd.PersistentFlags().GetString("instance")
+	if name == "" && os.Getenv(cli.EnvAPIKey) != "" {
+		target := "the current instance"
+		if url := os.Getenv(cli.EnvURL); url != "" {
+			target = url
+		}
+		logging.Warn(fmt.Sprintf("Authentication failed for %s with the API key from %s. The key may be invalid or expired.\n"+
+			"Set %s to a valid key.", target, cli.EnvAPIKey, cli.EnvAPIKey), "env", cli.EnvAPIKey)
+		return
+	}
+
+	if name == "" {
+		name = "<name>"
+		if cfg, err := config.Load(); err == nil && cfg.CurrentInstance != "" {
+			name = cfg.CurrentInstance
+		}
+	}
+	logging.Warn(fmt.Sprintf("Authentication failed for instance '%s'. The API key may be invalid or expired.\n"+
+		"Update it with: n8nctl config init --name %s --api-key <key> --force", name, name), "instance", name)
+}
+
+// shadowingAlias is an alias expandAliases ignored because it has the
+// name of a command. The warning waits for the logger to be set up.
+var shadowingAlias string
+
+// expandAliases replaces a leading alias from the config with the
+// arguments it stands for. Aliases that would shadow a real command are
+// ignored with a warning, see shadowingAlias.
+func expandAliases(args []string) []string {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || !config.Exists() {
+		return args
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return args
+	}
+
+	expansion, ok := cfg.ActiveAliases()[args[0]]
+	if !ok {
+		return args
+	}
+	if isCommandName(args[0]) {
+		shadowingAlias = args[0]
+		return args
+	}
+
+	expanded := strings.Fields(expansion)
+	if len(expanded) == 0 {
+		return args
+	}
+	return append(expanded, args[1:]...)
+}
+
+// isCommandName reports whether name is a top-level command or alias
+func isCommandName(name string) bool {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// newBuiltinAliasCmd creates a hidden command that re-runs the CLI with
+// the alias replaced by its target command
+func newBuiltinAliasCmd(name string, target []string) *cobra.Command {
+	return &cobra.Command{
+		Use:                name,
+		Short:              "Alias for " + strings.Join(target, " "),
+		Hidden:             true,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rootCmd.SetArgs(append(append([]string{}, target...), args...))
+			err := rootCmd.Execute()
+			if err != nil {
+				// The nested run already reported the error
+				rootCmd.SilenceErrors = true
+			}
+			return err
+		},
+	}
 }
 
 func init() {
-	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", string(output.Table), "Output format: table, json, yaml, or jsonl")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output in JSON format (deprecated, same as --output json)")
+	rootCmd.PersistentFlags().String("instance", "", "Instance to use instead of the current one")
+	rootCmd.PersistentFlags().StringArray("header", nil, "Extra HTTP header for API requests as key=value (can be repeated)")
+	rootCmd.PersistentFlags().BoolVar(&showStats, "stats", false, "Print API call counts and timings per endpoint to stderr")
+	rootCmd.PersistentFlags().StringVar(&auditPath, "audit-log", "", "Append mutating API requests to this file as JSON lines")
+	rootCmd.PersistentFlags().Bool("no-keepalive", false, "Open a new connection for every API request (for proxies that break reused connections)")
+	rootCmd.PersistentFlags().StringVar(&envFile, "env-file", "", "Load environment variables from this dotenv file (KEY=VALUE lines)")
+	rootCmd.PersistentFlags().BoolVar(&envFileOverride, "env-file-override", false, "Let --env-file replace variables that are already set")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logging.Text, "Format of progress and warning messages on stderr: text or json")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Least severe stderr messages to show: debug, info, warn, or error")
+	rootCmd.PersistentFlags().Bool("plain", false, "Print tables as tab-separated rows without a header, for scripts")
+	rootCmd.PersistentFlags().Bool("no-pager", false, "Don't page long output through $PAGER")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "Skip confirmation prompts of destructive commands")
 
 	rootCmd.AddCommand(configcmd.NewConfigCmd())
 	rootCmd.AddCommand(workflowcmd.NewWorkflowCmd())
 	rootCmd.AddCommand(executioncmd.NewExecutionCmd())
 	rootCmd.AddCommand(projectcmd.NewProjectCmd())
+	rootCmd.AddCommand(credentialcmd.NewCredentialCmd())
 	rootCmd.AddCommand(variablecmd.NewVariableCmd())
 	rootCmd.AddCommand(newVersionCmd())
+
+	for name, target := range builtinAliases {
+		rootCmd.AddCommand(newBuiltinAliasCmd(name, target))
+	}
 }
 
+// buildInfo describes the running binary
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"goVersion"`
+	Platform  string `json:"platform"`
+	// Latest and UpdateAvailable are set by version --check
+	Latest          string `json:"latest,omitempty"`
+	UpdateAvailable *bool  `json:"updateAvailable,omitempty"`
+}
+
+// SetBuildInfo records the commit and build date passed to release builds
+// with -ldflags. Builds without them fall back to the VCS information Go
+// embeds.
+func SetBuildInfo(commit, date string) {
+	buildCommit, buildDate = commit, date
+}
+
+var buildCommit, buildDate string
+
+// releasesURL is the GitHub API endpoint for the latest release
+const releasesURL = "https://api.github.com/repos/enthus-appdev/n8n-cli/releases/latest"
+
 func newVersionCmd() *cobra.Command {
-	return &cobra.Command{
+	var check bool
+
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the version number",
-		Run: func(cmd *cobra.Command, args []string) {
+		Long: `Print the version, commit, build date, and Go version of this build.
+
+--check asks GitHub for the latest release and reports whether it is newer.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			commit, date := vcsInfo()
-			if jsonOutput {
-				out, _ := json.Marshal(map[string]string{"version": version, "commit": commit, "date": date})
-				fmt.Println(string(out))
-			} else {
-				fmt.Printf("n8n-cli %s\ncommit: %s\nbuilt:  %s\n", version, commit, date)
+			info := buildInfo{
+				Version:   version,
+				Commit:    firstSet(buildCommit, commit),
+				Date:      firstSet(buildDate, date),
+				GoVersion: runtime.Version(),
+				Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+			}
+
+			if check {
+				latest, err := latestRelease()
+				if err != nil {
+					return fmt.Errorf("failed to check for updates: %w", err)
+				}
+				info.Latest = latest
+				if isRelease(version) {
+					newer := compareVersions(latest, version) > 0
+					info.UpdateAvailable = &newer
+				}
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, info)
+			}
+			fmt.Printf("n8n-cli %s\ncommit: %s\nbuilt:  %s\ngo:     %s (%s)\n",
+				info.Version, info.Commit, info.Date, info.GoVersion, info.Platform)
+			if check {
+				switch {
+				case info.UpdateAvailable == nil:
+					fmt.Printf("\nLatest release is %s; this is a development build.\n", info.Latest)
+				case *info.UpdateAvailable:
+					fmt.Printf("\nA newer version is available: %s\n", info.Latest)
+					fmt.Println("See https://github.com/enthus-appdev/n8n-cli/releases")
+				default:
+					fmt.Println("\nThis is the latest version.")
+				}
 			}
+			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&check, "check", false, "Check GitHub for a newer release")
+
+	return cmd
+}
+
+// latestRelease returns the tag of the latest GitHub release
+func latestRelease() (string, error) {
+	req, err := http.NewRequest(http.MethodGet, releasesURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "n8nctl/"+version)
+
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub answered %s", resp.Status)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("failed to parse release: %w", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("release has no tag")
+	}
+	return release.TagName, nil
+}
+
+// isRelease reports whether v looks like a release version such as 1.4.0
+// or v1.4.0, as opposed to "dev"
+func isRelease(v string) bool {
+	_, ok := parseVersion(v)
+	return ok
+}
+
+// compareVersions compares two release versions by their numeric parts
+// and returns -1, 0, or 1. Pre-release suffixes are ignored.
+func compareVersions(a, b string) int {
+	pa, _ := parseVersion(a)
+	pb, _ := parseVersion(b)
+	for i := 0; i < 3; i++ {
+		switch {
+		case pa[i] > pb[i]:
+			return 1
+		case pa[i] < pb[i]:
+			return -1
+		}
+	}
+	return 0
+}
+
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+func firstSet(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
 }
 
 func vcsInfo() (commit, date string) {
@@ -90,23 +470,36 @@ func vcsInfo() (commit, date string) {
 	return
 }
 
-// IsJSONOutput returns whether JSON output is enabled
+// IsJSONOutput returns whether structured output is enabled
 func IsJSONOutput() bool {
-	return jsonOutput
+	return rootFormat() != output.Table
 }
 
-// PrintJSON outputs data as formatted JSON
+// PrintJSON outputs data in the selected structured format
 func PrintJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(v)
+	format := rootFormat()
+	if format == output.Table {
+		format = output.JSON
+	}
+	return output.Write(os.Stdout, format, v)
 }
 
 // PrintError outputs an error in the appropriate format
 func PrintError(err error) {
-	if jsonOutput {
+	if IsJSONOutput() {
 		_ = PrintJSON(map[string]string{"error": err.Error()})
 	} else {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 	}
 }
+
+// rootFormat returns the output format selected by the global flags
+func rootFormat() output.Format {
+	if jsonOutput {
+		return output.JSON
+	}
+	if f, err := output.Parse(outputFormat); err == nil {
+		return f
+	}
+	return output.Table
+}
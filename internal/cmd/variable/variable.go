@@ -1,15 +1,16 @@
 package variable
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/enthus-appdev/n8n-cli/internal/api"
-	"github.com/enthus-appdev/n8n-cli/internal/config"
+	"github.com/enthus-appdev/n8n-cli/internal/cli"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+	"github.com/enthus-appdev/n8n-cli/internal/output"
+	"github.com/enthus-appdev/n8n-cli/internal/prompt"
 )
 
 func NewVariableCmd() *cobra.Command {
@@ -22,7 +23,7 @@ func NewVariableCmd() *cobra.Command {
 
 	cmd.PersistentFlags().String("project", "", "Project ID (omit for global variables)")
 
-	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(output.Pageable(newListCmd()))
 	cmd.AddCommand(newGetCmd())
 	cmd.AddCommand(newCreateCmd())
 	cmd.AddCommand(newUpdateCmd())
@@ -31,26 +32,12 @@ func NewVariableCmd() *cobra.Command {
 	return cmd
 }
 
-func getClient() (*api.Client, error) {
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, fmt.Errorf("not configured. Run 'n8nctl config init' first")
-	}
-
-	instance, err := cfg.GetCurrentInstance()
-	if err != nil {
-		return nil, err
-	}
-
-	return api.NewClient(instance.URL, instance.APIKey), nil
-}
-
 func newListCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all variables",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -61,9 +48,8 @@ func newListCmd() *cobra.Command {
 				return fmt.Errorf("failed to list variables: %w", err)
 			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
-			if jsonFlag {
-				return printJSON(vars)
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, vars)
 			}
 
 			if len(vars) == 0 {
@@ -79,27 +65,22 @@ func newListCmd() *cobra.Command {
 				}
 			}
 
+			var table *output.TableWriter
 			if hasProject {
-				fmt.Printf("%-8s  %-18s  %-40s  %s\n", "ID", "PROJECT", "KEY", "VALUE")
-				fmt.Printf("%-8s  %-18s  %-40s  %s\n",
-					strings.Repeat("-", 8), strings.Repeat("-", 18),
-					strings.Repeat("-", 40), strings.Repeat("-", 50))
+				table = output.NewTable(cmd, "ID", "PROJECT", "KEY", "VALUE")
 			} else {
-				fmt.Printf("%-8s  %-40s  %s\n", "ID", "KEY", "VALUE")
-				fmt.Printf("%-8s  %-40s  %s\n", strings.Repeat("-", 8), strings.Repeat("-", 40), strings.Repeat("-", 50))
+				table = output.NewTable(cmd, "ID", "KEY", "VALUE")
 			}
 
 			for _, v := range vars {
-				value := v.Value
-				if len(value) > 50 {
-					value = value[:47] + "..."
-				}
+				value := output.Truncate(v.Value, 50)
 				if hasProject {
-					fmt.Printf("%-8s  %-18s  %-40s  %s\n", v.ID, v.ProjectID, v.Key, value)
+					table.AddRow(v.ID, v.ProjectID, v.Key, value)
 				} else {
-					fmt.Printf("%-8s  %-40s  %s\n", v.ID, v.Key, value)
+					table.AddRow(v.ID, v.Key, value)
 				}
 			}
+			table.Print()
 
 			return nil
 		},
@@ -114,7 +95,7 @@ func newGetCmd() *cobra.Command {
 		Short: "Get a variable by key",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -128,9 +109,8 @@ func newGetCmd() *cobra.Command {
 			key := args[0]
 			for _, v := range vars {
 				if v.Key == key {
-					jsonFlag, _ := cmd.Flags().GetBool("json")
-					if jsonFlag {
-						return printJSON(v)
+					if output.IsStructured(cmd) {
+						return output.Print(cmd, v)
 					}
 					fmt.Println(v.Value)
 					return nil
@@ -155,7 +135,7 @@ or via the --value flag. The flag form is useful for values containing
 special shell characters (e.g. n8nctl var create key --value 'b!xyz').`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -197,7 +177,7 @@ or via the --value flag. The flag form is useful for values containing
 special shell characters (e.g. n8nctl var update key --value 'b!xyz').`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
@@ -240,43 +220,116 @@ special shell characters (e.g. n8nctl var update key --value 'b!xyz').`,
 }
 
 func newDeleteCmd() *cobra.Command {
+	var (
+		prefix string
+		dryRun bool
+	)
+
 	cmd := &cobra.Command{
-		Use:   "delete <key>",
-		Short: "Delete a variable by key",
-		Args:  cobra.ExactArgs(1),
+		Use:   "delete [key...]",
+		Short: "Delete variables by key or prefix",
+		Long: `Delete one or more variables by key, or all variables whose key starts
+with --prefix. Asks for confirmation unless --yes is given; --dry-run
+only lists what would be deleted.
+
+Failures are reported and skipped, so the remaining variables are still
+deleted.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			if len(args) == 0 && prefix == "" {
+				return fmt.Errorf("specify at least one key or --prefix")
+			}
+
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
-			// Resolve key to ID within the given project scope.
+			// Resolve keys to IDs within the given project scope.
 			projectID, _ := cmd.Flags().GetString("project")
 			vars, err := client.ListVariables(0, "", projectID)
 			if err != nil {
 				return fmt.Errorf("failed to list variables: %w", err)
 			}
 
-			key := args[0]
+			byKey := make(map[string]api.Variable, len(vars))
 			for _, v := range vars {
-				if v.Key == key {
-					if err := client.DeleteVariable(v.ID); err != nil {
-						return fmt.Errorf("failed to delete variable: %w", err)
+				byKey[v.Key] = v
+			}
+
+			var targets []api.Variable
+			var missing []string
+			selected := make(map[string]bool)
+			for _, key := range args {
+				v, ok := byKey[key]
+				if !ok {
+					missing = append(missing, key)
+					continue
+				}
+				if !selected[key] {
+					selected[key] = true
+					targets = append(targets, v)
+				}
+			}
+			if prefix != "" {
+				for _, v := range vars {
+					if strings.HasPrefix(v.Key, prefix) && !selected[v.Key] {
+						selected[v.Key] = true
+						targets = append(targets, v)
 					}
-					fmt.Printf("Deleted variable: %s\n", key)
-					return nil
 				}
 			}
 
-			return fmt.Errorf("variable %q not found", key)
+			for _, key := range missing {
+				logging.Warn(fmt.Sprintf("variable %q not found", key), "variable", key)
+			}
+			if len(targets) == 0 {
+				if len(missing) > 0 {
+					return fmt.Errorf("no matching variables found")
+				}
+				fmt.Println("No matching variables found.")
+				return nil
+			}
+
+			if dryRun {
+				fmt.Printf("Would delete %d variable(s):\n", len(targets))
+				for _, v := range targets {
+					fmt.Printf("  %s\n", v.Key)
+				}
+				return nil
+			}
+
+			keys := make([]string, len(targets))
+			for i, v := range targets {
+				keys[i] = v.Key
+			}
+			if err := prompt.Confirm(cmd, fmt.Sprintf("Delete %d variable(s): %s?", len(targets), strings.Join(keys, ", "))); err != nil {
+				return err
+			}
+
+			failed := len(missing)
+			deleted := 0
+			for _, v := range targets {
+				if err := client.DeleteVariable(v.ID); err != nil {
+					logging.Error(fmt.Sprintf("failed to delete variable %s: %v", v.Key, err), "variable", v.Key, "error", err)
+					failed++
+					continue
+				}
+				deleted++
+				fmt.Printf("Deleted variable: %s\n", v.Key)
+			}
+
+			if len(targets) > 1 || failed > 0 {
+				fmt.Printf("\nDeleted %d variable(s), %d failed.\n", deleted, failed)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d variable(s) could not be deleted", failed)
+			}
+			return nil
 		},
 	}
 
-	return cmd
-}
+	cmd.Flags().StringVar(&prefix, "prefix", "", "Delete all variables whose key starts with this prefix")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the variables that would be deleted without deleting them")
 
-func printJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(v)
+	return cmd
 }
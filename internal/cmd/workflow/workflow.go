@@ -1,16 +1,31 @@
 package workflow
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/cli"
 	"github.com/enthus-appdev/n8n-cli/internal/config"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+	"github.com/enthus-appdev/n8n-cli/internal/output"
+	"github.com/enthus-appdev/n8n-cli/internal/pagestate"
+	"github.com/enthus-appdev/n8n-cli/internal/progress"
+	"github.com/enthus-appdev/n8n-cli/internal/prompt"
+	"github.com/enthus-appdev/n8n-cli/internal/redact"
 	"github.com/enthus-appdev/n8n-cli/internal/workflow"
 )
 
@@ -22,52 +37,113 @@ func NewWorkflowCmd() *cobra.Command {
 		Long:    `List, view, pull, push, and manage n8n workflows.`,
 	}
 
-	cmd.AddCommand(newListCmd())
-	cmd.AddCommand(newViewCmd())
+	cmd.AddCommand(output.Pageable(newListCmd()))
+	cmd.AddCommand(output.Pageable(newViewCmd()))
 	cmd.AddCommand(newPullCmd())
 	cmd.AddCommand(newPushCmd())
 	cmd.AddCommand(newRunCmd())
+	cmd.AddCommand(newPatchCmd())
+	cmd.AddCommand(newValidateCmd())
+	cmd.AddCommand(newVerifyCmd())
+	cmd.AddCommand(newCompareCmd())
+	cmd.AddCommand(newCopyToCmd())
+	cmd.AddCommand(newManifestCmd())
+	cmd.AddCommand(newWebhooksCmd())
 	cmd.AddCommand(newActivateCmd())
 	cmd.AddCommand(newDeactivateCmd())
+	cmd.AddCommand(newArchiveCmd(true))
+	cmd.AddCommand(newArchiveCmd(false))
 	cmd.AddCommand(newTransferCmd())
+	cmd.AddCommand(newMoveCmd())
+	cmd.AddCommand(newTagsCmd())
+	cmd.AddCommand(output.Pageable(newHistoryCmd()))
+	cmd.AddCommand(newRestoreCmd())
 
 	return cmd
 }
 
-func getClient() (*api.Client, error) {
-	cfg, err := config.Load()
-	if err != nil {
-		return nil, fmt.Errorf("not configured. Run 'n8n config init' first")
-	}
-
-	instance, err := cfg.GetCurrentInstance()
-	if err != nil {
-		return nil, err
-	}
-
-	return api.NewClient(instance.URL, instance.APIKey), nil
-}
-
 func newListCmd() *cobra.Command {
 	var (
-		active    bool
-		inactive  bool
-		tags      []string
-		limit     int
-		cursor    string
-		projectID string
-		name      string
+		active     bool
+		inactive   bool
+		tags       []string
+		limit      int
+		cursor     string
+		resumeFile string
+		saveCursor string
+		projectID  string
+		name       string
+		details    bool
+		selectID   bool
+		archived   bool
+		withArch   bool
+		duplicates bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all workflows",
+		Long: `List workflows, optionally filtered by state, tag, project, or name.
+
+--details adds two audit columns: whether the workflow has an enabled
+trigger node (workflows without one only run as sub-workflows or by
+hand), and its execution order setting, where v0 is the legacy order of
+workflows created before n8n 1.0. Workflows whose nodes aren't part of
+the list response are fetched one by one, which costs an API call each.
+
+Archived workflows are hidden unless --include-archived is given;
+--archived lists only those. Instances older than the archiving feature
+report no workflow as archived, so both flags change nothing there. The
+filter is applied to each page after fetching, so a page may hold fewer
+workflows than --limit.
+
+--select lists the workflows on the terminal for picking one, and prints
+only the chosen ID:
+
+  n8nctl workflow view "$(n8nctl workflow list --select)"
+
+--duplicates lists only the names used by more than one workflow, with
+the IDs of each. n8n allows duplicate names, but they make name lookups
+ambiguous; see --require-unique-name of 'n8nctl workflow push'. All
+matching workflows are fetched, so the paging flags don't apply.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			if duplicates {
+				for _, flag := range []string{"limit", "cursor", "resume", "save-cursor", "details", "select"} {
+					if cmd.Flags().Changed(flag) {
+						return fmt.Errorf("--duplicates cannot be combined with --%s", flag)
+					}
+				}
+			}
+
+			flags := pagestate.Flags{Limit: limit, LimitSet: cmd.Flags().Changed("limit"), Cursor: cursor, Resume: resumeFile, SaveCursor: saveCursor}
+			if err := flags.Check(); err != nil {
+				return err
+			}
+
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
+			if archived && withArch {
+				return fmt.Errorf("--archived and --include-archived cannot be combined")
+			}
+
+			// The archive filter runs on the client, but changes which
+			// workflows a saved position has listed all the same
+			filterHash := pagestate.FilterHash([]interface{}{active, inactive, tags, projectID, name, limit, archived, withArch})
+			if resumeFile != "" {
+				saved, complete, err := pagestate.Resume(resumeFile, "workflow list", filterHash)
+				if err != nil {
+					return err
+				}
+				if complete {
+					logging.Info(fmt.Sprintf("All results were already listed. Delete %s to start over.", resumeFile), "resumeFile", resumeFile)
+					return nil
+				}
+				cursor = saved
+			}
+
 			opts := api.ListWorkflowsOptions{
 				Limit:     limit,
 				Tags:      tags,
@@ -86,10 +162,39 @@ func newListCmd() *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to list workflows: %w", err)
 			}
+			if !withArch {
+				result.Data = filterArchived(result.Data, archived)
+			}
+			if duplicates {
+				return printDuplicateNames(cmd, result.Data)
+			}
+
+			if saveCursor != "" {
+				state := &pagestate.State{Command: "workflow list", FilterHash: filterHash, Cursor: result.NextCursor}
+				if err := pagestate.Save(saveCursor, state); err != nil {
+					return err
+				}
+			}
+
+			if selectID {
+				options := make([]prompt.Option, len(result.Data))
+				for i, wf := range result.Data {
+					options[i] = prompt.Option{ID: wf.ID, Label: fmt.Sprintf("%-18s  %s", wf.ID, wf.Name)}
+				}
+				id, err := prompt.Select(options)
+				if err != nil {
+					return err
+				}
+				fmt.Println(id)
+				return nil
+			}
+
+			if details {
+				return printWorkflowDetails(cmd, client, result)
+			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
-			if jsonFlag {
-				return printJSON(result)
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, result)
 			}
 
 			if len(result.Data) == 0 {
@@ -97,15 +202,22 @@ func newListCmd() *cobra.Command {
 				return nil
 			}
 
-			// Table output
-			fmt.Printf("%-18s  %-6s  %s\n", "ID", "ACTIVE", "NAME")
-			fmt.Printf("%-18s  %-6s  %s\n", strings.Repeat("-", 18), "------", strings.Repeat("-", 50))
+			table := output.NewTable(cmd, "ID", "ACTIVE", "NAME")
 			for _, wf := range result.Data {
 				activeStr := "no"
 				if wf.Active {
 					activeStr = "yes"
 				}
-				fmt.Printf("%-18s  %-6s  %s\n", wf.ID, activeStr, wf.Name)
+				name := wf.Name
+				if wf.IsArchived {
+					name += " [archived]"
+				}
+				table.AddRow(wf.ID, activeStr, name)
+			}
+			table.Print()
+
+			if result.NextCursor != "" {
+				table.Footer("More results available. Use --cursor %s to continue.", result.NextCursor)
 			}
 
 			return nil
@@ -115,33 +227,190 @@ func newListCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&active, "active", false, "Show only active workflows")
 	cmd.Flags().BoolVar(&inactive, "inactive", false, "Show only inactive workflows")
 	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Filter by tag (can be repeated)")
-	cmd.Flags().IntVar(&limit, "limit", 0, "Page size per API request (0 = default)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "Maximum number of workflows to return (0 = unlimited)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Pagination cursor (fetches single page only)")
+	cmd.Flags().StringVar(&resumeFile, "resume", "", "Continue from the cursor saved in this file by --save-cursor")
+	cmd.Flags().StringVar(&saveCursor, "save-cursor", "", "Save the next cursor to this file for a later --resume")
 	cmd.Flags().StringVar(&projectID, "project", "", "Filter by project ID")
 	cmd.Flags().StringVar(&name, "name", "", "Filter by workflow name")
+	cmd.Flags().BoolVar(&details, "details", false, "Show trigger and execution order columns (may fetch each workflow)")
+	cmd.Flags().BoolVar(&selectID, "select", false, "Pick a workflow interactively and print its ID")
+	cmd.Flags().BoolVar(&archived, "archived", false, "Show only archived workflows")
+	cmd.Flags().BoolVar(&withArch, "include-archived", false, "Show archived workflows too")
+	cmd.Flags().BoolVar(&duplicates, "duplicates", false, "Show only names used by more than one workflow")
 
 	return cmd
 }
 
+// duplicateName is a workflow name shared by several workflows
+type duplicateName struct {
+	Name string   `json:"name"`
+	IDs  []string `json:"ids"`
+}
+
+// printDuplicateNames groups workflows by name and prints the names used
+// more than once
+func printDuplicateNames(cmd *cobra.Command, workflows []api.Workflow) error {
+	ids := make(map[string][]string)
+	for _, wf := range workflows {
+		ids[wf.Name] = append(ids[wf.Name], wf.ID)
+	}
+
+	dups := []duplicateName{}
+	for name, list := range ids {
+		if len(list) > 1 {
+			sort.Strings(list)
+			dups = append(dups, duplicateName{Name: name, IDs: list})
+		}
+	}
+	sort.Slice(dups, func(i, j int) bool { return dups[i].Name < dups[j].Name })
+
+	if output.IsStructured(cmd) {
+		return output.Print(cmd, dups)
+	}
+
+	if len(dups) == 0 {
+		fmt.Println("No duplicate workflow names found.")
+		return nil
+	}
+
+	table := output.NewTable(cmd, "NAME", "COUNT", "IDS")
+	for _, dup := range dups {
+		table.AddRow(dup.Name, strconv.Itoa(len(dup.IDs)), strings.Join(dup.IDs, ", "))
+	}
+	table.Print()
+	return nil
+}
+
+// workflowDetails is a listed workflow with the audit fields of
+// 'workflow list --details'
+type workflowDetails struct {
+	api.Workflow
+	HasTrigger     bool   `json:"hasTrigger"`
+	ExecutionOrder string `json:"executionOrder"`
+}
+
+func printWorkflowDetails(cmd *cobra.Command, client *api.Client, result *api.ListResult[api.Workflow]) error {
+	missing := 0
+	for _, wf := range result.Data {
+		if wf.Nodes == nil {
+			missing++
+		}
+	}
+	if missing > 0 {
+		logging.Info(fmt.Sprintf("Fetching %d workflow(s) for details...", missing), "workflows", missing)
+	}
+
+	detailed := &api.ListResult[workflowDetails]{NextCursor: result.NextCursor}
+	for _, wf := range result.Data {
+		if wf.Nodes == nil {
+			full, err := client.GetWorkflow(wf.ID)
+			if err != nil {
+				return fmt.Errorf("failed to get workflow %s: %w", wf.ID, err)
+			}
+			wf = *full
+		}
+		detailed.Data = append(detailed.Data, workflowDetails{
+			Workflow:       wf,
+			HasTrigger:     workflow.HasTrigger(&wf),
+			ExecutionOrder: workflow.ExecutionOrder(&wf),
+		})
+	}
+
+	if output.IsStructured(cmd) {
+		if detailed.Data == nil {
+			detailed.Data = []workflowDetails{}
+		}
+		return output.Print(cmd, detailed)
+	}
+
+	if len(detailed.Data) == 0 {
+		fmt.Println("No workflows found.")
+		return nil
+	}
+
+	table := output.NewTable(cmd, "ID", "ACTIVE", "TRIGGER", "ORDER", "NAME")
+	noTrigger, legacy := 0, 0
+	for _, wf := range detailed.Data {
+		activeStr := "no"
+		if wf.Active {
+			activeStr = "yes"
+		}
+		triggerStr := "yes"
+		if !wf.HasTrigger {
+			triggerStr = "no"
+			noTrigger++
+		}
+		if wf.ExecutionOrder == workflow.LegacyExecutionOrder {
+			legacy++
+		}
+		table.AddRow(wf.ID, activeStr, triggerStr, wf.ExecutionOrder, wf.Name)
+	}
+	table.Print()
+
+	if noTrigger > 0 || legacy > 0 {
+		table.Footer("%d workflow(s) without a trigger, %d using the legacy execution order.", noTrigger, legacy)
+	}
+	if detailed.NextCursor != "" {
+		table.Footer("More results available. Use --cursor %s to continue.", detailed.NextCursor)
+	}
+	return nil
+}
+
 func newViewCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		raw         bool
+		connections bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "view <workflow-id>",
 		Short: "View a workflow",
-		Args:  cobra.ExactArgs(1),
+		Long: `Show a summary of a workflow: name, ID, active state, project, tags,
+node count, trigger nodes, and timestamps.
+
+--connections adds an outline of the flow: each node followed by the
+nodes its outputs lead to. Output branches are numbered when a node has
+several (e.g. IF: [0] true, [1] false), and connection types other than
+"main" (e.g. ai_tool) are shown in parentheses.
+
+--output json prints the full workflow, or with --connections the list
+of edges. --raw prints the exact JSON sent by the server, including fields
+this CLI doesn't know about.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
-			wf, err := client.GetWorkflow(args[0])
+			if raw {
+				var data []byte
+				_, err = workflow.WithID(client, args[0], func(id string) error {
+					data, err = client.GetWorkflowRaw(id)
+					return err
+				})
+				if err != nil {
+					return fmt.Errorf("failed to get workflow: %w", err)
+				}
+				_, err = os.Stdout.Write(append(bytes.TrimSpace(data), '\n'))
+				return err
+			}
+
+			wf, err := workflow.Get(client, args[0])
 			if err != nil {
 				return fmt.Errorf("failed to get workflow: %w", err)
 			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
-			if jsonFlag {
-				return printJSON(wf)
+			if output.IsStructured(cmd) {
+				if connections {
+					edges := workflow.ParseConnections(wf.Connections)
+					if edges == nil {
+						edges = []workflow.Connection{}
+					}
+					return output.Print(cmd, edges)
+				}
+				return output.Print(cmd, wf)
 			}
 
 			// Human-readable summary
@@ -151,16 +420,15 @@ func newViewCmd() *cobra.Command {
 				activeStr = "yes"
 			}
 			fmt.Printf("Active: %s\n", activeStr)
+			if wf.IsArchived {
+				fmt.Println("Archived: yes")
+			}
 
-			// Show project info from shared field.
-			// n8n's API always returns exactly one owner entry per workflow.
-			if len(wf.Shared) > 0 {
-				s := wf.Shared[0]
-				if s.Project != nil {
-					fmt.Printf("Project: %s (%s)\n", s.Project.Name, s.ProjectID)
-				} else if s.ProjectID != "" {
-					fmt.Printf("Project: %s\n", s.ProjectID)
-				}
+			// Show project info from shared field
+			if projectID, projectName := workflow.OwnerProject(wf); projectName != "" {
+				fmt.Printf("Project: %s (%s)\n", projectName, projectID)
+			} else if projectID != "" {
+				fmt.Printf("Project: %s\n", projectID)
 			}
 
 			// Tags
@@ -172,447 +440,3750 @@ func newViewCmd() *cobra.Command {
 				fmt.Printf("Tags: %s\n", strings.Join(tagNames, ", "))
 			}
 
-			if wf.CreatedAt != nil {
-				fmt.Printf("Created: %s\n", wf.CreatedAt.Local().Format("2006-01-02 15:04:05"))
-			}
-			if wf.UpdatedAt != nil {
-				fmt.Printf("Updated: %s\n", wf.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
-			}
-
-			return nil
-		},
-	}
-}
-
-func newPullCmd() *cobra.Command {
-	var (
-		recursive bool
-		dir       string
-		force     bool
-	)
-
-	cmd := &cobra.Command{
-		Use:   "pull <workflow-id>",
-		Short: "Pull a workflow to local files",
-		Long: `Download a workflow JSON to local filesystem.
-
-With --recursive, also downloads all sub-workflows referenced
-by Execute Workflow nodes, creating a manifest.json that tracks
-the relationships.`,
-		Args: cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
-			if err != nil {
-				return err
-			}
-
-			workflowID := args[0]
-
-			// Create output directory if specified
-			if dir != "" {
-				if err := os.MkdirAll(dir, 0755); err != nil {
-					return fmt.Errorf("failed to create directory: %w", err)
+			fmt.Printf("Nodes: %d\n", len(wf.Nodes))
+			var triggers []string
+			for _, node := range wf.Nodes {
+				nodeType, _ := node["type"].(string)
+				if workflow.IsTriggerType(nodeType) {
+					name, _ := node["name"].(string)
+					triggers = append(triggers, fmt.Sprintf("%s [%s]", name, nodeType))
 				}
 			}
-
-			if recursive {
-				return pullRecursive(client, workflowID, dir, force)
+			if len(triggers) > 0 {
+				fmt.Printf("Triggers: %s\n", strings.Join(triggers, ", "))
+			} else {
+				fmt.Println("Triggers: none")
 			}
 
-			// Simple single workflow pull
-			wf, err := client.GetWorkflow(workflowID)
-			if err != nil {
-				return fmt.Errorf("failed to get workflow: %w", err)
+			if wf.CreatedAt != nil {
+				fmt.Printf("Created: %s\n", wf.CreatedAt.Local().Format("2006-01-02 15:04:05"))
 			}
-
-			filename := workflow.SanitizeFilename(wf.Name) + ".json"
-			if dir != "" {
-				filename = filepath.Join(dir, filename)
+			if wf.UpdatedAt != nil {
+				fmt.Printf("Updated: %s\n", wf.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
 			}
 
-			if !force {
-				if _, err := os.Stat(filename); err == nil {
-					return fmt.Errorf("file %s already exists. Use --force to overwrite", filename)
+			if disabled := workflow.DisabledNodes(wf); len(disabled) > 0 {
+				names := make([]string, len(disabled))
+				for i, node := range disabled {
+					names[i] = node.Name
 				}
+				fmt.Printf("Disabled nodes: %s\n", strings.Join(names, ", "))
 			}
 
-			data, err := json.MarshalIndent(wf, "", "  ")
-			if err != nil {
-				return fmt.Errorf("failed to marshal workflow: %w", err)
-			}
-
-			if err := os.WriteFile(filename, data, 0644); err != nil {
-				return fmt.Errorf("failed to write file: %w", err)
+			if connections {
+				printConnections(wf)
 			}
 
-			fmt.Printf("Pulled workflow to %s\n", filename)
 			return nil
 		},
 	}
 
-	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Also pull sub-workflows")
-	cmd.Flags().StringVarP(&dir, "dir", "d", "", "Output directory")
-	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing files")
+	cmd.Flags().BoolVar(&raw, "raw", false, "Print the workflow JSON exactly as returned by the server")
+	cmd.Flags().BoolVar(&connections, "connections", false, "Show an outline of the node connections")
 
 	return cmd
 }
 
-func pullRecursive(client *api.Client, workflowID, dir string, force bool) error {
-	puller := workflow.NewRecursivePuller(client)
-	result, err := puller.Pull(workflowID)
-	if err != nil {
-		return err
-	}
-
-	// Write all workflows
-	for id, wf := range result.Workflows {
-		filename := workflow.SanitizeFilename(wf.Name) + ".json"
-		if dir != "" {
-			filename = filepath.Join(dir, filename)
+// printConnections prints each node of wf, in workflow order, followed by
+// the nodes its outputs connect to
+func printConnections(wf *api.Workflow) {
+	outgoing := make(map[string][]workflow.Connection)
+	connected := make(map[string]bool)
+	// Count the distinct outputs per source and type, to decide whether
+	// branch numbers are worth showing
+	outputs := make(map[string]map[int]bool)
+	for _, c := range workflow.ParseConnections(wf.Connections) {
+		outgoing[c.Source] = append(outgoing[c.Source], c)
+		connected[c.Source] = true
+		connected[c.Target] = true
+		key := c.Source + "\x00" + c.Type
+		if outputs[key] == nil {
+			outputs[key] = make(map[int]bool)
 		}
+		outputs[key][c.Output] = true
+	}
 
-		if !force {
-			if _, err := os.Stat(filename); err == nil {
-				return fmt.Errorf("file %s already exists. Use --force to overwrite", filename)
+	fmt.Printf("\nConnections:\n")
+	var unconnected []string
+	for _, node := range wf.Nodes {
+		name, _ := node["name"].(string)
+		edges := outgoing[name]
+		if len(edges) == 0 {
+			if !connected[name] {
+				unconnected = append(unconnected, name)
 			}
+			continue
 		}
 
-		data, err := json.MarshalIndent(wf, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal workflow %s: %w", id, err)
-		}
-
-		if err := os.WriteFile(filename, data, 0644); err != nil {
-			return fmt.Errorf("failed to write file: %w", err)
+		fmt.Printf("  %s\n", name)
+		for i, c := range edges {
+			branch := "├─"
+			if i == len(edges)-1 {
+				branch = "└─"
+			}
+			label := ""
+			if c.Type != "main" {
+				label += "(" + c.Type + ") "
+			}
+			if len(outputs[c.Source+"\x00"+c.Type]) > 1 {
+				label += fmt.Sprintf("[%d] ", c.Output)
+			}
+			target := c.Target
+			if c.Input > 0 {
+				target += fmt.Sprintf(" (input %d)", c.Input)
+			}
+			fmt.Printf("    %s %s→ %s\n", branch, label, target)
 		}
-
-		fmt.Printf("Pulled: %s -> %s\n", wf.Name, filename)
-	}
-
-	// Write manifest
-	manifestPath := "manifest.json"
-	if dir != "" {
-		manifestPath = filepath.Join(dir, manifestPath)
 	}
 
-	manifestData, err := json.MarshalIndent(result.Manifest, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal manifest: %w", err)
+	if len(outgoing) == 0 {
+		fmt.Println("  (none)")
 	}
-
-	if err := os.WriteFile(manifestPath, manifestData, 0644); err != nil {
-		return fmt.Errorf("failed to write manifest: %w", err)
+	if len(unconnected) > 0 {
+		fmt.Printf("  Unconnected: %s\n", strings.Join(unconnected, ", "))
 	}
-
-	fmt.Printf("\nPulled %d workflow(s). Manifest: %s\n", len(result.Workflows), manifestPath)
-	return nil
 }
 
-func newPushCmd() *cobra.Command {
+func newValidateCmd() *cobra.Command {
 	var (
-		create bool
+		failOnDisabled bool
+		maxVersions    []string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "push <file-or-directory>",
-		Short: "Push workflow(s) to n8n",
-		Long: `Upload workflow JSON file(s) to n8n.
+		Use:   "validate <workflow-id-file-or-directory>",
+		Short: "Check a workflow or pulled directory for common problems",
+		Long: `Check a workflow for common problems. The argument is a local
+workflow file if it exists, otherwise a workflow ID.
 
-If a directory is specified and contains a manifest.json,
-all workflows in the manifest will be pushed in the correct order.
+Currently reports nodes that are disabled in the editor. A disabled
+trigger or key node can silently break a workflow, so these are listed
+for review. Findings are informational unless --fail-on-disabled is
+given, which makes the command exit with an error, e.g. in CI.
 
-By default, updates existing workflows. Use --create to create new ones.`,
+With --max-node-version (see 'n8nctl workflow push --help'), nodes whose
+typeVersion is too new for the target instance are reported as errors.
+
+Given a directory, its manifest.json is checked against the workflow files
+before a push: every listed file must exist and parse, IDs in the files
+must match the manifest, dependencies must point at listed workflows, and
+no workflow may be caught in a dependency cycle, which push would leave
+out. All problems are reported at once, and the command fails if there
+are any.`,
+		Example: `  n8nctl workflow validate abc123 --fail-on-disabled
+  n8nctl workflow validate ./workflows`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			if info, err := os.Stat(args[0]); err == nil && info.IsDir() {
+				if failOnDisabled || len(maxVersions) > 0 {
+					return fmt.Errorf("--fail-on-disabled and --max-node-version only apply to single workflows")
+				}
+				return validateDirectory(cmd, args[0])
+			}
+
+			limits, err := workflow.ParseVersionLimits(maxVersions)
 			if err != nil {
 				return err
 			}
 
-			path := args[0]
-			info, err := os.Stat(path)
+			wf, err := loadWorkflowArg(cmd, args[0])
 			if err != nil {
-				return fmt.Errorf("failed to access path: %w", err)
+				return err
 			}
 
-			if info.IsDir() {
-				return pushDirectory(client, path, create)
+			disabled := workflow.DisabledNodes(wf)
+			tooNew := limits.Check(wf)
+
+			if output.IsStructured(cmd) {
+				if disabled == nil {
+					disabled = []workflow.DisabledNode{}
+				}
+				if tooNew == nil {
+					tooNew = []workflow.VersionViolation{}
+				}
+				if err := output.Print(cmd, map[string]interface{}{
+					"id":            wf.ID,
+					"name":          wf.Name,
+					"disabledNodes": disabled,
+					"versionErrors": tooNew,
+				}); err != nil {
+					return err
+				}
+			} else if len(disabled) == 0 && len(tooNew) == 0 {
+				fmt.Printf("%s: no problems found.\n", wf.Name)
+			} else {
+				if len(disabled) > 0 {
+					fmt.Printf("%s: %d disabled node(s):\n", wf.Name, len(disabled))
+					for _, node := range disabled {
+						note := ""
+						if node.Trigger {
+							note = " (trigger)"
+						}
+						fmt.Printf("  - %s [%s]%s\n", node.Name, node.Type, note)
+					}
+				}
+				if len(tooNew) > 0 {
+					fmt.Printf("%s: %d node(s) above the version limit:\n", wf.Name, len(tooNew))
+					for _, v := range tooNew {
+						fmt.Printf("  - %s\n", v)
+					}
+				}
 			}
 
-			return pushFile(client, path, create)
+			if len(tooNew) > 0 {
+				return fmt.Errorf("workflow %s has %d node(s) above the version limit", wf.Name, len(tooNew))
+			}
+			if failOnDisabled && len(disabled) > 0 {
+				return fmt.Errorf("workflow %s has %d disabled node(s)", wf.Name, len(disabled))
+			}
+			return nil
 		},
 	}
 
-	cmd.Flags().BoolVar(&create, "create", false, "Create new workflows instead of updating")
+	cmd.Flags().BoolVar(&failOnDisabled, "fail-on-disabled", false, "Exit with an error if any node is disabled")
+	cmd.Flags().StringArrayVar(&maxVersions, "max-node-version", nil, "Highest allowed node typeVersion, as VERSION or TYPE=VERSION (can be repeated)")
 
 	return cmd
 }
 
-func pushFile(client *api.Client, path string, create bool) error {
-	data, err := os.ReadFile(path)
+// resolveWorkflowID resolves a workflow ID, ID prefix, or name given on
+// the command line, see workflow.Get
+func resolveWorkflowID(client *api.Client, ref string) (string, error) {
+	id, err := workflow.ResolveID(client, ref)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
-	}
-
-	var wf api.Workflow
-	if err := json.Unmarshal(data, &wf); err != nil {
-		return fmt.Errorf("failed to parse workflow JSON: %w", err)
+		return "", fmt.Errorf("failed to find workflow: %w", err)
 	}
+	return id, nil
+}
 
-	if create {
-		created, err := client.CreateWorkflow(&wf)
+// loadWorkflowArg reads a workflow from a local file if arg names one,
+// otherwise fetches the workflow with ID arg
+func loadWorkflowArg(cmd *cobra.Command, arg string) (*api.Workflow, error) {
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(arg)
 		if err != nil {
-			return fmt.Errorf("failed to create workflow: %w", err)
+			return nil, fmt.Errorf("failed to read file: %w", err)
 		}
-		fmt.Printf("Created workflow: %s (ID: %s)\n", created.Name, created.ID)
-	} else {
-		if wf.ID == "" {
-			return fmt.Errorf("workflow has no ID. Use --create to create a new workflow")
-		}
-		updated, err := client.UpdateWorkflow(wf.ID, &wf)
+		wf, err := workflow.ParseWorkflowJSON(data)
 		if err != nil {
-			return fmt.Errorf("failed to update workflow: %w", err)
+			return nil, fmt.Errorf("%s: %w", arg, err)
 		}
-		fmt.Printf("Updated workflow: %s (ID: %s)\n", updated.Name, updated.ID)
+		return wf, nil
 	}
 
-	return nil
-}
-
-func pushDirectory(client *api.Client, dir string, create bool) error {
-	manifestPath := filepath.Join(dir, "manifest.json")
-	data, err := os.ReadFile(manifestPath)
+	client, err := cli.NewClient(cmd)
 	if err != nil {
-		return fmt.Errorf("no manifest.json found in directory")
+		return nil, err
 	}
-
-	var manifest workflow.Manifest
-	if err := json.Unmarshal(data, &manifest); err != nil {
-		return fmt.Errorf("failed to parse manifest: %w", err)
+	wf, err := workflow.Get(client, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow: %w", err)
 	}
+	return wf, nil
+}
 
-	// Push in dependency order (sub-workflows first)
-	pusher := workflow.NewPusher(client, dir)
-	if err := pusher.Push(&manifest, create); err != nil {
-		return err
-	}
+// pullOptions controls how pulled workflows are written
+type pullOptions struct {
+	dir            string
+	force          bool
+	filenames      *workflow.FilenameTemplate
+	transforms     []workflow.Transform
+	withExecutions int
+	// redactor masks sensitive values in saved executions (nil = off)
+	redactor *redact.Redactor
+	byTag    bool
+	// multiTag is how workflows with several tags appear in the
+	// directories of their other tags: "first", "copy", or "symlink"
+	multiTag string
+	// signingKey signs the manifest's file hashes (nil = unsigned)
+	signingKey []byte
+	// parents also pulls the workflows calling the pulled one, and
+	// transitive their callers in turn
+	parents    bool
+	transitive bool
+	// fileMode sets the permissions of written files exactly (0 = 0644,
+	// subject to the umask)
+	fileMode os.FileMode
+	// followTypes and ignoreIDs limit which sub-workflow references are
+	// followed
+	followTypes []string
+	ignoreIDs   []string
+	// stateFile records the progress of --all so that an interrupted
+	// pull can be resumed ("" = none)
+	stateFile string
+	// resumable holds the files of the interrupted pull being resumed,
+	// which may be overwritten without --force
+	resumable map[string]bool
+	// strip lists top-level fields left out of the written files
+	strip []string
+	// noFollow pulls a single workflow with a manifest, but without its
+	// sub-workflows
+	noFollow bool
+	// overwrite asks before existing files are replaced without --force
+	// (nil = refuse)
+	overwrite *overwriteConfirmation
+}
+
+// overwriteConfirmation asks once per pull whether existing files may be
+// replaced
+type overwriteConfirmation struct {
+	cmd       *cobra.Command
+	confirmed bool
+}
 
-	fmt.Printf("\nPushed %d workflow(s) successfully.\n", len(manifest.Workflows))
+// confirmOverwrite returns nil if the existing file path may be
+// replaced: with --force, or once the shared confirmation was answered,
+// which --yes does up front. The first answer covers the rest of the pull.
+func (o pullOptions) confirmOverwrite(path string) error {
+	if o.force {
+		return nil
+	}
+	if o.overwrite == nil {
+		return fmt.Errorf("file %s already exists. Use --force to overwrite", path)
+	}
+	if o.overwrite.confirmed {
+		return nil
+	}
+	if err := prompt.Confirm(o.overwrite.cmd, fmt.Sprintf("File %s already exists. Overwrite it and other existing files?", path)); err != nil {
+		return fmt.Errorf("file %s already exists: %w", path, err)
+	}
+	o.overwrite.confirmed = true
 	return nil
 }
 
-func newRunCmd() *cobra.Command {
+func newPullCmd() *cobra.Command {
 	var (
-		inputJSON   string
-		wait        bool
-		webhookPath string
-		method      string
+		opts             pullOptions
+		recursive        bool
+		withManifest     bool
+		stripFields      []string
+		all              bool
+		filenameTemplate string
+		transformPath    string
+		signKeyPath      string
+		redactOut        bool
+		redactProfile    string
+		fileMode         string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "run <workflow-id>",
-		Short: "Execute a workflow",
-		Long: `Execute a workflow via the API or via a webhook trigger.
+		Use:   "pull [workflow-id]",
+		Short: "Pull a workflow to local files",
+		Long: `Download a workflow JSON to local filesystem.
 
-By default, uses the /execute API endpoint. If your n8n instance doesn't
-support this endpoint (returns 405), use --webhook to trigger via webhook instead.
+With --recursive, also downloads all sub-workflows referenced
+by Execute Workflow nodes, creating a manifest.json that tracks
+the relationships. --all pulls every workflow on the instance the
+same way, e.g. for backups.
 
-Examples:
-  n8nctl wf run abc123                         # Execute via API
-  n8nctl wf run abc123 --webhook my-hook-path  # Trigger via webhook (GET)
-  n8nctl wf run abc123 --webhook my-hook-path --method POST`,
-		Args: cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
-			if err != nil {
-				return err
+Repeating --all in the same directory is incremental: workflows whose
+update time matches the one recorded in the manifest are neither
+downloaded nor rewritten, and the files of changed workflows are
+updated in place. --force downloads everything again, e.g. after
+changing --transform rules.
+
+Other existing files are only overwritten after a confirmation, which
+--force or the global --yes skip.
+
+--state makes a large --all pull resumable: each workflow is written as
+soon as it is fetched and recorded in the state file. If the pull fails,
+running the same command again skips the workflows already written. The
+state file is removed once the manifest is written.
+
+With --by-tag, each workflow is written to a subdirectory named after
+its first tag (untagged/ if it has none), and the manifest records
+these paths so push works unchanged. --multi-tag controls the other
+tags' directories: "first" (default) only uses the first tag, "copy"
+writes a copy of the file, and "symlink" a relative symbolic link.
+
+The manifest records a SHA-256 hash of each workflow file, which
+'n8nctl workflow verify' checks later. With --sign-key, the hashes are
+also signed with an HMAC key read from a file.
+
+File names are built from --filename-template, a Go template with the
+fields .ID, .Name, and .Slug (lowercase, dash-separated name). The result
+is sanitized and .json is appended, e.g.:
+
+  --filename-template '{{.ID}}'             -> abc123.json
+  --filename-template '{{.Slug}}-{{.ID}}'   -> my-workflow-abc123.json
+
+--transform applies find/replace rules to each workflow before it is
+written; see 'n8nctl workflow push --help' for the rule format.
+
+--with-executions N also saves the last N executions of each pulled
+workflow, including their data, to executions/<id>.json. With a
+manifest, the files are listed per workflow. Add --redact to mask values
+stored under sensitive keys (passwords, tokens, API keys, ...), or
+--redact-profile to use a profile from the config file's redactProfiles.
+
+--parents pulls the workflows that call the given one through Execute
+Workflow nodes, each with its own sub-workflows, to see what a change to
+a shared sub-workflow affects. Add --transitive to follow the callers up
+to the top. The manifest records the callers of each workflow.
+
+--follow-type only follows sub-workflow references of the given node
+types (e.g. n8n-nodes-base.executeWorkflow), and --ignore-id never
+follows references to the given workflows, e.g. large shared libraries.
+References left out are listed under "skipped" in the manifest.
+
+--manifest also writes a manifest.json for a single workflow, without
+pulling its sub-workflows. The directory can then be pushed like one
+from a recursive pull, and the manifest keeps the workflow's active
+state, project, and credentials.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (redactOut || redactProfile != "") && opts.withExecutions == 0 {
+				return fmt.Errorf("--redact requires --with-executions")
+			}
+			opts.overwrite = &overwriteConfirmation{cmd: cmd}
+			if all == (len(args) == 1) {
+				return fmt.Errorf("specify either a workflow ID or --all")
+			}
+			if opts.parents && all {
+				return fmt.Errorf("--parents requires a workflow ID")
+			}
+			if opts.transitive && !opts.parents {
+				return fmt.Errorf("--transitive requires --parents")
+			}
+			if withManifest && (all || recursive || opts.parents) {
+				return fmt.Errorf("--manifest is implied by --all, --recursive, and --parents")
+			}
+			if opts.byTag && !all && !recursive && !opts.parents && !withManifest {
+				return fmt.Errorf("--by-tag requires --all, --recursive, or --manifest")
+			}
+			if opts.stateFile != "" && !all {
+				return fmt.Errorf("--state requires --all")
+			}
+			if (len(opts.followTypes) > 0 || len(opts.ignoreIDs) > 0) && !all && !recursive && !opts.parents {
+				return fmt.Errorf("--follow-type and --ignore-id require --recursive or --all")
+			}
+			if signKeyPath != "" {
+				if !all && !recursive && !opts.parents && !withManifest {
+					return fmt.Errorf("--sign-key requires --all, --recursive, or --manifest")
+				}
+				key, err := workflow.ReadSigningKey(signKeyPath)
+				if err != nil {
+					return err
+				}
+				opts.signingKey = key
+			}
+			if fileMode != "" {
+				mode, err := parseFileMode(fileMode)
+				if err != nil {
+					return err
+				}
+				opts.fileMode = mode
+			}
+			strip, err := workflow.ParseStripFields(stripFields)
+			if err != nil {
+				return err
+			}
+			opts.strip = strip
+			switch opts.multiTag {
+			case "first", "copy", "symlink":
+			default:
+				return fmt.Errorf("invalid --multi-tag %q (expected first, copy, or symlink)", opts.multiTag)
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			if !cmd.Flags().Changed("dir") {
+				if cfg, err := config.Load(); err == nil {
+					// The instance pulled from, like the client
+					if name, _ := cmd.Flags().GetString("instance"); name != "" {
+						opts.dir = cfg.InstancePullDir(name)
+					} else {
+						opts.dir = cfg.PullDir()
+					}
+				}
+			}
+
+			opts.filenames, err = workflow.ParseFilenameTemplate(filenameTemplate)
+			if err != nil {
+				return err
+			}
+
+			if redactOut || redactProfile != "" {
+				opts.redactor, err = redact.ForConfiguredProfile(redactProfile)
+				if err != nil {
+					return err
+				}
+			}
+
+			if transformPath != "" {
+				opts.transforms, err = workflow.LoadTransforms(transformPath)
+				if err != nil {
+					return err
+				}
+			}
+
+			// Create output directory if specified
+			if opts.dir != "" {
+				if err := os.MkdirAll(opts.dir, 0755); err != nil {
+					return fmt.Errorf("failed to create directory: %w", err)
+				}
+			}
+
+			if recursive || all || opts.parents || withManifest {
+				opts.noFollow = withManifest
+				if len(args) == 0 {
+					return pullRecursive(client, "", opts)
+				}
+				_, err = workflow.WithID(client, args[0], func(id string) error {
+					return pullRecursive(client, id, opts)
+				})
+				return err
+			}
+
+			// Simple single workflow pull
+			wf, err := workflow.Get(client, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get workflow: %w", err)
+			}
+
+			changes, err := workflow.ApplyTransforms(opts.transforms, wf, workflow.StagePull)
+			if err != nil {
+				return err
+			}
+			if changes > 0 {
+				fmt.Printf("Applied %d transform change(s).\n", changes)
+			}
+
+			filename, err := opts.filenames.Filename(wf)
+			if err != nil {
+				return err
+			}
+			if opts.dir != "" {
+				filename = filepath.Join(opts.dir, filename)
+			}
+
+			if _, err := os.Stat(filename); err == nil {
+				if err := opts.confirmOverwrite(filename); err != nil {
+					return err
+				}
+			}
+
+			data, err := workflow.MarshalFile(workflow.Strip(wf, opts.strip))
+			if err != nil {
+				return fmt.Errorf("failed to marshal workflow: %w", err)
+			}
+
+			if err := writePulledFile(filename, data, opts.fileMode); err != nil {
+				return fmt.Errorf("failed to write file: %w", err)
+			}
+
+			fmt.Printf("Pulled workflow to %s\n", filename)
+
+			if _, err := pullExecutions(client, wf.ID, opts); err != nil {
+				return err
+			}
+
+			printCredentialSummary(workflow.ExtractCredentials(wf.Nodes))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Also pull sub-workflows")
+	cmd.Flags().BoolVar(&all, "all", false, "Pull all workflows of the instance")
+	cmd.Flags().BoolVar(&withManifest, "manifest", false, "Also write a manifest.json for a single workflow, without sub-workflows")
+	cmd.Flags().StringVar(&opts.stateFile, "state", "", "With --all, record progress in this file to resume an interrupted pull")
+	cmd.Flags().BoolVar(&opts.parents, "parents", false, "Also pull the workflows that call this one")
+	cmd.Flags().BoolVar(&opts.transitive, "transitive", false, "With --parents, also pull callers of callers")
+	cmd.Flags().BoolVar(&opts.byTag, "by-tag", false, "Group workflow files in a directory per tag")
+	cmd.Flags().StringSliceVar(&opts.followTypes, "follow-type", nil, "Only follow sub-workflow references of this node type (can be repeated)")
+	cmd.Flags().StringSliceVar(&opts.ignoreIDs, "ignore-id", nil, "Don't follow references to this workflow ID (can be repeated)")
+	cmd.Flags().StringVar(&signKeyPath, "sign-key", "", "Sign the manifest's file hashes with the HMAC key in this file")
+	cmd.Flags().StringVar(&opts.multiTag, "multi-tag", "first", "With --by-tag, how to place workflows with several tags: first, copy, or symlink")
+	cmd.Flags().StringVarP(&opts.dir, "dir", "d", "", "Output directory (default: the configured default-pull-dir)")
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Overwrite existing files without asking")
+	cmd.Flags().StringVar(&fileMode, "file-mode", "", "Octal permissions of written files, e.g. 0600 (default: 0644 minus umask)")
+	cmd.Flags().StringVar(&filenameTemplate, "filename-template", workflow.DefaultFilenameTemplate, "Go template for workflow file names (fields: .ID, .Name, .Slug)")
+	cmd.Flags().StringVar(&transformPath, "transform", "", "Transform rule file or directory of rule files")
+	cmd.Flags().IntVar(&opts.withExecutions, "with-executions", 0, "Also save the last N executions of each workflow")
+	cmd.Flags().BoolVar(&redactOut, "redact", false, "Mask sensitive values in saved executions")
+	cmd.Flags().StringVar(&redactProfile, "redact-profile", "", "Redaction profile from the config file (implies --redact)")
+	cmd.Flags().StringSliceVar(&stripFields, "strip", nil, "Leave these top-level fields out of the files: staticData, pinData, tags, shared, timestamps (can be repeated)")
+
+	return cmd
+}
+
+// parseFileMode parses an octal permission value such as 0600
+func parseFileMode(s string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode == 0 || mode > 0777 {
+		return 0, fmt.Errorf("invalid --file-mode %q (expected octal permissions such as 0600)", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+// writePulledFile writes a file created by pull. With a mode, the file
+// gets exactly these permissions, also when it is overwritten and
+// regardless of the umask.
+func writePulledFile(path string, data []byte, mode os.FileMode) error {
+	if mode == 0 {
+		return os.WriteFile(path, data, 0644)
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return err
+	}
+	return os.Chmod(path, mode)
+}
+
+// pullRecursive pulls a workflow and its sub-workflows, or all workflows
+// if workflowID is empty, and writes them together with a manifest
+func pullRecursive(client *api.Client, workflowID string, opts pullOptions) error {
+	puller := workflow.NewRecursivePuller(client)
+	puller.Filenames = opts.filenames
+	puller.Transforms = opts.transforms
+	puller.ByTag = opts.byTag
+	puller.FollowTypes = opts.followTypes
+	puller.IgnoreIDs = opts.ignoreIDs
+	puller.NoFollow = opts.noFollow
+	if workflowID == "" && !opts.force {
+		puller.Previous = previousPull(opts.dir)
+	}
+	// Files of a pull with other --strip fields can't be kept as they are
+	previous := puller.Previous
+	if previous != nil && strings.Join(previous.Stripped, ",") != strings.Join(opts.strip, ",") {
+		puller.Previous = nil
+	}
+
+	// With a state file, each workflow is written as soon as it is
+	// fetched and recorded, so a rerun continues after the last one. The
+	// workflow is marked pending while its files are written, so a rerun
+	// after an interruption in between fetches it again and may overwrite
+	// what was left.
+	written := make(map[string]bool)
+	if opts.stateFile != "" {
+		state, err := loadPullState(opts.stateFile, opts.dir)
+		if err != nil {
+			return err
+		}
+		if state != nil {
+			puller.Resume = state.Manifest
+			opts.resumable = state.resumable
+			logging.Info(fmt.Sprintf("Resuming from %s: %d workflow(s) already written.", opts.stateFile, len(state.Manifest.Workflows)),
+				"stateFile", opts.stateFile, "written", len(state.Manifest.Workflows))
+		}
+		puller.AfterPull = func(wf *api.Workflow, manifest *workflow.Manifest) error {
+			if err := savePullState(opts.stateFile, manifest, wf.ID); err != nil {
+				return err
+			}
+			if err := writePulledWorkflow(client, wf, manifest, previous, opts); err != nil {
+				return err
+			}
+			written[wf.ID] = true
+			return savePullState(opts.stateFile, manifest, "")
+		}
+	}
+
+	var result *workflow.PullResult
+	var err error
+	switch {
+	case workflowID == "":
+		result, err = puller.PullAll()
+	case opts.parents:
+		result, err = puller.PullParents(workflowID, opts.transitive)
+	default:
+		result, err = puller.Pull(workflowID)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Write all workflows
+	for id, wf := range result.Workflows {
+		if written[id] {
+			continue
+		}
+		if err := writePulledWorkflow(client, wf, result.Manifest, previous, opts); err != nil {
+			return err
+		}
+	}
+
+	result.Manifest.Stripped = opts.strip
+	if opts.signingKey != nil {
+		result.Manifest.Sign(opts.signingKey)
+	}
+
+	// Write manifest
+	manifestPath := "manifest.json"
+	if opts.dir != "" {
+		manifestPath = filepath.Join(opts.dir, manifestPath)
+	}
+
+	manifestData, err := workflow.MarshalFile(result.Manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err := writePulledFile(manifestPath, manifestData, opts.fileMode); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if opts.stateFile != "" {
+		if err := os.Remove(opts.stateFile); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove state file: %w", err)
+		}
+	}
+
+	for _, cycle := range result.Manifest.Cycles {
+		names := make([]string, len(cycle))
+		for i, id := range cycle {
+			names[i] = workflowName(result.Manifest, id)
+		}
+		logging.Info("Note: sub-workflow cycle detected: "+strings.Join(names, " -> "), "cycle", cycle)
+	}
+
+	if len(result.Manifest.Skipped) > 0 {
+		logging.Info(fmt.Sprintf("Skipped %d sub-workflow reference(s); see \"skipped\" in the manifest.", len(result.Manifest.Skipped)),
+			"skipped", len(result.Manifest.Skipped))
+	}
+
+	if opts.parents {
+		printCallers(result.Manifest, workflowID)
+	}
+
+	if result.Transformed > 0 {
+		fmt.Printf("Applied %d transform change(s).\n", result.Transformed)
+	}
+
+	pulled := fmt.Sprintf("%d workflow(s)", len(result.Workflows))
+	if len(result.Unchanged) > 0 {
+		pulled += fmt.Sprintf(", skipped %d unchanged", len(result.Unchanged))
+	}
+	if len(result.Resumed) > 0 {
+		pulled += fmt.Sprintf(", %d written before resuming", len(result.Resumed))
+	}
+	fmt.Printf("\nPulled %s. Manifest: %s\n", pulled, manifestPath)
+	printCredentialSummary(result.Manifest.Credentials)
+	return nil
+}
+
+// writePulledWorkflow writes a workflow fetched by a recursive pull to the
+// file recorded in manifest, along with its executions, and completes its
+// manifest entry
+func writePulledWorkflow(client *api.Client, wf *api.Workflow, manifest *workflow.Manifest, previous *workflow.Manifest, opts pullOptions) error {
+	id := wf.ID
+	meta := manifest.Workflows[id]
+	filename := meta.Filename
+	if opts.dir != "" {
+		filename = filepath.Join(opts.dir, filename)
+	}
+
+	// Files left by an interrupted run with the same state file are
+	// rewritten, along with the workflow's executions
+	if opts.resumable[meta.Filename] {
+		opts.force = true
+	}
+
+	// Files of an earlier --all pull are updated in place
+	if !pulledBefore(previous, id, meta.Filename) {
+		if _, err := os.Stat(filename); err == nil {
+			if err := opts.confirmOverwrite(filename); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := workflow.MarshalFile(workflow.Strip(wf, opts.strip))
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow %s: %w", id, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := writePulledFile(filename, data, opts.fileMode); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	logging.Info(fmt.Sprintf("Pulled: %s -> %s", wf.Name, filename), "workflow", id, "name", wf.Name, "file", filename)
+
+	if opts.byTag && opts.multiTag != "first" {
+		if err := linkOtherTags(wf, filename, opts); err != nil {
+			return err
+		}
+	}
+
+	files, err := pullExecutions(client, id, opts)
+	if err != nil {
+		return err
+	}
+	meta.SHA256 = workflow.ContentHash(data)
+	meta.Executions = files
+	manifest.Workflows[id] = meta
+	return nil
+}
+
+// pullState is the progress of 'pull --all --state': the manifest entries
+// of the workflows written so far, and the one being written
+type pullState struct {
+	SavedAt time.Time `json:"savedAt"`
+	// Pending is the ID of a workflow whose files were being written.
+	// Its manifest entry is incomplete.
+	Pending  string             `json:"pending,omitempty"`
+	Manifest *workflow.Manifest `json:"manifest"`
+
+	// resumable holds the manifest filenames that the interrupted run
+	// wrote or was writing, which the rerun may overwrite
+	resumable map[string]bool
+}
+
+// loadPullState reads a state file. A missing file yields nil without
+// error, so the first run starts from the beginning. The pending workflow
+// and those whose files are gone from dir are dropped from the manifest,
+// so they are fetched again.
+func loadPullState(path, dir string) (*pullState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state pullState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if state.Manifest == nil || state.Manifest.Workflows == nil {
+		return nil, fmt.Errorf("state file %s has no manifest", path)
+	}
+
+	state.resumable = make(map[string]bool, len(state.Manifest.Workflows))
+	for id, meta := range state.Manifest.Workflows {
+		state.resumable[meta.Filename] = true
+		if _, err := os.Stat(filepath.Join(dir, meta.Filename)); id == state.Pending || err != nil {
+			delete(state.Manifest.Workflows, id)
+			delete(state.Manifest.Dependencies, id)
+		}
+	}
+	return &state, nil
+}
+
+// savePullState records the manifest so far, with the workflow whose
+// files are about to be written as pending ("" = none). The file is
+// replaced by a rename, so an interruption never leaves a truncated
+// state behind.
+func savePullState(path string, manifest *workflow.Manifest, pending string) error {
+	data, err := workflow.MarshalFile(&pullState{SavedAt: time.Now().UTC(), Pending: pending, Manifest: manifest})
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// previousPull returns the manifest of an earlier pull into dir, without
+// the workflows whose files have since been deleted, or nil if there is
+// none
+func previousPull(dir string) *workflow.Manifest {
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return nil
+	}
+	for id, meta := range manifest.Workflows {
+		if _, err := os.Stat(filepath.Join(dir, meta.Filename)); err != nil {
+			delete(manifest.Workflows, id)
+		}
+	}
+	return manifest
+}
+
+// pulledBefore reports whether the previous manifest wrote workflow id to
+// filename
+func pulledBefore(previous *workflow.Manifest, id, filename string) bool {
+	if previous == nil {
+		return false
+	}
+	meta, ok := previous.Workflows[id]
+	return ok && meta.Filename == filename
+}
+
+// printCallers lists the workflows calling the root workflow, and with
+// transitive pulls their callers, as an indented tree
+func printCallers(manifest *workflow.Manifest, workflowID string) {
+	if len(manifest.Callers[workflowID]) == 0 {
+		fmt.Printf("No workflows call %s.\n", workflowID)
+		return
+	}
+
+	fmt.Printf("Callers of %s:\n", workflowName(manifest, workflowID))
+	seen := map[string]bool{workflowID: true}
+	var walk func(id string, depth int)
+	walk = func(id string, depth int) {
+		for _, caller := range manifest.Callers[id] {
+			fmt.Printf("%s- %s\n", strings.Repeat("  ", depth+1), workflowName(manifest, caller))
+			if !seen[caller] {
+				seen[caller] = true
+				walk(caller, depth+1)
+			}
+		}
+	}
+	walk(workflowID, 0)
+}
+
+// workflowName formats a manifest workflow as "Name (id)"
+func workflowName(manifest *workflow.Manifest, id string) string {
+	if meta, ok := manifest.Workflows[id]; ok {
+		return fmt.Sprintf("%s (%s)", meta.Name, id)
+	}
+	return id
+}
+
+// linkOtherTags places a copy of or a symbolic link to a pulled workflow
+// file in the directories of the workflow's tags after the first
+func linkOtherTags(wf *api.Workflow, filename string, opts pullOptions) error {
+	for _, tag := range wf.Tags[min(1, len(wf.Tags)):] {
+		tagDir := filepath.Join(opts.dir, workflow.TagDir(tag.Name))
+		if err := os.MkdirAll(tagDir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		target := filepath.Join(tagDir, filepath.Base(filename))
+		if target == filename {
+			continue
+		}
+
+		if _, err := os.Lstat(target); err == nil {
+			if err := opts.confirmOverwrite(target); err != nil {
+				return err
+			}
+			if err := os.Remove(target); err != nil {
+				return fmt.Errorf("failed to replace %s: %w", target, err)
+			}
+		}
+
+		if opts.multiTag == "symlink" {
+			rel, err := filepath.Rel(tagDir, filename)
+			if err != nil {
+				return fmt.Errorf("failed to link %s: %w", target, err)
+			}
+			if err := os.Symlink(rel, target); err != nil {
+				return fmt.Errorf("failed to link %s: %w", target, err)
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		if err := writePulledFile(target, data, opts.fileMode); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	}
+	return nil
+}
+
+// pullExecutions saves the last opts.withExecutions executions of a
+// workflow, including their data, to executions/<id>.json below opts.dir.
+// It returns the written paths relative to opts.dir.
+func pullExecutions(client *api.Client, workflowID string, opts pullOptions) ([]string, error) {
+	if opts.withExecutions <= 0 {
+		return nil, nil
+	}
+
+	result, err := client.ListExecutions(api.ListExecutionsOptions{
+		WorkflowID:  workflowID,
+		IncludeData: true,
+		Limit:       opts.withExecutions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions of workflow %s: %w", workflowID, err)
+	}
+	if len(result.Data) == 0 {
+		return nil, nil
+	}
+
+	execDir := filepath.Join(opts.dir, "executions")
+	if err := os.MkdirAll(execDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	redacted := 0
+	files := make([]string, 0, len(result.Data))
+	for _, exec := range result.Data {
+		if opts.redactor != nil {
+			redacted += opts.redactor.Apply(exec.Data)
+		}
+
+		rel := filepath.Join("executions", workflow.SanitizeFilename(exec.ID)+".json")
+		path := filepath.Join(opts.dir, rel)
+		if _, err := os.Stat(path); err == nil {
+			if err := opts.confirmOverwrite(path); err != nil {
+				return nil, err
+			}
+		}
+
+		data, err := workflow.MarshalFile(exec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal execution %s: %w", exec.ID, err)
+		}
+		if err := writePulledFile(path, data, opts.fileMode); err != nil {
+			return nil, fmt.Errorf("failed to write file: %w", err)
+		}
+		files = append(files, filepath.ToSlash(rel))
+	}
+
+	fmt.Printf("Saved %d execution(s) to %s\n", len(files), execDir)
+	if redacted > 0 {
+		fmt.Printf("Redacted %d sensitive value(s).\n", redacted)
+	}
+	return files, nil
+}
+
+// printCredentialSummary lists the credentials that must exist on a target
+// instance before the pulled workflows can run there
+func printCredentialSummary(creds []workflow.CredentialRef) {
+	if len(creds) == 0 {
+		return
+	}
+	names := make([]string, len(creds))
+	for i, c := range creds {
+		names[i] = c.Name
+		if names[i] == "" {
+			names[i] = c.ID
+		}
+	}
+	fmt.Printf("References %d credential(s): %s\n", len(creds), strings.Join(names, ", "))
+}
+
+// pushOptions controls how workflow files are pushed
+type pushOptions struct {
+	create          bool
+	force           bool
+	autoLayout      bool
+	allowDuplicates bool
+	// requireUniqueName refuses names used by another workflow, also
+	// when updating
+	requireUniqueName bool
+	prunePinData      bool
+	onlyTypes         []string
+	excludeTypes      []string
+	transforms        []workflow.Transform
+	interpolator      *workflow.Interpolator
+	versionLimits     workflow.VersionLimits
+	downgrade         bool
+	// summaryFile receives a report of the changes ("" = none)
+	summaryFile string
+	staticData  workflow.StaticDataMode
+	// continueOnError pushes the remaining workflows of a directory after
+	// one fails
+	continueOnError bool
+}
+
+func (o pushOptions) nodeFilter() workflow.NodeFilter {
+	return workflow.NodeFilter{Only: o.onlyTypes, Exclude: o.excludeTypes}
+}
+
+func newPushCmd() *cobra.Command {
+	var (
+		opts          pushOptions
+		transformPath string
+		interpolate   bool
+		vars          []string
+		allowUnset    bool
+		maxVersions   []string
+		preserveData  bool
+		resetData     bool
+		plan          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "push <file-or-directory>",
+		Aliases: []string{"apply"},
+		Short:   "Push workflow(s) to n8n",
+		Long: `Upload workflow JSON file(s) to n8n.
+
+If a directory is specified and contains a manifest.json,
+all workflows in the manifest will be pushed in the correct order.
+'apply' is an alias, so 'n8nctl workflow apply <dir> --plan' previews a
+directory push.
+
+By default, updates existing workflows. Use --create to create new ones.
+
+Before updating, the remote workflow is fetched and compared with the
+local file. Workflows whose content is identical are reported as
+unchanged and skipped, unless --force is given.
+
+With --create, workflows are only created if no workflow with the same
+name exists yet, so running the same push twice doesn't produce
+duplicates. Use --allow-duplicates to skip this check.
+
+--require-unique-name extends the check to updates: the push is refused
+before anything is uploaded if a workflow would get a name that another
+workflow on the instance already has, e.g. after being renamed in its
+file, or if two files of a directory share a name. 'n8nctl workflow list
+--duplicates' shows the names that are already taken more than once.
+
+Use --prune-pindata to drop data pinned in the editor during testing
+and report how many nodes had some. The n8n API doesn't
+accept pinned data, so it is never uploaded, with or without the flag,
+and data pinned on the server is left as it is. Static data is kept.
+
+With --create, --auto-layout assigns fresh node positions based on the
+connection graph, which helps when nodes are missing positions or
+would render stacked on top of each other.
+
+--exclude-type and --only-type restrict the unchanged check to certain
+node types, so e.g. moved sticky notes alone don't trigger an update:
+
+  n8nctl workflow push ./workflows --exclude-type n8n-nodes-base.stickyNote
+
+Filtered nodes are still uploaded when a workflow does get updated.
+
+--transform applies find/replace rules to string node parameters, e.g. to
+rewrite environment-specific URLs. It takes a JSON rule file or a directory
+of them:
+
+  {
+    "rules": [
+      {
+        "nodeType": "n8n-nodes-base.httpRequest",
+        "parameter": "url",
+        "find": "https://staging.example.com",
+        "replace": "https://api.example.com",
+        "regex": false,
+        "stages": ["push"]
+      }
+    ]
+  }
+
+nodeType (empty = all nodes), parameter (dot-separated path below the node's
+parameters; empty = every string parameter), regex (Go syntax, $1 expands
+groups), and stages (pull and/or push; empty = both) are optional. Files in
+a directory run in lexical order, rules in the order listed. On push, rules
+run right after a file is read, before sub-workflow and credential references
+are rewritten; on pull, right after a workflow is fetched.
+
+--interpolate expands ${NAME} placeholders in the workflow files before
+they are parsed, so one file can be pushed to several environments:
+
+  "url": "${API_BASE_URL}/orders"
+
+Values come from --var NAME=value, then from the environment; --var implies
+--interpolate. Unresolved placeholders are an error unless --allow-unset is
+given, in which case they are left as they are. Write $$ for a literal $,
+e.g. $${name} in Code node template literals.
+
+--max-node-version guards against pushing nodes that are too new for the
+target instance. A plain number applies to all node types, TYPE=VERSION to
+one type (can be repeated):
+
+  n8nctl workflow push ./workflows --max-node-version 4 \
+    --max-node-version n8n-nodes-base.httpRequest=4.1
+
+Offending nodes are reported and nothing is pushed. With --downgrade, their
+typeVersion is lowered to the limit instead; parameters are not migrated,
+so check the result in the editor.
+
+A directory push stops at the first workflow that fails to upload.
+--continue-on-error pushes the others anyway, reports each failure, and
+exits non-zero at the end. With --create, workflows calling a sub-workflow
+that failed are skipped, since their references couldn't be rewritten.
+Invalid files still stop the push before anything is uploaded.
+
+--summary-file writes a report of what the push did: the action per
+workflow (created, updated, unchanged, failed, or skipped after an error) and how
+many nodes were added, removed, and changed. Files ending in .md get a
+Markdown table, e.g. for a pull request comment; others get JSON.
+
+n8n replaces a workflow's staticData on every update. Trigger nodes keep
+state there, e.g. when a polling trigger last checked for new items, so
+uploading the stale copy from a file would make them skip or repeat
+items. Updates therefore keep the staticData stored on the instance.
+--reset-static-data clears it instead (also for created workflows), and
+--preserve-static-data=false sends the file's copy, e.g. to restore a
+backup.
+
+--plan shows what pushing a directory would do without changing anything:
+each workflow of the manifest is matched to the instance by ID, then by
+name, and reported as to be created, updated (with the number of added,
+removed, and changed nodes), or unchanged. Workflows whose name matches
+several on the instance are reported as conflicts. Name matches are
+marked as such, since push itself only updates by ID. Transforms,
+placeholders, and the type filters apply as for a real push.`,
+		Example: `  n8nctl workflow push ./workflows --plan
+  n8nctl workflow push ./workflows --plan -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if plan {
+				for _, name := range []string{"create", "force", "summary-file", "continue-on-error", "auto-layout", "allow-duplicates", "require-unique-name"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--plan cannot be combined with --%s", name)
+					}
+				}
+			}
+			if opts.autoLayout && !opts.create {
+				return fmt.Errorf("--auto-layout can only be used with --create")
+			}
+			if opts.requireUniqueName && opts.allowDuplicates {
+				return fmt.Errorf("--require-unique-name and --allow-duplicates cannot be combined")
+			}
+
+			switch {
+			case resetData && cmd.Flags().Changed("preserve-static-data"):
+				return fmt.Errorf("--reset-static-data and --preserve-static-data cannot be combined")
+			case resetData:
+				opts.staticData = workflow.StaticDataReset
+			case !preserveData:
+				opts.staticData = workflow.StaticDataFile
+			default:
+				opts.staticData = workflow.StaticDataPreserve
+			}
+
+			if transformPath != "" {
+				transforms, err := workflow.LoadTransforms(transformPath)
+				if err != nil {
+					return err
+				}
+				opts.transforms = transforms
+			}
+
+			if interpolate || len(vars) > 0 {
+				parsed, err := workflow.ParseVars(vars)
+				if err != nil {
+					return err
+				}
+				opts.interpolator = &workflow.Interpolator{Vars: parsed, AllowUnset: allowUnset}
+			} else if allowUnset {
+				return fmt.Errorf("--allow-unset requires --interpolate or --var")
+			}
+
+			limits, err := workflow.ParseVersionLimits(maxVersions)
+			if err != nil {
+				return err
+			}
+			opts.versionLimits = limits
+			if opts.downgrade && opts.versionLimits.IsEmpty() {
+				return fmt.Errorf("--downgrade requires --max-node-version")
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			path := args[0]
+			info, err := os.Stat(path)
+			if err != nil {
+				return fmt.Errorf("failed to access path: %w", err)
+			}
+
+			if info.IsDir() {
+				if plan {
+					return planDirectory(cmd, client, path, opts)
+				}
+				return pushDirectory(client, path, opts)
+			}
+			if plan {
+				return fmt.Errorf("--plan requires a directory with a manifest.json")
+			}
+
+			return pushFile(client, path, opts)
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.create, "create", false, "Create new workflows instead of updating")
+	cmd.Flags().BoolVar(&opts.continueOnError, "continue-on-error", false, "With a directory, push the remaining workflows after one fails")
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Update workflows even if they are unchanged")
+	cmd.Flags().BoolVar(&opts.autoLayout, "auto-layout", false, "Recompute node positions when creating workflows")
+	cmd.Flags().BoolVar(&opts.allowDuplicates, "allow-duplicates", false, "Create workflows even if one with the same name exists")
+	cmd.Flags().BoolVar(&opts.requireUniqueName, "require-unique-name", false, "Refuse to push workflows whose name another workflow already has, also when updating")
+	cmd.Flags().BoolVar(&opts.prunePinData, "prune-pindata", false, "Remove pinned test data before uploading")
+	cmd.Flags().StringSliceVar(&opts.onlyTypes, "only-type", nil, "Only compare nodes of this type when checking for changes (can be repeated)")
+	cmd.Flags().StringSliceVar(&opts.excludeTypes, "exclude-type", nil, "Ignore nodes of this type when checking for changes (can be repeated)")
+	cmd.Flags().StringVar(&transformPath, "transform", "", "Transform rule file or directory of rule files")
+	cmd.Flags().BoolVar(&interpolate, "interpolate", false, "Expand ${NAME} placeholders from the environment")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Placeholder value as NAME=value (can be repeated)")
+	cmd.Flags().BoolVar(&allowUnset, "allow-unset", false, "Leave unresolved placeholders instead of failing")
+	cmd.Flags().StringArrayVar(&maxVersions, "max-node-version", nil, "Highest allowed node typeVersion, as VERSION or TYPE=VERSION (can be repeated)")
+	cmd.Flags().BoolVar(&opts.downgrade, "downgrade", false, "Lower node typeVersions above --max-node-version instead of failing")
+	cmd.Flags().StringVar(&opts.summaryFile, "summary-file", "", "Write a report of the changes to this file (.md for Markdown, otherwise JSON)")
+	cmd.Flags().BoolVar(&preserveData, "preserve-static-data", true, "Keep the instance's staticData (trigger state) when updating")
+	cmd.Flags().BoolVar(&resetData, "reset-static-data", false, "Clear the staticData (trigger state) of pushed workflows")
+	cmd.Flags().BoolVar(&plan, "plan", false, "Show what pushing a directory would do without changing anything")
+
+	return cmd
+}
+
+func pushFile(client *api.Client, path string, opts pushOptions) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	if opts.interpolator != nil {
+		if data, err = opts.interpolator.Expand(data); err != nil {
+			return err
+		}
+	}
+
+	wf, err := workflow.ParseWorkflowJSON(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	changes, err := workflow.ApplyTransforms(opts.transforms, wf, workflow.StagePush)
+	if err != nil {
+		return err
+	}
+	if changes > 0 {
+		fmt.Printf("Applied %d transform change(s).\n", changes)
+	}
+
+	if opts.downgrade {
+		printDowngraded(opts.versionLimits.Downgrade(wf))
+	} else if violations := opts.versionLimits.Check(wf); len(violations) > 0 {
+		return &workflow.VersionError{Workflow: wf.Name, Violations: violations}
+	}
+
+	if opts.prunePinData {
+		if pruned := workflow.PrunePinData(wf); pruned > 0 {
+			fmt.Printf("Removed pinned data from %d node(s).\n", pruned)
+		}
+	}
+
+	if opts.create {
+		switch {
+		case opts.requireUniqueName:
+			if err := workflow.CheckUniqueName(client, wf.Name, ""); err != nil {
+				return err
+			}
+		case !opts.allowDuplicates:
+			if err := workflow.CheckDuplicateName(client, wf.Name); err != nil {
+				return err
+			}
+		}
+		if opts.autoLayout {
+			workflow.AutoLayout(wf)
+		}
+		if opts.staticData == workflow.StaticDataReset {
+			opts.staticData.Apply(wf, nil)
+		}
+		created, err := client.CreateWorkflow(wf)
+		if err != nil {
+			return fmt.Errorf("failed to create workflow: %w", err)
+		}
+		fmt.Printf("Created workflow: %s (ID: %s)\n", created.Name, created.ID)
+		return writePushSummary(opts.summaryFile, workflow.Change{
+			ID:       created.ID,
+			Name:     created.Name,
+			Action:   workflow.ActionCreated,
+			Filename: path,
+			Diff:     &workflow.NodeDiffStat{Added: len(wf.Nodes)},
+		})
+	}
+
+	if wf.ID == "" {
+		return fmt.Errorf("workflow has no ID. Use --create to create a new workflow")
+	}
+	if opts.requireUniqueName {
+		if err := workflow.CheckUniqueName(client, wf.Name, wf.ID); err != nil {
+			return err
+		}
+	}
+	var remote *api.Workflow
+	if !opts.force || opts.summaryFile != "" || opts.staticData.NeedsRemote() {
+		if remote, err = client.GetWorkflow(wf.ID); err != nil {
+			return fmt.Errorf("failed to get workflow: %w", err)
+		}
+	}
+	opts.staticData.Apply(wf, remote)
+
+	if !opts.force {
+		unchanged, filtered, err := workflow.CompareWith(wf, remote, opts.nodeFilter())
+		if err != nil {
+			return fmt.Errorf("failed to compare workflow: %w", err)
+		}
+		if filtered > 0 {
+			fmt.Printf("Ignored %d node(s) by type filter when comparing.\n", filtered)
+		}
+		if unchanged {
+			fmt.Printf("Unchanged workflow: %s (ID: %s)\n", wf.Name, wf.ID)
+			return writePushSummary(opts.summaryFile, workflow.Change{
+				ID:       wf.ID,
+				Name:     wf.Name,
+				Action:   workflow.ActionUnchanged,
+				Filename: path,
+				Diff:     &workflow.NodeDiffStat{},
+			})
+		}
+	}
+	updated, err := client.UpdateWorkflow(wf.ID, wf)
+	if err != nil {
+		return fmt.Errorf("failed to update workflow: %w", err)
+	}
+	fmt.Printf("Updated workflow: %s (ID: %s)\n", updated.Name, updated.ID)
+
+	change := workflow.Change{ID: updated.ID, Name: updated.Name, Action: workflow.ActionUpdated, Filename: path}
+	if remote != nil {
+		diff := workflow.DiffNodes(remote, wf, opts.nodeFilter())
+		change.Diff = &diff
+	}
+	return writePushSummary(opts.summaryFile, change)
+}
+
+// writePushSummary writes the change report requested with --summary-file:
+// Markdown if path ends in .md, JSON otherwise. An empty path writes nothing.
+func writePushSummary(path string, changes ...workflow.Change) error {
+	if path == "" {
+		return nil
+	}
+
+	summary := workflow.NewChangeSummary(changes)
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".md") {
+		data = []byte(summary.Markdown())
+	} else {
+		var err error
+		if data, err = workflow.MarshalFile(summary); err != nil {
+			return fmt.Errorf("failed to marshal summary: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
+
+func newVerifyCmd() *cobra.Command {
+	var signKeyPath string
+
+	cmd := &cobra.Command{
+		Use:   "verify <directory>",
+		Short: "Check pulled workflow files against their manifest hashes",
+		Long: `Recompute the SHA-256 hash of every workflow file in a pulled directory
+and compare it with the hash recorded in manifest.json at pull time.
+Files that were edited, deleted, or pulled before hashes were recorded
+are reported, and the command fails if any file doesn't match.
+
+With --sign-key, the manifest's signature is checked as well, using the
+HMAC key the directory was pulled with.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			manifest, err := loadManifest(dir)
+			if err != nil {
+				return err
+			}
+
+			var signatureErr error
+			if signKeyPath != "" {
+				key, err := workflow.ReadSigningKey(signKeyPath)
+				if err != nil {
+					return err
+				}
+				signatureErr = manifest.VerifySignature(key)
+			}
+
+			checks := manifest.VerifyFiles(dir)
+			drift := 0
+			for _, check := range checks {
+				if check.Status != workflow.FileOK {
+					drift++
+				}
+			}
+
+			if output.IsStructured(cmd) {
+				result := map[string]interface{}{"files": checks}
+				if signKeyPath != "" {
+					result["signatureValid"] = signatureErr == nil
+				}
+				if err := output.Print(cmd, result); err != nil {
+					return err
+				}
+			} else {
+				for _, check := range checks {
+					if check.Status != workflow.FileOK {
+						fmt.Printf("%-9s %s (%s)\n", check.Status, check.Filename, check.ID)
+					}
+				}
+				fmt.Printf("%d of %d file(s) match the manifest.\n", len(checks)-drift, len(checks))
+				if signKeyPath != "" && signatureErr == nil {
+					fmt.Println("Manifest signature is valid.")
+				}
+			}
+
+			if signatureErr != nil {
+				return signatureErr
+			}
+			if drift > 0 {
+				return fmt.Errorf("%d file(s) do not match the manifest", drift)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&signKeyPath, "sign-key", "", "Also check the manifest signature with the HMAC key in this file")
+
+	return cmd
+}
+
+func newCompareCmd() *cobra.Command {
+	var from, to string
+
+	cmd := &cobra.Command{
+		Use:   "compare --from <instance> --to <instance>",
+		Short: "Compare the workflows of two instances",
+		Long: `Compare the workflow inventories of two configured instances, e.g.
+staging and production, to catch drift before a release.
+
+Workflows are matched by name, since IDs differ between instances. The
+report lists workflows that only exist on --from (added), those that
+only exist on --to (removed), and those on both whose normalized
+definitions differ (changed). Changed workflows show their node counts
+the same way: +added (only on --from), -removed (only on --to), and
+~changed. Credentials are compared by name, and so are the workflows
+that Execute Workflow nodes call. Names used by several workflows on one
+instance can't be matched and are listed separately.
+
+The command fails if the instances differ.
+
+Examples:
+  n8nctl wf compare --from staging --to prod
+  n8nctl wf compare --from staging --to prod -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" || to == "" {
+				return fmt.Errorf("--from and --to are required")
+			}
+
+			fromWorkflows, err := listInstanceWorkflows(cmd, from)
+			if err != nil {
+				return err
+			}
+			toWorkflows, err := listInstanceWorkflows(cmd, to)
+			if err != nil {
+				return err
+			}
+
+			diff, err := workflow.CompareInventories(fromWorkflows, toWorkflows)
+			if err != nil {
+				return err
+			}
+
+			if output.IsStructured(cmd) {
+				if err := output.Print(cmd, diff); err != nil {
+					return err
+				}
+			} else {
+				printInventoryDiff(diff, from, to)
+			}
+
+			if diff.HasDrift() {
+				return fmt.Errorf("instances %s and %s differ", from, to)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Instance to compare from, e.g. staging")
+	cmd.Flags().StringVar(&to, "to", "", "Instance to compare to, e.g. prod")
+
+	return cmd
+}
+
+func newCopyToCmd() *cobra.Command {
+	var (
+		to                string
+		credentialMapFile string
+		force             bool
+		noFollow          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "copy-to <workflow-id> --to <instance>",
+		Short: "Copy a workflow and its sub-workflows to another instance",
+		Long: `Copy a workflow from the current instance (or --instance) to another
+configured instance, e.g. to promote it from staging to production.
+
+The workflow and the sub-workflows it calls are fetched like with
+'n8nctl workflow pull --recursive' and written to the target
+sub-workflows first. IDs differ between instances, so workflows are
+matched there by name: missing ones are created, existing ones updated,
+and unchanged ones left alone unless --force is given. A name used by
+several workflows on the target stops the copy. Calls to sub-workflows
+are rewritten to their IDs on the target, and credential IDs with
+--credential-map, a JSON file mapping source to target IDs:
+
+  {"12": "4", "15": "7"}
+
+Credentials the map doesn't cover are reported, since their IDs will
+rarely exist on the target. Workflows that call each other in a cycle
+can't be created sub-workflows first, so they stop the copy before
+anything is written.
+
+Updated workflows keep their trigger state (staticData) on the target;
+created ones start without any and inactive. The resulting IDs on the
+target are reported for each workflow.
+
+--no-follow copies only the given workflow. Its calls to sub-workflows
+then keep the source IDs and need to be fixed on the target.`,
+		Example: `  n8nctl workflow copy-to abc123 --to prod
+  n8nctl --instance staging workflow copy-to "Order sync" --to prod --credential-map creds.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if to == "" {
+				return fmt.Errorf("--to is required")
+			}
+
+			credentialMapping, err := loadCredentialMap(credentialMapFile)
+			if err != nil {
+				return err
+			}
+
+			source, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+			if from, _ := cmd.Flags().GetString("instance"); from == to {
+				return fmt.Errorf("--to names the instance the workflow is copied from")
+			} else if from == "" {
+				if cfg, err := config.Load(); err == nil && cfg.CurrentInstance == to {
+					return fmt.Errorf("--to names the current instance; copy from another one with --instance")
+				}
+			}
+			target, err := cli.NewInstanceClient(cmd, to)
+			if err != nil {
+				return err
+			}
+
+			var pulled *workflow.PullResult
+			_, err = workflow.WithID(source, args[0], func(id string) error {
+				puller := workflow.NewRecursivePuller(source)
+				puller.NoFollow = noFollow
+				pulled, err = puller.Pull(id)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			pusher := workflow.NewPusher(target, "")
+			pusher.CredentialMapping = credentialMapping
+			pusher.Force = force
+			entries, copyErr := pusher.Copy(pulled)
+
+			if output.IsStructured(cmd) {
+				if entries == nil {
+					entries = []workflow.CopyEntry{}
+				}
+				if err := output.Print(cmd, entries); err != nil {
+					return err
+				}
+				return copyErr
+			}
+
+			if len(entries) > 0 {
+				fmt.Println()
+				table := output.NewTable(cmd, "SOURCE ID", "TARGET ID", "ACTION", "NAME")
+				for _, e := range entries {
+					table.AddRow(e.SourceID, e.TargetID, string(e.Action), e.Name)
+				}
+				table.Print()
+			}
+			if copyErr != nil {
+				if len(entries) > 0 {
+					logging.Warn(fmt.Sprintf("Copy stopped after %d of %d workflow(s).", len(entries), len(pulled.Workflows)),
+						"copied", len(entries), "total", len(pulled.Workflows))
+				}
+				return copyErr
+			}
+			fmt.Printf("\nCopied %d workflow(s) to %s.\n", len(entries), to)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Instance to copy to, e.g. prod (required)")
+	cmd.Flags().StringVar(&credentialMapFile, "credential-map", "", "JSON file mapping source credential IDs to target ones")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Update workflows on the target even if they are unchanged")
+	cmd.Flags().BoolVar(&noFollow, "no-follow", false, "Copy only the given workflow, not its sub-workflows")
+
+	return cmd
+}
+
+// listInstanceWorkflows lists every workflow of the named instance
+func listInstanceWorkflows(cmd *cobra.Command, instance string) ([]api.Workflow, error) {
+	client, err := cli.NewInstanceClient(cmd, instance)
+	if err != nil {
+		return nil, err
+	}
+	list, err := client.ListWorkflows(api.ListWorkflowsOptions{ExcludePinnedData: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows of %s: %w", instance, err)
+	}
+	return list.Data, nil
+}
+
+// printInventoryDiff prints the compare report grouped by change
+func printInventoryDiff(diff *workflow.InventoryDiff, from, to string) {
+	if len(diff.Added) > 0 {
+		fmt.Printf("Only on %s (%d):\n", from, len(diff.Added))
+		for _, e := range diff.Added {
+			fmt.Printf("  + %s (%s)\n", e.Name, e.FromID)
+		}
+		fmt.Println()
+	}
+	if len(diff.Removed) > 0 {
+		fmt.Printf("Only on %s (%d):\n", to, len(diff.Removed))
+		for _, e := range diff.Removed {
+			fmt.Printf("  - %s (%s)\n", e.Name, e.ToID)
+		}
+		fmt.Println()
+	}
+	if len(diff.Changed) > 0 {
+		fmt.Printf("Changed (%d):\n", len(diff.Changed))
+		for _, e := range diff.Changed {
+			fmt.Printf("  ~ %s (%s -> %s) nodes %s\n", e.Name, e.FromID, e.ToID, e.Diff)
+		}
+		fmt.Println()
+	}
+	if len(diff.Duplicates) > 0 {
+		fmt.Printf("Not compared, name used more than once (%d):\n", len(diff.Duplicates))
+		for _, name := range diff.Duplicates {
+			fmt.Printf("  ! %s\n", name)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("%d added, %d removed, %d changed, %d unchanged\n",
+		len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged)
+}
+
+// validateDirectory checks a pulled directory's manifest against its files
+func validateDirectory(cmd *cobra.Command, dir string) error {
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return err
+	}
+	problems := manifest.Validate(dir)
+
+	if output.IsStructured(cmd) {
+		if problems == nil {
+			problems = []string{}
+		}
+		if err := output.Print(cmd, map[string]interface{}{
+			"directory": dir,
+			"workflows": len(manifest.Workflows),
+			"problems":  problems,
+		}); err != nil {
+			return err
+		}
+	} else if len(problems) == 0 {
+		fmt.Printf("%s: %d workflow(s), no problems found.\n", dir, len(manifest.Workflows))
+	} else {
+		fmt.Printf("%s: %d problem(s):\n", dir, len(problems))
+		for _, problem := range problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("manifest has %d problem(s)", len(problems))
+	}
+	return nil
+}
+
+// manifestInfo is a manifest as printed by workflow manifest
+type manifestInfo struct {
+	*workflow.Manifest
+	PushOrder []string `json:"pushOrder"`
+	Problems  []string `json:"problems,omitempty"`
+}
+
+func newManifestCmd() *cobra.Command {
+	var from string
+
+	cmd := &cobra.Command{
+		Use:   "manifest [directory]",
+		Short: "Print a manifest with its computed push order",
+		Long: `Print the manifest.json of a pulled directory as JSON, together with the
+order 'workflow push' would push the workflows in. The manifest is
+checked on the way: the root workflow must be listed, every workflow file
+must exist, and workflows caught in a dependency cycle, which push would
+leave out, are reported. The command fails if there are problems.
+
+With --from, the manifest is built from the server instead, by walking the
+sub-workflows of the given workflow like 'workflow pull --recursive' does,
+without writing any files.`,
+		Example: `  n8nctl workflow manifest ./workflows
+  n8nctl workflow manifest --from abc123 | jq .pushOrder`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (from == "") == (len(args) == 0) {
+				return fmt.Errorf("specify either a directory or --from")
+			}
+
+			var manifest *workflow.Manifest
+			dir := ""
+			if from != "" {
+				client, err := cli.NewClient(cmd)
+				if err != nil {
+					return err
+				}
+				var result *workflow.PullResult
+				_, err = workflow.WithID(client, from, func(id string) error {
+					result, err = workflow.NewRecursivePuller(client).Pull(id)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				manifest = result.Manifest
+			} else {
+				dir = args[0]
+				var err error
+				if manifest, err = loadManifest(dir); err != nil {
+					return err
+				}
+			}
+
+			info := manifestInfo{
+				Manifest:  manifest,
+				PushOrder: manifest.GetPushOrder(),
+				Problems:  manifest.Validate(dir),
+			}
+			if err := output.Print(cmd, info); err != nil {
+				return err
+			}
+			if len(info.Problems) > 0 {
+				for _, problem := range info.Problems {
+					logging.Warn(problem, "problem", problem)
+				}
+				return fmt.Errorf("manifest has %d problem(s)", len(info.Problems))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Build the manifest from this workflow on the server")
+
+	return cmd
+}
+
+// printDowngraded lists nodes whose typeVersion was lowered
+func printDowngraded(downgraded []workflow.VersionViolation) {
+	if len(downgraded) == 0 {
+		return
+	}
+	fmt.Printf("Downgraded %d node(s):\n", len(downgraded))
+	for _, v := range downgraded {
+		fmt.Printf("  - %s\n", v)
+	}
+}
+
+// errNoManifest is returned by loadManifest for a directory without a
+// manifest.json
+var errNoManifest = errors.New("no manifest.json found in directory")
+
+// loadManifest reads the manifest.json of a pulled directory
+func loadManifest(dir string) (*workflow.Manifest, error) {
+	manifestPath := filepath.Join(dir, "manifest.json")
+	data, err := os.ReadFile(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNoManifest
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", manifestPath, err)
+	}
+
+	var manifest workflow.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", manifestPath, workflow.JSONError(data, err))
+	}
+
+	return &manifest, nil
+}
+
+// saveManifest writes manifest to the manifest.json of dir
+func saveManifest(dir string, manifest *workflow.Manifest) error {
+	data, err := workflow.MarshalFile(manifest)
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+func pushDirectory(client *api.Client, dir string, opts pushOptions) error {
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	// Push in dependency order (sub-workflows first)
+	pusher := workflow.NewPusher(client, dir)
+	pusher.Force = opts.force
+	pusher.AutoLayout = opts.autoLayout
+	pusher.AllowDuplicates = opts.allowDuplicates
+	pusher.RequireUniqueNames = opts.requireUniqueName
+	pusher.PrunePinData = opts.prunePinData
+	pusher.NodeFilter = opts.nodeFilter()
+	pusher.Transforms = opts.transforms
+	pusher.Interpolator = opts.interpolator
+	pusher.VersionLimits = opts.versionLimits
+	pusher.Downgrade = opts.downgrade
+	pusher.DiffForced = opts.summaryFile != ""
+	pusher.StaticData = opts.staticData
+	pusher.ContinueOnError = opts.continueOnError
+	result, err := pusher.Push(manifest, opts.create)
+	if opts.summaryFile != "" {
+		result.AddSkipped(manifest)
+		if summaryErr := writePushSummary(opts.summaryFile, result.Changes...); summaryErr != nil && err == nil {
+			err = summaryErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.Transformed > 0 {
+		fmt.Printf("Applied %d transform change(s).\n", result.Transformed)
+	}
+	printDowngraded(result.Downgraded)
+
+	if result.NodesFiltered > 0 {
+		fmt.Printf("Ignored %d node(s) by type filter when comparing.\n", result.NodesFiltered)
+	}
+	if result.PinDataPruned > 0 {
+		fmt.Printf("Removed pinned data from %d node(s).\n", result.PinDataPruned)
+	}
+
+	if result.Failed > 0 || result.Skipped > 0 {
+		fmt.Printf("\nPushed %d of %d workflow(s): %d created, %d updated, %d unchanged, %d failed, %d skipped.\n",
+			result.Created+result.Updated+result.Unchanged, len(manifest.Workflows),
+			result.Created, result.Updated, result.Unchanged, result.Failed, result.Skipped)
+		for _, c := range result.Changes {
+			if c.Action == workflow.ActionFailed || c.Action == workflow.ActionSkipped {
+				fmt.Printf("  %s %s: %s\n", c.Action, c.Name, c.Error)
+			}
+		}
+		return fmt.Errorf("%d workflow(s) failed, %d skipped", result.Failed, result.Skipped)
+	}
+
+	fmt.Printf("\nPushed %d workflow(s) successfully: %d created, %d updated, %d unchanged.\n",
+		len(manifest.Workflows), result.Created, result.Updated, result.Unchanged)
+	return nil
+}
+
+// planDirectory prints what pushing dir would do
+func planDirectory(cmd *cobra.Command, client *api.Client, dir string, opts pushOptions) error {
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	pusher := workflow.NewPusher(client, dir)
+	pusher.PrunePinData = opts.prunePinData
+	pusher.NodeFilter = opts.nodeFilter()
+	pusher.Transforms = opts.transforms
+	pusher.Interpolator = opts.interpolator
+	pusher.VersionLimits = opts.versionLimits
+	pusher.Downgrade = opts.downgrade
+	pusher.StaticData = opts.staticData
+	entries, err := pusher.Plan(manifest)
+	if err != nil {
+		return err
+	}
+
+	if output.IsStructured(cmd) {
+		if entries == nil {
+			entries = []workflow.PlanEntry{}
+		}
+		if err := output.Print(cmd, entries); err != nil {
+			return err
+		}
+		return planConflictError(entries)
+	}
+
+	counts := make(map[workflow.PlanAction]int)
+	for _, e := range entries {
+		counts[e.Action]++
+		line := fmt.Sprintf("%s %-9s  %s", planSymbol(e.Action), e.Action, e.Name)
+		switch e.Action {
+		case workflow.PlanCreate:
+			line += fmt.Sprintf(" (new, from %s)", e.Filename)
+		case workflow.PlanConflict:
+			line += ": " + e.Error
+		default:
+			line += fmt.Sprintf(" (ID: %s", e.RemoteID)
+			if e.MatchedBy == workflow.MatchedByName {
+				line += ", matched by name"
+			}
+			line += ")"
+		}
+		if e.Action == workflow.PlanUpdate {
+			if *e.Diff == (workflow.NodeDiffStat{}) {
+				line += "  nodes unchanged, settings or connections differ"
+			} else {
+				line += "  nodes " + e.Diff.String()
+			}
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\nPlan: %d to create, %d to update, %d unchanged",
+		counts[workflow.PlanCreate], counts[workflow.PlanUpdate], counts[workflow.PlanUnchanged])
+	if counts[workflow.PlanConflict] > 0 {
+		fmt.Printf(", %d conflict(s)", counts[workflow.PlanConflict])
+	}
+	fmt.Println(".")
+	return planConflictError(entries)
+}
+
+// planConflictError fails a plan with workflows that can't be matched
+func planConflictError(entries []workflow.PlanEntry) error {
+	conflicts := 0
+	for _, e := range entries {
+		if e.Action == workflow.PlanConflict {
+			conflicts++
+		}
+	}
+	if conflicts > 0 {
+		return fmt.Errorf("%d workflow(s) match several workflows by name", conflicts)
+	}
+	return nil
+}
+
+// planSymbol marks plan lines like a diff
+func planSymbol(action workflow.PlanAction) string {
+	switch action {
+	case workflow.PlanCreate:
+		return "+"
+	case workflow.PlanUpdate:
+		return "~"
+	case workflow.PlanConflict:
+		return "!"
+	}
+	return "="
+}
+
+func newRestoreCmd() *cobra.Command {
+	var (
+		credentialMapFile string
+		outDir            string
+		allowDuplicates   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore <directory>",
+		Short: "Recreate workflows from a pulled directory",
+		Long: `Recreate all workflows of a pulled directory on the current instance.
+
+Workflows are created in dependency order (sub-workflows first) and
+receive new IDs. References between them are rewritten to the new IDs.
+Credential IDs can be rewritten too, using a JSON file that maps old
+credential IDs to the IDs of the credentials on the target instance:
+
+  {"12": "4", "15": "7"}
+
+Afterwards, the workflow files and manifest are rewritten with the new
+IDs, so the directory can be used for regular pushes to the new instance.
+Use --out to write them to a different directory instead.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := args[0]
+			manifest, err := loadManifest(dir)
+			if err != nil {
+				return err
+			}
+
+			credentialMapping, err := loadCredentialMap(credentialMapFile)
+			if err != nil {
+				return err
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			pusher := workflow.NewPusher(client, dir)
+			pusher.CredentialMapping = credentialMapping
+			pusher.AllowDuplicates = allowDuplicates
+			result, err := pusher.Push(manifest, true)
+			if err != nil {
+				if len(result.IDMapping) > 0 {
+					logging.Warn(fmt.Sprintf("Restore stopped after creating %d workflow(s); their new IDs were printed above.", len(result.IDMapping)),
+						"created", len(result.IDMapping))
+				}
+				return err
+			}
+
+			if outDir == "" {
+				outDir = dir
+			}
+			if err := os.MkdirAll(outDir, 0755); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+
+			restored := manifest.Remap(result.IDMapping)
+			if len(credentialMapping) > 0 {
+				restored.Credentials = remapCredentials(restored.Credentials, credentialMapping)
+				for id, meta := range restored.Workflows {
+					meta.Credentials = remapCredentials(meta.Credentials, credentialMapping)
+					restored.Workflows[id] = meta
+				}
+			}
+
+			for oldID, wf := range result.Workflows {
+				meta := manifest.Workflows[oldID]
+				data, err := workflow.MarshalFile(wf)
+				if err != nil {
+					return fmt.Errorf("failed to marshal workflow %s: %w", wf.ID, err)
+				}
+				if err := os.WriteFile(filepath.Join(outDir, meta.Filename), data, 0644); err != nil {
+					return fmt.Errorf("failed to write file: %w", err)
+				}
+				newID := result.IDMapping[oldID]
+				restoredMeta := restored.Workflows[newID]
+				restoredMeta.SHA256 = workflow.ContentHash(data)
+				restored.Workflows[newID] = restoredMeta
+			}
+
+			manifestPath := filepath.Join(outDir, "manifest.json")
+			manifestData, err := workflow.MarshalFile(restored)
+			if err != nil {
+				return fmt.Errorf("failed to marshal manifest: %w", err)
+			}
+			if err := os.WriteFile(manifestPath, manifestData, 0644); err != nil {
+				return fmt.Errorf("failed to write manifest: %w", err)
+			}
+
+			fmt.Printf("\nRestored %d workflow(s). Manifest with new IDs: %s\n", result.Created, manifestPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&credentialMapFile, "credential-map", "", "JSON file mapping old credential IDs to new ones")
+	cmd.Flags().StringVar(&outDir, "out", "", "Write rewritten files and manifest here instead of the input directory")
+	cmd.Flags().BoolVar(&allowDuplicates, "allow-duplicates", false, "Create workflows even if one with the same name exists")
+
+	return cmd
+}
+
+// loadCredentialMap reads a JSON file mapping old credential IDs to new
+// ones. An empty path gives no mapping.
+func loadCredentialMap(path string) (map[string]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential map: %w", err)
+	}
+	var mapping map[string]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse credential map: %w", err)
+	}
+	return mapping, nil
+}
+
+// remapCredentials replaces credential IDs according to mapping
+func remapCredentials(refs []workflow.CredentialRef, mapping map[string]string) []workflow.CredentialRef {
+	remapped := make([]workflow.CredentialRef, len(refs))
+	for i, ref := range refs {
+		if newID, ok := mapping[ref.ID]; ok && ref.ID != "" {
+			ref.ID = newID
+		}
+		remapped[i] = ref
+	}
+	return remapped
+}
+
+func newRunCmd() *cobra.Command {
+	var (
+		inputJSON   string
+		waitOpts    waitOptions
+		webhookPath string
+		method      string
+		dryRun      bool
+		csvPath     string
+		concurrency int
+		runTimeout  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run <workflow-id>...",
+		Short: "Execute one or more workflows",
+		Long: `Execute a workflow via the API or via a webhook trigger.
+
+By default, uses the /execute API endpoint. If your n8n instance doesn't
+support this endpoint (returns 405), use --webhook to trigger via webhook instead.
+
+When several workflow IDs are given, all of them are started and --wait
+shows a status table that refreshes until every execution has finished
+or --timeout elapses.
+
+--dry-run prints the request that would be sent, including the parsed
+input, and checks that each workflow exists and has a trigger (or, with
+--webhook, a Webhook node for the path) without executing anything.
+
+--csv runs the workflow once per row of a CSV file. The header row names
+the input fields, so each row becomes an input object of strings. Up to
+--concurrency executions are started at a time; with --wait, the status
+of all of them is tracked until they finish. Combine with --dry-run to
+preview the payloads.
+
+--run-timeout limits the request that starts the workflow (default: the
+instance's run-timeout setting, or 30m). With a single workflow and
+--wait, or with --webhook, n8n answers only once the workflow has
+finished, so the limit must cover the whole run. --timeout instead limits
+how long the status of several executions is tracked.
+
+Webhook calls don't return an execution ID. With --webhook and --wait,
+the workflow's executions are polled for the one the call started: a new
+execution started closest to the time of the call, which matters when
+the workflow runs concurrently. It is then followed until it finishes.
+
+Executions paused at a Wait node or waiting for a webhook call are given
+up on after --waiting-timeout once nothing else is left to wait for. The
+command then exits with code 3, and the execution can be checked later
+with 'n8nctl execution view <id>'.
+
+Examples:
+  n8nctl wf run abc123                         # Execute via API
+  n8nctl wf run abc123 def456 --wait           # Run several and watch progress
+  n8nctl wf run abc123 --webhook my-hook-path  # Trigger via webhook (GET)
+  n8nctl wf run abc123 --webhook my-hook-path --method POST
+  n8nctl wf run abc123 -i '{"id":1}' --dry-run   # Preview without running
+  n8nctl wf run abc123 --csv rows.csv --wait     # Run once per CSV row`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if csvPath != "" {
+				if len(args) > 1 {
+					return fmt.Errorf("--csv can only be used with a single workflow")
+				}
+				if inputJSON != "" || webhookPath != "" {
+					return fmt.Errorf("--csv cannot be combined with --input or --webhook")
+				}
+				if concurrency < 1 {
+					return fmt.Errorf("--concurrency must be at least 1")
+				}
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+			client.SetTimeouts(0, runTimeout)
+
+			// A single plain run resolves its workflow lazily, see
+			// workflow.WithID; the other modes need the IDs up front
+			if csvPath != "" || dryRun || webhookPath != "" || len(args) > 1 {
+				for i, ref := range args {
+					if args[i], err = resolveWorkflowID(client, ref); err != nil {
+						return err
+					}
+				}
+			}
+
+			if csvPath != "" {
+				rows, err := readCSVInputs(csvPath)
+				if err != nil {
+					return err
+				}
+				if dryRun {
+					return previewCSVRun(cmd, client, args[0], rows)
+				}
+				return runCSV(cmd, client, args[0], rows, concurrency, waitOpts)
+			}
+
+			if dryRun {
+				var inputData map[string]interface{}
+				if inputJSON != "" {
+					if err := json.Unmarshal([]byte(inputJSON), &inputData); err != nil {
+						return fmt.Errorf("invalid input JSON: %w", err)
+					}
+				}
+				return previewRun(cmd, client, args, inputData, waitOpts.wait, webhookPath, method)
+			}
+
+			// Webhook mode: trigger via webhook URL instead of execute API
+			if webhookPath != "" {
+				if len(args) > 1 {
+					return fmt.Errorf("--webhook can only be used with a single workflow")
+				}
+				// Webhook calls don't return an execution ID, so remember
+				// the executions that already exist to find the new one
+				var known map[string]bool
+				if waitOpts.wait {
+					if known, err = recentExecutionIDs(client, args[0]); err != nil {
+						return err
+					}
+				}
+				triggeredAt := time.Now()
+
+				respBody, err := client.TriggerWebhook(webhookPath, method)
+				if err != nil {
+					return fmt.Errorf("failed to trigger webhook: %w", err)
+				}
+
+				if waitOpts.wait {
+					if !output.IsStructured(cmd) && len(respBody) > 0 {
+						fmt.Printf("Response: %s\n", string(respBody))
+					}
+					return waitForWebhookExecution(cmd, client, args[0], known, triggeredAt, waitOpts)
+				}
+
+				if output.IsStructured(cmd) {
+					// Try to pretty-print if valid JSON, otherwise print raw
+					var parsed interface{}
+					if json.Unmarshal(respBody, &parsed) == nil {
+						return output.Print(cmd, parsed)
+					}
+				}
+
+				fmt.Printf("Webhook triggered successfully.\n")
+				if len(respBody) > 0 {
+					fmt.Printf("Response: %s\n", string(respBody))
+				}
+				return nil
+			}
+
+			var inputData map[string]interface{}
+			if inputJSON != "" {
+				if err := json.Unmarshal([]byte(inputJSON), &inputData); err != nil {
+					return fmt.Errorf("invalid input JSON: %w", err)
+				}
+			}
+
+			if len(args) > 1 {
+				return runMultiple(cmd, client, args, inputData, waitOpts)
+			}
+
+			var execution *api.Execution
+			args[0], err = workflow.WithID(client, args[0], func(id string) error {
+				execution, err = client.ExecuteWorkflow(id, inputData, waitOpts.wait)
+				return err
+			})
+			if err != nil {
+				if strings.Contains(err.Error(), "405") {
+					logging.Info(fmt.Sprintf("Hint: The /execute API endpoint returned 405. This endpoint may not be available on your n8n instance.\n"+
+						"Use --webhook to trigger the workflow via its webhook URL instead:\n  n8nctl wf run %s --webhook <webhook-path>", args[0]),
+						"workflow", args[0])
+				}
+				return fmt.Errorf("failed to execute workflow: %w", err)
+			}
+
+			// n8n answers a waiting run once the execution pauses, e.g. at
+			// a Wait node, so keep polling until it finishes or stalls
+			if waitOpts.wait && execution.ID != "" && !progress.IsTerminal(execution.Status) {
+				executions, err := waitOpts.track(cmd, client, []string{execution.ID})
+				if len(executions) > 0 && executions[0] != nil {
+					execution = executions[0]
+				}
+				if err != nil {
+					if output.IsStructured(cmd) {
+						_ = output.Print(cmd, execution)
+					}
+					return err
+				}
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, execution)
+			}
+
+			fmt.Printf("Execution ID: %s\n", execution.ID)
+			fmt.Printf("Status: %s\n", execution.Status)
+			if execution.Finished {
+				fmt.Printf("Finished: yes\n")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&inputJSON, "input", "i", "", "Input data as JSON")
+	cmd.Flags().BoolVarP(&waitOpts.wait, "wait", "w", false, "Wait for execution to complete")
+	cmd.Flags().DurationVar(&waitOpts.timeout, "timeout", 0, "Maximum time to wait for multiple executions (0 = no limit)")
+	cmd.Flags().DurationVar(&waitOpts.waitingTimeout, "waiting-timeout", 30*time.Second, "With --wait, stop waiting for executions paused at a Wait node after this long (0 = no limit)")
+	cmd.Flags().DurationVar(&runTimeout, "run-timeout", 0, "Maximum duration of the execute or webhook request (default: the run-timeout setting or 30m)")
+	cmd.Flags().StringVar(&webhookPath, "webhook", "", "Trigger via webhook path instead of execute API")
+	cmd.Flags().StringVar(&method, "method", "GET", "HTTP method for webhook trigger")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the request that would be sent without executing")
+	cmd.Flags().StringVar(&csvPath, "csv", "", "Run once per row of this CSV file, using the header row as input keys")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 4, "Maximum number of executions started at once with --csv")
+
+	return cmd
+}
+
+// runPreview describes the request workflow run would send
+type runPreview struct {
+	WorkflowID   string                 `json:"workflowId"`
+	WorkflowName string                 `json:"workflowName,omitempty"`
+	Active       bool                   `json:"active"`
+	Method       string                 `json:"method"`
+	URL          string                 `json:"url"`
+	Body         map[string]interface{} `json:"body,omitempty"`
+	Problems     []string               `json:"problems,omitempty"`
+}
+
+// previewRun prints what workflow run would do for each workflow and
+// checks that it can be triggered, without executing anything
+func previewRun(cmd *cobra.Command, client *api.Client, ids []string, inputData map[string]interface{}, wait bool, webhookPath, method string) error {
+	if webhookPath != "" && len(ids) > 1 {
+		return fmt.Errorf("--webhook can only be used with a single workflow")
+	}
+
+	previews := make([]runPreview, 0, len(ids))
+	problems := 0
+	for _, id := range ids {
+		preview := runPreview{WorkflowID: id}
+		if webhookPath != "" {
+			preview.Method = strings.ToUpper(method)
+			webhookURL, err := client.WebhookURL(webhookPath)
+			if err != nil {
+				preview.Problems = append(preview.Problems, err.Error())
+			}
+			preview.URL = webhookURL
+			if inputData != nil {
+				preview.Problems = append(preview.Problems, "--input is not sent with webhook triggers")
+			}
+		} else {
+			preview.Method = http.MethodPost
+			preview.URL = client.ExecuteURL(id, wait && len(ids) == 1)
+			preview.Body = api.ExecuteRequestBody(inputData)
+		}
+
+		wf, err := client.GetWorkflow(id)
+		if err != nil {
+			preview.Problems = append(preview.Problems, fmt.Sprintf("workflow not found: %v", err))
+		} else {
+			preview.WorkflowName = wf.Name
+			preview.Active = wf.Active
+			preview.Problems = append(preview.Problems, triggerProblems(wf, webhookPath)...)
+		}
+
+		problems += len(preview.Problems)
+		previews = append(previews, preview)
+	}
+
+	if output.IsStructured(cmd) {
+		if err := output.Print(cmd, previews); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println("Dry run: nothing was executed.")
+		for _, p := range previews {
+			fmt.Println()
+			if p.WorkflowName != "" {
+				fmt.Printf("Workflow: %s (%s)\n", p.WorkflowName, p.WorkflowID)
+			} else {
+				fmt.Printf("Workflow ID: %s\n", p.WorkflowID)
+			}
+			fmt.Printf("Request: %s %s\n", p.Method, p.URL)
+			if p.Body != nil {
+				body, err := json.MarshalIndent(p.Body, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal request body: %w", err)
+				}
+				fmt.Printf("Body:\n%s\n", body)
+			}
+			for _, problem := range p.Problems {
+				fmt.Printf("Problem: %s\n", problem)
+			}
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("dry run found %d problem(s)", problems)
+	}
+	return nil
+}
+
+// triggerProblems reports why a workflow can't be started the requested
+// way: via a Webhook node listening on webhookPath, or otherwise via any
+// trigger node
+func triggerProblems(wf *api.Workflow, webhookPath string) []string {
+	if webhookPath != "" {
+		want := strings.Trim(webhookPath, "/")
+		for _, node := range wf.Nodes {
+			nodeType, _ := node["type"].(string)
+			params, _ := node["parameters"].(map[string]interface{})
+			path, _ := params["path"].(string)
+			if nodeType == "n8n-nodes-base.webhook" && strings.Trim(path, "/") == want {
+				if !wf.Active {
+					return []string{"workflow is not active, so its production webhook is not registered"}
+				}
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("no Webhook node with path %q", webhookPath)}
+	}
+
+	for _, node := range wf.Nodes {
+		nodeType, _ := node["type"].(string)
+		if workflow.IsTriggerType(nodeType) {
+			return nil
+		}
+	}
+	return []string{"workflow has no trigger node"}
+}
+
+// readCSVInputs reads a CSV file into one input object per row, keyed by
+// the header row. Header names must be unique, or one column's values
+// would silently replace another's.
+func readCSVInputs(path string) ([]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open CSV file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV file: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("CSV file %s has no data rows", path)
+	}
+
+	header := records[0]
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		header[i] = strings.TrimSpace(column)
+		if header[i] == "" {
+			return nil, fmt.Errorf("CSV column %d has an empty header", i+1)
+		}
+		if first, ok := columns[header[i]]; ok {
+			return nil, fmt.Errorf("CSV columns %d and %d have the same header %q", first+1, i+1, header[i])
+		}
+		columns[header[i]] = i
+	}
+
+	rows := make([]map[string]interface{}, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]interface{}, len(header))
+		for i, value := range record {
+			row[header[i]] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// csvRun is the outcome of one CSV row's execution
+type csvRun struct {
+	Row         int    `json:"row"`
+	ExecutionID string `json:"executionId,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// previewCSVRun prints the payload each CSV row would be sent with
+func previewCSVRun(cmd *cobra.Command, client *api.Client, id string, rows []map[string]interface{}) error {
+	var problems []string
+	wf, err := client.GetWorkflow(id)
+	if err != nil {
+		problems = append(problems, fmt.Sprintf("workflow not found: %v", err))
+	} else {
+		problems = triggerProblems(wf, "")
+	}
+
+	if output.IsStructured(cmd) {
+		bodies := make([]interface{}, len(rows))
+		for i, row := range rows {
+			bodies[i] = api.ExecuteRequestBody(row)
+		}
+		if err := output.Print(cmd, map[string]interface{}{
+			"workflowId": id,
+			"url":        client.ExecuteURL(id, false),
+			"bodies":     bodies,
+			"problems":   problems,
+		}); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println("Dry run: nothing was executed.")
+		if wf != nil {
+			fmt.Printf("Workflow: %s (%s)\n", wf.Name, id)
+		}
+		fmt.Printf("Request: %s %s (x%d)\n", http.MethodPost, client.ExecuteURL(id, false), len(rows))
+		for i, row := range rows {
+			body, err := json.Marshal(api.ExecuteRequestBody(row))
+			if err != nil {
+				return fmt.Errorf("failed to marshal request body: %w", err)
+			}
+			fmt.Printf("Row %d: %s\n", i+1, body)
+		}
+		for _, problem := range problems {
+			fmt.Printf("Problem: %s\n", problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("dry run found %d problem(s)", len(problems))
+	}
+	return nil
+}
+
+// waitOptions controls how run waits for executions it started
+type waitOptions struct {
+	wait           bool
+	timeout        time.Duration
+	waitingTimeout time.Duration
+	// concurrency bounds the status requests in flight (0 = default)
+	concurrency int
+}
+
+const (
+	// webhookFindTimeout is how long run --webhook --wait looks for the
+	// execution started by the webhook call
+	webhookFindTimeout = 30 * time.Second
+	// webhookFindInterval is the pause between two such lookups
+	webhookFindInterval = time.Second
+	// webhookClockSkew is the difference between the local and the
+	// server clock tolerated when matching start times
+	webhookClockSkew = 5 * time.Second
+	// webhookRecentExecutions is how many of the workflow's newest
+	// executions are looked at
+	webhookRecentExecutions = 20
+)
+
+// recentExecutionIDs returns the IDs of the newest executions of a
+// workflow
+func recentExecutionIDs(client *api.Client, workflowID string) (map[string]bool, error) {
+	result, err := client.ListExecutions(api.ListExecutionsOptions{WorkflowID: workflowID, Limit: webhookRecentExecutions})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list executions: %w", err)
+	}
+	ids := make(map[string]bool, len(result.Data))
+	for _, exec := range result.Data {
+		ids[exec.ID] = true
+	}
+	return ids, nil
+}
+
+// findWebhookExecution polls the executions of a workflow for the one a
+// webhook call at triggeredAt started: among those not in known, the one
+// whose start is closest to the call. It reports how many executions
+// qualified, so that concurrent runs can be pointed out.
+func findWebhookExecution(client *api.Client, workflowID string, known map[string]bool, triggeredAt time.Time) (*api.Execution, int, error) {
+	deadline := time.Now().Add(webhookFindTimeout)
+	for {
+		result, err := client.ListExecutions(api.ListExecutionsOptions{WorkflowID: workflowID, Limit: webhookRecentExecutions})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list executions: %w", err)
+		}
+
+		var best *api.Execution
+		var bestDistance time.Duration
+		candidates := 0
+		for i := range result.Data {
+			exec := &result.Data[i]
+			if known[exec.ID] || exec.StartedAt == nil || exec.StartedAt.Before(triggeredAt.Add(-webhookClockSkew)) {
+				continue
+			}
+			if exec.Mode != "" && exec.Mode != "webhook" {
+				continue
+			}
+			candidates++
+			distance := exec.StartedAt.Sub(triggeredAt)
+			if distance < 0 {
+				distance = -distance
+			}
+			if best == nil || distance < bestDistance {
+				best, bestDistance = exec, distance
+			}
+		}
+		if best != nil {
+			return best, candidates, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, 0, fmt.Errorf("no execution of workflow %s started by the webhook call was found within %s; n8n may be set not to save these executions", workflowID, webhookFindTimeout)
+		}
+		time.Sleep(webhookFindInterval)
+	}
+}
+
+// waitForWebhookExecution finds the execution a webhook call started and
+// follows it until it finishes, like run --wait does for the execute API
+func waitForWebhookExecution(cmd *cobra.Command, client *api.Client, workflowID string, known map[string]bool, triggeredAt time.Time, opts waitOptions) error {
+	execution, candidates, err := findWebhookExecution(client, workflowID, known, triggeredAt)
+	if err != nil {
+		return err
+	}
+	if candidates > 1 {
+		logging.Warn(fmt.Sprintf("%d executions started around the webhook call; following %s, the one started closest to it.", candidates, execution.ID),
+			"candidates", candidates, "execution", execution.ID)
+	}
+
+	if !progress.IsTerminal(execution.Status) {
+		executions, err := opts.track(cmd, client, []string{execution.ID})
+		if len(executions) > 0 && executions[0] != nil {
+			execution = executions[0]
+		}
+		if err != nil {
+			if output.IsStructured(cmd) {
+				_ = output.Print(cmd, execution)
+			}
+			return err
+		}
+	}
+
+	if output.IsStructured(cmd) {
+		return output.Print(cmd, execution)
+	}
+
+	fmt.Printf("Execution ID: %s\n", execution.ID)
+	fmt.Printf("Status: %s\n", execution.Status)
+	if execution.Finished {
+		fmt.Printf("Finished: yes\n")
+	}
+	return nil
+}
+
+// track waits for executions with a progress tracker. Executions paused
+// for longer than the waiting timeout end the wait with a
+// *progress.WaitingError.
+func (o waitOptions) track(cmd *cobra.Command, client *api.Client, ids []string) ([]*api.Execution, error) {
+	tracker := progress.NewTracker(client, ids)
+	if output.IsStructured(cmd) {
+		tracker.Quiet()
+	}
+	tracker.SetWaitingTimeout(o.waitingTimeout)
+	tracker.SetConcurrency(o.concurrency)
+	executions, err := tracker.Wait(o.timeout)
+
+	var waiting *progress.WaitingError
+	if errors.As(err, &waiting) {
+		for _, id := range waiting.IDs {
+			logging.Info(fmt.Sprintf("Paused awaiting external input (a Wait node or webhook). Check on it later with: n8nctl execution view %s", id),
+				"execution", id)
+		}
+	}
+	return executions, err
+}
+
+// runCSV executes a workflow once per CSV row, starting at most
+// concurrency executions at a time
+func runCSV(cmd *cobra.Command, client *api.Client, id string, rows []map[string]interface{}, concurrency int, opts waitOptions) error {
+	structured := output.IsStructured(cmd)
+
+	runs := make([]csvRun, len(rows))
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, concurrency)
+	)
+	for i, row := range rows {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, row map[string]interface{}) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			run := csvRun{Row: i + 1}
+			execution, err := client.ExecuteWorkflow(id, row, false)
+			if err != nil {
+				run.Status = "failed to start"
+				run.Error = err.Error()
+			} else {
+				run.ExecutionID = execution.ID
+				run.Status = execution.Status
+			}
+			runs[i] = run
+
+			if !structured {
+				mu.Lock()
+				if err != nil {
+					logging.Warn(fmt.Sprintf("row %d failed to start: %v", run.Row, err), "row", run.Row, "error", err)
+				} else {
+					fmt.Printf("Row %d: started execution %s\n", run.Row, run.ExecutionID)
+				}
+				mu.Unlock()
+			}
+		}(i, row)
+	}
+	wg.Wait()
+
+	var execIDs []string
+	for _, run := range runs {
+		if run.ExecutionID != "" {
+			execIDs = append(execIDs, run.ExecutionID)
+		}
+	}
+	failed := len(rows) - len(execIDs)
+
+	var waitErr error
+	if opts.wait && len(execIDs) > 0 {
+		if !structured {
+			fmt.Println()
+		}
+		opts.concurrency = concurrency
+		executions, err := opts.track(cmd, client, execIDs)
+		waitErr = err
+		status := make(map[string]string, len(executions))
+		for _, execution := range executions {
+			status[execution.ID] = execution.Status
+		}
+		for i := range runs {
+			if s, ok := status[runs[i].ExecutionID]; ok && s != "" {
+				runs[i].Status = s
+			}
+		}
+	}
+
+	if structured {
+		if err := output.Print(cmd, runs); err != nil {
+			return err
+		}
+	} else {
+		counts := make(map[string]int)
+		for _, run := range runs {
+			counts[run.Status]++
+		}
+		statuses := make([]string, 0, len(counts))
+		for status := range counts {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		parts := make([]string, len(statuses))
+		for i, status := range statuses {
+			label := status
+			if label == "" {
+				label = "started"
+			}
+			parts[i] = fmt.Sprintf("%d %s", counts[status], label)
+		}
+		fmt.Printf("\n%d row(s): %s\n", len(rows), strings.Join(parts, ", "))
+	}
+
+	if waitErr != nil {
+		return waitErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rows failed to start", failed, len(rows))
+	}
+	return nil
+}
+
+// runMultiple starts each workflow without waiting and, with wait set,
+// tracks all resulting executions in a live status table.
+func runMultiple(cmd *cobra.Command, client *api.Client, ids []string, inputData map[string]interface{}, opts waitOptions) error {
+	structured := output.IsStructured(cmd)
+
+	var (
+		executions []*api.Execution
+		failed     int
+	)
+	for _, id := range ids {
+		execution, err := client.ExecuteWorkflow(id, inputData, false)
+		if err != nil {
+			logging.Warn(fmt.Sprintf("failed to execute workflow %s: %v", id, err), "workflow", id, "error", err)
+			failed++
+			continue
+		}
+		executions = append(executions, execution)
+		if !structured {
+			fmt.Printf("Started workflow %s. Execution ID: %s\n", id, execution.ID)
+		}
+	}
+
+	if opts.wait && len(executions) > 0 {
+		execIDs := make([]string, len(executions))
+		for i, execution := range executions {
+			execIDs[i] = execution.ID
+		}
+		if !structured {
+			fmt.Println()
+		}
+		var err error
+		executions, err = opts.track(cmd, client, execIDs)
+		if err != nil {
+			if structured {
+				_ = output.Print(cmd, executions)
+			}
+			return err
+		}
+	}
+
+	if structured {
+		if err := output.Print(cmd, executions); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d workflows failed to start", failed, len(ids))
+	}
+	return nil
+}
+
+func newPatchCmd() *cobra.Command {
+	var (
+		nodeName string
+		sets     []string
+		allowNew bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "patch <workflow-id>",
+		Short: "Change parameters of a single node",
+		Long: `Fetch a workflow, set parameters of one node, and update it.
+
+Each --set takes a dot-separated path below the node's parameters and a
+value. Values that parse as JSON (numbers, booleans, objects, quoted
+strings) are used as such; anything else is taken as a plain string.
+Numeric path segments index into arrays.
+
+The node and every path segment must exist, unless --allow-new is given.
+
+Examples:
+  n8nctl wf patch abc123 --node "HTTP Request" --set url=https://api.example.com
+  n8nctl wf patch abc123 --node Wait --set amount=30 --set unit='"minutes"'
+  n8nctl wf patch abc123 --node Set --set assignments.assignments.0.value=42`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if nodeName == "" {
+				return fmt.Errorf("--node is required")
+			}
+			if len(sets) == 0 {
+				return fmt.Errorf("at least one --set path=value is required")
+			}
+
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			wf, err := workflow.Get(client, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get workflow: %w", err)
+			}
+
+			node, err := workflow.FindNode(wf, nodeName)
+			if err != nil {
+				return err
+			}
+
+			for _, set := range sets {
+				path, raw, ok := strings.Cut(set, "=")
+				if !ok || path == "" {
+					return fmt.Errorf("invalid --set %q, expected path=value", set)
+				}
+				var value interface{}
+				if err := json.Unmarshal([]byte(raw), &value); err != nil {
+					value = raw
+				}
+				if err := workflow.SetParameter(node, path, value, allowNew); err != nil {
+					return err
+				}
+			}
+
+			updated, err := client.UpdateWorkflow(wf.ID, wf)
+			if err != nil {
+				return fmt.Errorf("failed to update workflow: %w", err)
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, updated)
+			}
+
+			fmt.Printf("Patched %d parameter(s) of node %q in workflow %s (ID: %s)\n", len(sets), nodeName, updated.Name, updated.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&nodeName, "node", "", "Name of the node to patch")
+	cmd.Flags().StringArrayVar(&sets, "set", nil, "Parameter to set as path=value (can be repeated)")
+	cmd.Flags().BoolVar(&allowNew, "allow-new", false, "Create parameters that don't exist yet")
+
+	return cmd
+}
+
+func newWebhooksCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "webhooks <workflow-id>",
+		Short: "Show the webhook URLs of a workflow",
+		Long: `List the Webhook and Form Trigger nodes of a workflow with their HTTP
+methods and URLs.
+
+The production URL only responds while the workflow is active; the test
+URL while the editor is listening for a test event. URLs are built from
+the instance URL, so instances that serve webhooks under a different
+WEBHOOK_URL use another host.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			wf, err := workflow.Get(client, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get workflow: %w", err)
+			}
+
+			baseURL, err := client.WebhookBaseURL()
+			if err != nil {
+				return err
+			}
+			hooks := workflow.Webhooks(wf, baseURL)
+			if output.IsStructured(cmd) {
+				if hooks == nil {
+					hooks = []workflow.Webhook{}
+				}
+				return output.Print(cmd, hooks)
+			}
+
+			if len(hooks) == 0 {
+				fmt.Printf("Workflow %s has no Webhook or Form Trigger nodes.\n", wf.Name)
+				return nil
+			}
+
+			for i, hook := range hooks {
+				if i > 0 {
+					fmt.Println()
+				}
+				name := hook.Node
+				if hook.Disabled {
+					name += " (disabled)"
+				}
+				fmt.Printf("%s [%s]\n", name, strings.Join(hook.Methods, ", "))
+				fmt.Printf("  Production: %s\n", hook.ProductionURL)
+				fmt.Printf("  Test:       %s\n", hook.TestURL)
+			}
+
+			if !wf.Active {
+				fmt.Println("\nNote: the workflow is inactive, so its production URLs don't respond.")
+			}
+			return nil
+		},
+	}
+}
+
+func newActivateCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "activate [workflow-id...]",
+		Short: "Activate workflows",
+		Long: `Activate one or more workflows. Without arguments, the IDs are read
+from stdin, one per line; blank lines and lines starting with # are
+skipped.
+
+Each workflow is checked first, since n8n's errors for workflows that
+can't be activated are terse: it needs an enabled trigger, poller, or
+webhook node (manual and Execute Workflow triggers don't count), and no
+two webhooks may listen on the same method and path. Several triggers
+only produce a warning. --force skips the check and leaves the decision
+to the server.`,
+		Example: `  n8nctl workflow activate abc123
+  grep -v legacy ids.txt | n8nctl workflow activate`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorkflowBatch(cmd, args, "activated", func(client *api.Client, id string) error {
+				if !force {
+					if err := checkActivation(client, id); err != nil {
+						return err
+					}
+				}
+				if err := client.ActivateWorkflow(id); err != nil {
+					return fmt.Errorf("failed to activate workflow: %w", err)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Skip the trigger check and let the server decide")
+
+	return cmd
+}
+
+// checkActivation fetches a workflow and fails with a specific diagnostic
+// if n8n would refuse to activate it. Warnings go to stderr.
+func checkActivation(client *api.Client, id string) error {
+	wf, err := client.GetWorkflow(id)
+	if err != nil {
+		return fmt.Errorf("failed to get workflow: %w", err)
+	}
+	warnings, err := workflow.CheckActivation(wf)
+	for _, w := range warnings {
+		logging.Warn(fmt.Sprintf("%s: %s", id, w), "workflow", id)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot activate workflow %s: %w (use --force to try anyway)", id, err)
+	}
+	return nil
+}
+
+func newDeactivateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "deactivate [workflow-id...]",
+		Short: "Deactivate workflows",
+		Long: `Deactivate one or more workflows. Without arguments, the IDs are read
+from stdin, one per line; blank lines and lines starting with # are
+skipped.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorkflowBatch(cmd, args, "deactivated", func(client *api.Client, id string) error {
+				if err := client.DeactivateWorkflow(id); err != nil {
+					return fmt.Errorf("failed to deactivate workflow: %w", err)
+				}
+				return nil
+			})
+		},
+	}
+}
+
+// batchIDs returns the IDs given as arguments, or those piped into stdin
+// if there are none
+func batchIDs(args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	return prompt.StdinIDs()
+}
+
+// runWorkflowBatch applies fn to the workflows given as arguments or on
+// stdin. A single workflow fails with fn's error; batches report every
+// workflow with a counter and carry on after failures.
+func runWorkflowBatch(cmd *cobra.Command, args []string, past string, fn func(client *api.Client, id string) error) error {
+	refs, err := batchIDs(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := cli.NewClient(cmd)
+	if err != nil {
+		return err
+	}
+
+	if len(refs) == 1 {
+		_, err = workflow.WithID(client, refs[0], func(id string) error {
+			return fn(client, id)
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Workflow %s.\n", past)
+		return nil
+	}
+
+	failed := 0
+	for i, ref := range refs {
+		counter := fmt.Sprintf("[%d/%d]", i+1, len(refs))
+		id, err := workflow.WithID(client, ref, func(id string) error {
+			return fn(client, id)
+		})
+		if err != nil {
+			logging.Error(fmt.Sprintf("%s %s: %v", counter, ref, err), "workflow", ref, "error", err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s Workflow %s %s.\n", counter, id, past)
+	}
+
+	fmt.Printf("\n%d workflow(s) %s, %d failed.\n", len(refs)-failed, past, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d workflow(s) could not be %s", failed, past)
+	}
+	return nil
+}
+
+// archiveResult is the state of a workflow after archive or unarchive
+type archiveResult struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+	Error    string `json:"error,omitempty"`
+}
+
+// newArchiveCmd builds the archive command, or the unarchive command if
+// archive is false
+func newArchiveCmd(archive bool) *cobra.Command {
+	var tags []string
+
+	verb, past := "archive", "archived"
+	short := "Archive workflows"
+	long := `Archive workflows by ID or name, or all workflows with one of the given
+--tag. Archived workflows are kept but hidden from workflow list unless
+--include-archived is given, which makes archiving a reversible
+alternative to deleting. Asks for confirmation unless --yes is given.`
+	if !archive {
+		verb, past = "unarchive", "unarchived"
+		short = "Restore archived workflows"
+		long = `Restore archived workflows by ID or name, or all archived workflows with
+one of the given --tag. Asks for confirmation unless --yes is given.`
+	}
+	long += `
+
+Without arguments or --tag, the IDs are read from stdin, one per line;
+blank lines and lines starting with # are skipped. Stdin then can't
+answer the confirmation, so --yes is required.
+
+Failures are reported and skipped, so the remaining workflows are still
+` + past + `. Instances older than the archiving feature don't offer the
+endpoint; the command then fails without changing anything.`
+
+	cmd := &cobra.Command{
+		Use:   verb + " [workflow-id...]",
+		Short: short,
+		Long:  long,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && len(tags) == 0 {
+				ids, err := prompt.StdinIDs()
+				if err != nil {
+					return fmt.Errorf("specify at least one workflow or --tag, or pipe in IDs: %w", err)
+				}
+				args = ids
 			}
 
-			// Webhook mode: trigger via webhook URL instead of execute API
-			if webhookPath != "" {
-				respBody, err := client.TriggerWebhook(webhookPath, method)
+			client, err := cli.NewClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			var targets []api.Workflow
+			selected := make(map[string]bool)
+			for _, arg := range args {
+				wf, err := workflow.Get(client, arg)
 				if err != nil {
-					return fmt.Errorf("failed to trigger webhook: %w", err)
+					return fmt.Errorf("failed to get workflow: %w", err)
+				}
+				if !selected[wf.ID] {
+					selected[wf.ID] = true
+					targets = append(targets, *wf)
+				}
+			}
+			if len(tags) > 0 {
+				list, err := client.ListWorkflows(api.ListWorkflowsOptions{Tags: tags, ExcludePinnedData: true})
+				if err != nil {
+					return fmt.Errorf("failed to list workflows: %w", err)
+				}
+				for _, wf := range list.Data {
+					// Tag selections only pick workflows the command changes
+					if wf.IsArchived != archive && !selected[wf.ID] {
+						selected[wf.ID] = true
+						targets = append(targets, wf)
+					}
 				}
+			}
 
-				jsonFlag, _ := cmd.Flags().GetBool("json")
-				if jsonFlag {
-					// Try to pretty-print if valid JSON, otherwise print raw
-					var parsed interface{}
-					if json.Unmarshal(respBody, &parsed) == nil {
-						return printJSON(parsed)
+			if len(targets) == 0 {
+				fmt.Println("No matching workflows found.")
+				return nil
+			}
+
+			names := make([]string, len(targets))
+			for i, wf := range targets {
+				names[i] = fmt.Sprintf("%s (%s)", wf.Name, wf.ID)
+			}
+			question := fmt.Sprintf("%s %d workflow(s): %s?", strings.ToUpper(verb[:1])+verb[1:], len(targets), strings.Join(names, ", "))
+			if err := prompt.Confirm(cmd, question); err != nil {
+				return err
+			}
+
+			results := make([]archiveResult, 0, len(targets))
+			failed := 0
+			for i, wf := range targets {
+				counter := ""
+				if len(targets) > 1 {
+					counter = fmt.Sprintf("[%d/%d] ", i+1, len(targets))
+				}
+				result := archiveResult{ID: wf.ID, Name: wf.Name, Archived: wf.IsArchived}
+				var updated *api.Workflow
+				if archive {
+					updated, err = client.ArchiveWorkflow(wf.ID)
+				} else {
+					updated, err = client.UnarchiveWorkflow(wf.ID)
+				}
+				if err != nil {
+					if archiveUnsupported(err) {
+						return fmt.Errorf("this n8n instance does not support archiving workflows (%v)", err)
+					}
+					result.Error = err.Error()
+					failed++
+					if !output.IsStructured(cmd) {
+						logging.Error(fmt.Sprintf("%sfailed to %s workflow %s: %v", counter, verb, wf.ID, err), "workflow", wf.ID, "error", err)
+					}
+				} else {
+					// Responses may leave out isArchived when it is false, so
+					// a successful archive counts even without the field
+					result.Archived = archive || updated.IsArchived
+					if !output.IsStructured(cmd) {
+						fmt.Printf("%sWorkflow %s (%s) %s.\n", counter, wf.Name, wf.ID, past)
 					}
 				}
+				results = append(results, result)
+			}
 
-				fmt.Printf("Webhook triggered successfully.\n")
-				if len(respBody) > 0 {
-					fmt.Printf("Response: %s\n", string(respBody))
+			if output.IsStructured(cmd) {
+				if err := output.Print(cmd, results); err != nil {
+					return err
 				}
-				return nil
+			} else if len(targets) > 1 || failed > 0 {
+				fmt.Printf("\n%d workflow(s) %s, %d failed.\n", len(targets)-failed, past, failed)
 			}
+			if failed > 0 {
+				return fmt.Errorf("%d workflow(s) could not be %s", failed, past)
+			}
+			return nil
+		},
+	}
 
-			var inputData map[string]interface{}
-			if inputJSON != "" {
-				if err := json.Unmarshal([]byte(inputJSON), &inputData); err != nil {
-					return fmt.Errorf("invalid input JSON: %w", err)
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Select all workflows with this tag (repeatable)")
+
+	return cmd
+}
+
+// archiveUnsupported reports whether err is the answer of an instance
+// without the archive endpoints. The workflows are fetched beforehand, so
+// a 404 here means the endpoint, not the workflow, is missing.
+func archiveUnsupported(err error) bool {
+	var apiErr *api.APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusNotFound || apiErr.StatusCode == http.StatusMethodNotAllowed)
+}
+
+func newTransferCmd() *cobra.Command {
+	var skipCredentials bool
+
+	cmd := &cobra.Command{
+		Use:   "transfer [workflow-id...] <project-id>",
+		Short: "Transfer workflows and their credentials to another project",
+		Long: `Transfer workflows and the credentials they use to another project.
+With only the project given, the workflow IDs are read from stdin, one
+per line; blank lines and lines starting with # are skipped.`,
+		Example: `  n8nctl workflow transfer abc123 <project-id>
+  jq -r '.workflows | keys[]' manifest.json | n8nctl workflow transfer <project-id>`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectID := args[len(args)-1]
+			if len(args) == 2 {
+				client, err := cli.NewClient(cmd)
+				if err != nil {
+					return err
+				}
+				workflowID, err := workflow.WithID(client, args[0], func(id string) error {
+					return transferWorkflow(client, id, projectID, skipCredentials)
+				})
+				if err != nil {
+					return err
 				}
+				fmt.Printf("Workflow %s transferred to project %s.\n", workflowID, projectID)
+				return nil
+			}
+
+			past := "transferred to project " + projectID
+			return runWorkflowBatch(cmd, args[:len(args)-1], past, func(client *api.Client, id string) error {
+				return transferWorkflow(client, id, projectID, skipCredentials)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&skipCredentials, "skip-credentials", false, "Don't transfer credentials used by the workflow")
+
+	return cmd
+}
+
+// transferWorkflow moves a workflow to another project. Unless
+// skipCredentials is set, the credentials it uses are transferred first
+// so the workflow doesn't lose access.
+func transferWorkflow(client *api.Client, workflowID, projectID string, skipCredentials bool) error {
+	if !skipCredentials {
+		wf, err := client.GetWorkflow(workflowID)
+		if err != nil {
+			return fmt.Errorf("failed to get workflow: %w", err)
+		}
+
+		credIDs := extractCredentialIDs(wf)
+		for _, credID := range credIDs {
+			if err := client.TransferCredential(credID, projectID); err != nil {
+				logging.Warn(fmt.Sprintf("failed to transfer credential %s: %v", credID, err), "credential", credID, "error", err)
+			} else {
+				fmt.Printf("Transferred credential %s\n", credID)
+			}
+		}
+	}
+
+	if err := client.TransferWorkflow(workflowID, projectID); err != nil {
+		return fmt.Errorf("failed to transfer workflow: %w", err)
+	}
+	return nil
+}
+
+func newMoveCmd() *cobra.Command {
+	var (
+		project         string
+		dir             string
+		skipCredentials bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "move <workflow-id> --project <project>",
+		Short: "Transfer a workflow and update the local manifest",
+		Long: `Transfer a workflow and its credentials to another project, like
+'workflow transfer', and record the new project in the local manifest.
+
+The project can be given by ID or name. If the manifest.json in --dir
+(default: the current directory) lists the workflow, its project is
+updated there too.`,
+		Example: `  n8nctl workflow move abc123 --project Marketing
+  n8nctl workflow move abc123 --project Xy7Kq2 --dir workflows/`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if project == "" {
+				return fmt.Errorf("--project is required")
 			}
 
-			execution, err := client.ExecuteWorkflow(args[0], inputData, wait)
+			client, err := cli.NewClient(cmd)
 			if err != nil {
-				if strings.Contains(err.Error(), "405") {
-					fmt.Fprintf(os.Stderr, "Hint: The /execute API endpoint returned 405. This endpoint may not be available on your n8n instance.\n")
-					fmt.Fprintf(os.Stderr, "Use --webhook to trigger the workflow via its webhook URL instead:\n")
-					fmt.Fprintf(os.Stderr, "  n8nctl wf run %s --webhook <webhook-path>\n", args[0])
-				}
-				return fmt.Errorf("failed to execute workflow: %w", err)
+				return err
 			}
 
-			jsonFlag, _ := cmd.Flags().GetBool("json")
-			if jsonFlag {
-				return printJSON(execution)
+			target, err := workflow.ResolveProject(client, project)
+			if err != nil {
+				return err
 			}
 
-			fmt.Printf("Execution ID: %s\n", execution.ID)
-			fmt.Printf("Status: %s\n", execution.Status)
-			if execution.Finished {
-				fmt.Printf("Finished: yes\n")
+			workflowID, err := workflow.WithID(client, args[0], func(id string) error {
+				return transferWorkflow(client, id, target.ID, skipCredentials)
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Workflow %s moved to project %s (%s).\n", workflowID, target.Name, target.ID)
+
+			manifest, err := loadManifest(dir)
+			if errors.Is(err, errNoManifest) {
+				// No local copy to update
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("workflow moved, but the local manifest was not updated: %w", err)
+			}
+			meta, ok := manifest.Workflows[workflowID]
+			if !ok {
+				fmt.Printf("Local manifest does not list workflow %s; not changed.\n", workflowID)
+				return nil
+			}
+
+			from := meta.ProjectName
+			if from == "" {
+				from = meta.ProjectID
+			}
+			if from == "" {
+				from = "(unknown)"
+			}
+			meta.ProjectID = target.ID
+			meta.ProjectName = target.Name
+			manifest.Workflows[workflowID] = meta
+			if err := saveManifest(dir, manifest); err != nil {
+				return err
 			}
 
+			fmt.Printf("Updated %s: project %s -> %s\n", filepath.Join(dir, "manifest.json"), from, target.Name)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVarP(&inputJSON, "input", "i", "", "Input data as JSON")
-	cmd.Flags().BoolVarP(&wait, "wait", "w", false, "Wait for execution to complete")
-	cmd.Flags().StringVar(&webhookPath, "webhook", "", "Trigger via webhook path instead of execute API")
-	cmd.Flags().StringVar(&method, "method", "GET", "HTTP method for webhook trigger")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Destination project ID or name (required)")
+	cmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory containing the local manifest.json")
+	cmd.Flags().BoolVar(&skipCredentials, "skip-credentials", false, "Don't transfer credentials used by the workflow")
 
 	return cmd
 }
 
-func newActivateCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:   "activate <workflow-id>",
-		Short: "Activate a workflow",
-		Args:  cobra.ExactArgs(1),
+// historyStats summarizes the outcomes of a set of executions
+type historyStats struct {
+	Total           int     `json:"total"`
+	Success         int     `json:"success"`
+	Error           int     `json:"error"`
+	Other           int     `json:"other"`
+	SuccessRate     float64 `json:"successRate"`
+	AverageDuration string  `json:"averageDuration,omitempty"`
+}
+
+func newHistoryCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history <workflow-id>",
+		Short: "Show recent executions of a workflow",
+		Long: `List the most recent executions of a workflow with their status,
+start time, and duration, followed by a success-rate summary.`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
-			if err := client.ActivateWorkflow(args[0]); err != nil {
-				return fmt.Errorf("failed to activate workflow: %w", err)
+			wf, err := workflow.Get(client, args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get workflow: %w", err)
+			}
+
+			result, err := client.ListExecutions(api.ListExecutionsOptions{
+				WorkflowID: wf.ID,
+				Limit:      limit,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list executions: %w", err)
+			}
+
+			executions := result.Data
+			stats := computeHistoryStats(executions)
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, map[string]interface{}{
+					"workflow":   map[string]string{"id": wf.ID, "name": wf.Name},
+					"executions": executions,
+					"stats":      stats,
+				})
+			}
+
+			fmt.Printf("Workflow: %s (%s)\n\n", wf.Name, wf.ID)
+
+			if len(executions) == 0 {
+				fmt.Println("No executions found.")
+				return nil
+			}
+
+			table := output.NewTable(cmd, "ID", "STATUS", "STARTED", "DURATION")
+			for _, exec := range executions {
+				started := ""
+				if exec.StartedAt != nil {
+					started = exec.StartedAt.Local().Format("2006-01-02 15:04:05")
+				}
+				duration := ""
+				if d, ok := exec.Duration(); ok {
+					duration = d.Round(time.Millisecond).String()
+				}
+				table.AddRow(exec.ID, exec.Status, started, duration)
+			}
+			table.Print()
+
+			summary := fmt.Sprintf("%d of %d succeeded (%.0f%%), %d failed",
+				stats.Success, stats.Total, stats.SuccessRate*100, stats.Error)
+			if stats.Other > 0 {
+				summary += fmt.Sprintf(", %d other", stats.Other)
 			}
+			if stats.AverageDuration != "" {
+				summary += fmt.Sprintf(". Average duration: %s", stats.AverageDuration)
+			}
+			table.Footer("%s.", summary)
 
-			fmt.Println("Workflow activated.")
 			return nil
 		},
 	}
+
+	cmd.Flags().IntVar(&limit, "limit", 20, "Number of recent executions to show (0 = unlimited)")
+
+	return cmd
 }
 
-func newDeactivateCmd() *cobra.Command {
+// computeHistoryStats counts executions by outcome and averages the
+// duration of those that have both start and stop times.
+func computeHistoryStats(executions []api.Execution) historyStats {
+	stats := historyStats{Total: len(executions)}
+
+	var (
+		total time.Duration
+		timed int
+	)
+	for _, exec := range executions {
+		switch exec.Status {
+		case "success":
+			stats.Success++
+		case "error", "crashed":
+			stats.Error++
+		default:
+			stats.Other++
+		}
+		if d, ok := exec.Duration(); ok {
+			total += d
+			timed++
+		}
+	}
+
+	if stats.Total > 0 {
+		stats.SuccessRate = float64(stats.Success) / float64(stats.Total)
+	}
+	if timed > 0 {
+		stats.AverageDuration = (total / time.Duration(timed)).Round(time.Millisecond).String()
+	}
+
+	return stats
+}
+
+func newTagsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "Manage the tags of a workflow",
+	}
+
+	cmd.AddCommand(newTagsListCmd())
+	cmd.AddCommand(newTagsSetCmd())
+
+	return cmd
+}
+
+func newTagsListCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:   "deactivate <workflow-id>",
-		Short: "Deactivate a workflow",
+		Use:   "list <workflow-id>",
+		Short: "List the tags of a workflow",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
-			if err := client.DeactivateWorkflow(args[0]); err != nil {
-				return fmt.Errorf("failed to deactivate workflow: %w", err)
+			var tags []api.Tag
+			_, err = workflow.WithID(client, args[0], func(id string) error {
+				tags, err = client.GetWorkflowTags(id)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to get workflow tags: %w", err)
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, tags)
 			}
 
-			fmt.Println("Workflow deactivated.")
+			if len(tags) == 0 {
+				fmt.Println("No tags.")
+				return nil
+			}
+
+			table := output.NewTable(cmd, "ID", "NAME")
+			for _, t := range tags {
+				table.AddRow(t.ID, t.Name)
+			}
+			table.Print()
 			return nil
 		},
 	}
 }
 
-func newTransferCmd() *cobra.Command {
-	var skipCredentials bool
+func newTagsSetCmd() *cobra.Command {
+	var createMissing bool
 
 	cmd := &cobra.Command{
-		Use:   "transfer <workflow-id> <project-id>",
-		Short: "Transfer a workflow and its credentials to another project",
-		Args:  cobra.ExactArgs(2),
+		Use:   "set <workflow-id> [tag-name...]",
+		Short: "Replace the tags of a workflow",
+		Long: `Replace the tags of a workflow with the given tag names. Without tag
+names, all tags are removed.
+
+Unknown tag names are an error unless --create-missing is given, in which
+case the tags are created first.`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			client, err := cli.NewClient(cmd)
 			if err != nil {
 				return err
 			}
 
 			workflowID := args[0]
-			projectID := args[1]
+			names := args[1:]
+
+			existing, err := client.ListTags(0, "")
+			if err != nil {
+				return fmt.Errorf("failed to list tags: %w", err)
+			}
+			byName := make(map[string]api.Tag, len(existing))
+			for _, t := range existing {
+				byName[t.Name] = t
+			}
 
-			// Transfer credentials first so the workflow doesn't lose access
-			if !skipCredentials {
-				wf, err := client.GetWorkflow(workflowID)
+			var missing []string
+			for _, name := range names {
+				if _, ok := byName[name]; !ok {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 && !createMissing {
+				return fmt.Errorf("unknown tag(s): %s. Use --create-missing to create them", strings.Join(missing, ", "))
+			}
+			if len(missing) > 0 {
+				// Don't create tags for a workflow that doesn't exist
+				if workflowID, err = resolveWorkflowID(client, workflowID); err != nil {
+					return err
+				}
+			}
+			for _, name := range missing {
+				tag, created, err := client.EnsureTag(name)
 				if err != nil {
-					return fmt.Errorf("failed to get workflow: %w", err)
+					return fmt.Errorf("failed to create tag %s: %w", name, err)
 				}
-
-				credIDs := extractCredentialIDs(wf)
-				for _, credID := range credIDs {
-					if err := client.TransferCredential(credID, projectID); err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: failed to transfer credential %s: %v\n", credID, err)
-					} else {
-						fmt.Printf("Transferred credential %s\n", credID)
-					}
+				if created {
+					fmt.Printf("Created tag: %s (ID: %s)\n", tag.Name, tag.ID)
 				}
+				byName[name] = *tag
 			}
 
-			if err := client.TransferWorkflow(workflowID, projectID); err != nil {
-				return fmt.Errorf("failed to transfer workflow: %w", err)
+			tagIDs := make([]string, 0, len(names))
+			for _, name := range names {
+				tagIDs = append(tagIDs, byName[name].ID)
 			}
 
-			fmt.Printf("Workflow %s transferred to project %s.\n", workflowID, projectID)
+			var tags []api.Tag
+			workflowID, err = workflow.WithID(client, workflowID, func(id string) error {
+				tags, err = client.UpdateWorkflowTags(id, tagIDs)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update workflow tags: %w", err)
+			}
+
+			if output.IsStructured(cmd) {
+				return output.Print(cmd, tags)
+			}
+
+			tagNames := make([]string, len(tags))
+			for i, t := range tags {
+				tagNames[i] = t.Name
+			}
+			fmt.Printf("Workflow %s tags: %s\n", workflowID, strings.Join(tagNames, ", "))
 			return nil
 		},
 	}
 
-	cmd.Flags().BoolVar(&skipCredentials, "skip-credentials", false, "Don't transfer credentials used by the workflow")
+	cmd.Flags().BoolVar(&createMissing, "create-missing", false, "Create tags that don't exist yet")
 
 	return cmd
 }
 
 // extractCredentialIDs returns unique credential IDs used by a workflow's nodes.
 func extractCredentialIDs(wf *api.Workflow) []string {
-	seen := make(map[string]bool)
 	var ids []string
-
-	for _, node := range wf.Nodes {
-		creds, ok := node["credentials"].(map[string]interface{})
-		if !ok {
-			continue
-		}
-		for _, cred := range creds {
-			credMap, ok := cred.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			id, ok := credMap["id"].(string)
-			if !ok || id == "" || seen[id] {
-				continue
-			}
-			seen[id] = true
-			ids = append(ids, id)
+	for _, ref := range workflow.ExtractCredentials(wf.Nodes) {
+		if ref.ID != "" {
+			ids = append(ids, ref.ID)
 		}
 	}
-
 	return ids
 }
 
-func boolPtr(b bool) *bool {
-	return &b
+// filterArchived keeps the workflows whose archived state matches archived
+func filterArchived(workflows []api.Workflow, archived bool) []api.Workflow {
+	kept := workflows[:0]
+	for _, wf := range workflows {
+		if wf.IsArchived == archived {
+			kept = append(kept, wf)
+		}
+	}
+	return kept
 }
 
-func printJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(v)
+func boolPtr(b bool) *bool {
+	return &b
 }
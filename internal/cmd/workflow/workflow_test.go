@@ -0,0 +1,220 @@
+package workflow
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+	"github.com/enthus-appdev/n8n-cli/internal/workflow"
+)
+
+func TestPullStateResumesInterruptedWrite(t *testing.T) {
+	dir := t.TempDir()
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Handle(http.MethodGet, "/workflows", apitest.Response{Body: map[string]interface{}{
+		"data": []map[string]string{{"id": "a", "name": "A"}, {"id": "b", "name": "B"}},
+	}})
+	srv.Handle(http.MethodGet, "/workflows/a", apitest.Response{Body: map[string]string{"id": "a", "name": "A"}})
+	srv.Handle(http.MethodGet, "/workflows/b", apitest.Response{Body: map[string]string{"id": "b", "name": "B"}})
+	// The first run fails after writing A's file, while saving executions
+	srv.Handle(http.MethodGet, "/executions", apitest.Response{Status: http.StatusInternalServerError, Body: "down"})
+	client := api.NewClient(srv.URL, "key")
+
+	opts := pullOptions{dir: dir, withExecutions: 1, stateFile: filepath.Join(dir, "pull-state.json")}
+	if err := pullRecursive(client, "", opts); err == nil {
+		t.Fatal("expected the first run to fail")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "A.json")); err != nil {
+		t.Fatalf("A.json was not written before the failure: %v", err)
+	}
+	state, err := loadPullState(opts.stateFile, dir)
+	if err != nil || state == nil {
+		t.Fatalf("loadPullState = %v, %v", state, err)
+	}
+	if state.Pending != "a" || len(state.Manifest.Workflows) != 0 || !state.resumable["A.json"] {
+		t.Fatalf("state = pending %q, %d workflow(s), resumable %v; want A pending and resumable", state.Pending, len(state.Manifest.Workflows), state.resumable)
+	}
+
+	srv.Handle(http.MethodGet, "/executions", apitest.Response{Body: map[string]interface{}{"data": []interface{}{}}})
+	if err := pullRecursive(client, "", opts); err != nil {
+		t.Fatalf("rerun: %v", err)
+	}
+	for _, name := range []string{"A.json", "B.json", "manifest.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s missing after the rerun: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(opts.stateFile); !os.IsNotExist(err) {
+		t.Errorf("state file left behind: %v", err)
+	}
+}
+
+func TestLoadPullStateDropsMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "A.json"), []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "state.json")
+	manifest := newTestManifest(map[string]string{"a": "A.json", "b": "B.json", "c": "C.json"})
+	if err := savePullState(path, manifest, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := loadPullState(path, dir)
+	if err != nil {
+		t.Fatalf("loadPullState: %v", err)
+	}
+
+	tests := []struct {
+		id        string
+		kept      bool
+		resumable bool
+	}{
+		{id: "a", kept: true, resumable: true},
+		// B.json was deleted since, so B is fetched again
+		{id: "b", kept: false, resumable: true},
+		// C was being written
+		{id: "c", kept: false, resumable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, kept := state.Manifest.Workflows[tt.id]
+			if kept != tt.kept {
+				t.Errorf("kept = %v, want %v", kept, tt.kept)
+			}
+			filename := manifest.Workflows[tt.id].Filename
+			if state.resumable[filename] != tt.resumable {
+				t.Errorf("resumable = %v, want %v", state.resumable[filename], tt.resumable)
+			}
+		})
+	}
+}
+
+func TestLoadPullStateMissingFile(t *testing.T) {
+	state, err := loadPullState(filepath.Join(t.TempDir(), "none.json"), "")
+	if state != nil || err != nil {
+		t.Errorf("loadPullState = %v, %v; want nil, nil", state, err)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(dir string) error
+		wantErr string
+		missing bool
+	}{
+		{name: "missing", setup: func(string) error { return nil }, wantErr: errNoManifest.Error(), missing: true},
+		{name: "unreadable", setup: func(dir string) error { return os.Mkdir(filepath.Join(dir, "manifest.json"), 0o755) }, wantErr: "failed to read"},
+		{name: "corrupt", setup: func(dir string) error {
+			return os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(`{"workflows":`), 0o644)
+		}, wantErr: "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := tt.setup(dir); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadManifest(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if got := errors.Is(err, errNoManifest); got != tt.missing {
+				t.Errorf("errors.Is(err, errNoManifest) = %v, want %v", got, tt.missing)
+			}
+		})
+	}
+}
+
+// newTestManifest returns a manifest listing workflows by ID with their
+// filenames
+func newTestManifest(files map[string]string) *workflow.Manifest {
+	manifest := &workflow.Manifest{Workflows: map[string]workflow.WorkflowMeta{}, Dependencies: map[string][]string{}}
+	for id, filename := range files {
+		manifest.Workflows[id] = workflow.WorkflowMeta{ID: id, Name: id, Filename: filename}
+	}
+	return manifest
+}
+
+func TestPulledFilesEndWithNewline(t *testing.T) {
+	dir := t.TempDir()
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Handle(http.MethodGet, "/workflows/a", apitest.Response{Body: map[string]interface{}{
+		"id": "a", "name": "A",
+		"nodes": []map[string]interface{}{{
+			"name":       "Call B",
+			"type":       "n8n-nodes-base.executeWorkflow",
+			"parameters": map[string]string{"workflowId": "b"},
+		}},
+	}})
+	srv.Handle(http.MethodGet, "/workflows/b", apitest.Response{Body: map[string]string{"id": "b", "name": "B"}})
+
+	if err := pullRecursive(api.NewClient(srv.URL, "key"), "a", pullOptions{dir: dir}); err != nil {
+		t.Fatalf("pullRecursive: %v", err)
+	}
+	for _, name := range []string{"A.json", "B.json", "manifest.json"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !strings.HasSuffix(string(data), "}\n") || strings.HasSuffix(string(data), "\n\n") {
+			t.Errorf("%s doesn't end with exactly one newline: %q", name, data[max(0, len(data)-10):])
+		}
+	}
+}
+
+func TestReadCSVInputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []map[string]interface{}
+		wantErr string
+	}{
+		{
+			name: "rows",
+			csv:  "email, name\na@example.com,A\nb@example.com,B\n",
+			want: []map[string]interface{}{
+				{"email": "a@example.com", "name": "A"},
+				{"email": "b@example.com", "name": "B"},
+			},
+		},
+		{name: "header only", csv: "email,name\n", wantErr: "has no data rows"},
+		{name: "empty header", csv: "email,\na,b\n", wantErr: "CSV column 2 has an empty header"},
+		{name: "duplicate header", csv: "email,name,email\na,b,c\n", wantErr: `CSV columns 1 and 3 have the same header "email"`},
+		{name: "duplicate after trimming", csv: "name, name\na,b\n", wantErr: `same header "name"`},
+		{name: "ragged rows", csv: "a,b\n1\n", wantErr: "failed to parse CSV file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "inputs.csv")
+			if err := os.WriteFile(path, []byte(tt.csv), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := readCSVInputs(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readCSVInputs: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("rows = %v, want %v", rows, tt.want)
+			}
+		})
+	}
+}
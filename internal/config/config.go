@@ -1,41 +1,385 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+)
+
+const (
+	// lockTimeout is how long to wait for another process to release the lock
+	lockTimeout    = 10 * time.Second
+	lockRetryDelay = 50 * time.Millisecond
 )
 
 // Config represents the CLI configuration
 type Config struct {
 	CurrentInstance string              `json:"currentInstance"`
 	Instances       map[string]Instance `json:"instances"`
+	// Aliases maps a command name to the arguments it expands to,
+	// e.g. "active": "workflow list --active"
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// DefaultPullDir is the directory 'workflow pull' writes to when no
+	// --dir is given, relative to the working directory
+	DefaultPullDir string `json:"defaultPullDir,omitempty"`
+	// RedactProfiles maps profile names to the key patterns they mask,
+	// selected with --redact-profile
+	RedactProfiles map[string][]string `json:"redactProfiles,omitempty"`
+	// Timeout limits API requests, as a Go duration such as "30s"
+	Timeout string `json:"timeout,omitempty"`
+	// RunTimeout limits requests that execute a workflow, e.g. 'workflow
+	// run --wait'
+	RunTimeout string `json:"runTimeout,omitempty"`
+	// AuditLog is the file mutating API requests are recorded in. Relative
+	// paths are resolved against the config directory.
+	AuditLog string `json:"auditLog,omitempty"`
 }
 
+// Prefixes for API keys that are resolved at runtime instead of being
+// stored in the config file
+const (
+	APIKeyFilePrefix    = "file:"
+	APIKeyCommandPrefix = "cmd:"
+)
+
 // Instance represents an n8n instance configuration
 type Instance struct {
-	Name   string `json:"name"`
-	URL    string `json:"url"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// APIKey is either the key itself, "file:<path>" to read it from a
+	// file, or "cmd:<command>" to take it from a command's output
 	APIKey string `json:"apiKey"`
+	// AuthMode is "apikey" (default) or "bearer"
+	AuthMode string `json:"authMode,omitempty"`
+	// NoVersionPrefix sends requests to URL plus the endpoint path without
+	// /api/v1 in between, for gateways that add it themselves
+	NoVersionPrefix bool `json:"noVersionPrefix,omitempty"`
+	// Headers are extra HTTP headers sent with every API request
+	Headers map[string]string `json:"headers,omitempty"`
+	// Aliases are command aliases that only apply while this instance is
+	// active. They take precedence over global aliases of the same name.
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// DefaultPullDir overrides the global DefaultPullDir for this instance
+	DefaultPullDir string `json:"defaultPullDir,omitempty"`
+	// Timeout and RunTimeout override the global settings for this instance
+	Timeout    string `json:"timeout,omitempty"`
+	RunTimeout string `json:"runTimeout,omitempty"`
 }
 
-// GetCurrentInstance returns the currently active instance
+// ParseHeaders parses "Key=Value" pairs as given on the command line
+func ParseHeaders(pairs []string) (map[string]string, error) {
+	headers := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid header %q, expected key=value", pair)
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// ResolveAPIKey returns the actual API key, reading it from a file or
+// running a helper command if the configured value references one.
+func (i *Instance) ResolveAPIKey() (string, error) {
+	switch {
+	case strings.HasPrefix(i.APIKey, APIKeyFilePrefix):
+		path := strings.TrimPrefix(i.APIKey, APIKeyFilePrefix)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read API key file for instance '%s': %w", i.Name, err)
+		}
+		key := strings.TrimSpace(string(data))
+		if key == "" {
+			return "", fmt.Errorf("API key file %s for instance '%s' is empty", path, i.Name)
+		}
+		return key, nil
+
+	case strings.HasPrefix(i.APIKey, APIKeyCommandPrefix):
+		command := strings.TrimPrefix(i.APIKey, APIKeyCommandPrefix)
+		var c *exec.Cmd
+		if runtime.GOOS == "windows" {
+			c = exec.Command("cmd", "/C", command)
+		} else {
+			c = exec.Command("sh", "-c", command)
+		}
+		var stderr bytes.Buffer
+		c.Stderr = &stderr
+		out, err := c.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("API key command for instance '%s' failed: %w: %s", i.Name, err, msg)
+			}
+			return "", fmt.Errorf("API key command for instance '%s' failed: %w", i.Name, err)
+		}
+		key := strings.TrimSpace(string(out))
+		if key == "" {
+			return "", fmt.Errorf("API key command for instance '%s' produced no output", i.Name)
+		}
+		return key, nil
+	}
+
+	return i.APIKey, nil
+}
+
+// MaskAPIKey returns a preview of an API key showing only its first and
+// last four characters. File and command references are not secret and
+// are returned unchanged.
+func MaskAPIKey(key string) string {
+	if strings.HasPrefix(key, APIKeyFilePrefix) || strings.HasPrefix(key, APIKeyCommandPrefix) {
+		return key
+	}
+	if len(key) <= 8 {
+		return strings.Repeat("*", len(key))
+	}
+	return key[:4] + "..." + key[len(key)-4:]
+}
+
+// GetCurrentInstance returns the currently active instance with its
+// API key resolved
 func (c *Config) GetCurrentInstance() (*Instance, error) {
 	if c.CurrentInstance == "" {
 		return nil, fmt.Errorf("no instance selected. Run 'n8n config use <name>'")
 	}
+	return c.GetInstance(c.CurrentInstance)
+}
 
-	instance, exists := c.Instances[c.CurrentInstance]
+// GetInstance returns the named instance with its API key resolved
+func (c *Config) GetInstance(name string) (*Instance, error) {
+	instance, exists := c.Instances[name]
 	if !exists {
-		return nil, fmt.Errorf("instance '%s' not found", c.CurrentInstance)
+		return nil, fmt.Errorf("instance '%s' not found", name)
 	}
 
+	// The returned copy carries the resolved key; the stored reference
+	// is left untouched so it is never persisted in plaintext
+	key, err := instance.ResolveAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	instance.APIKey = key
+
 	return &instance, nil
 }
 
-// configDir returns the configuration directory path
+// ActiveAliases returns the global aliases merged with those of the
+// current instance
+func (c *Config) ActiveAliases() map[string]string {
+	aliases := make(map[string]string, len(c.Aliases))
+	for name, expansion := range c.Aliases {
+		aliases[name] = expansion
+	}
+	if instance, ok := c.Instances[c.CurrentInstance]; ok {
+		for name, expansion := range instance.Aliases {
+			aliases[name] = expansion
+		}
+	}
+	return aliases
+}
+
+// PullDir returns the default pull directory of the current instance,
+// falling back to the global setting
+func (c *Config) PullDir() string {
+	return c.InstancePullDir(c.CurrentInstance)
+}
+
+// InstancePullDir returns the default pull directory of the named
+// instance, falling back to the global setting
+func (c *Config) InstancePullDir(name string) string {
+	if instance, ok := c.Instances[name]; ok && instance.DefaultPullDir != "" {
+		return instance.DefaultPullDir
+	}
+	return c.DefaultPullDir
+}
+
+// Timeouts returns the request timeouts of the current instance, falling
+// back to the global settings. Unset timeouts are zero.
+func (c *Config) Timeouts() (timeout, runTimeout time.Duration, err error) {
+	return c.InstanceTimeouts(c.CurrentInstance)
+}
+
+// InstanceTimeouts returns the request timeouts of the named instance,
+// falling back to the global settings
+func (c *Config) InstanceTimeouts(name string) (timeout, runTimeout time.Duration, err error) {
+	instance := c.Instances[name]
+	if timeout, err = ParseTimeout(firstNonEmpty(instance.Timeout, c.Timeout)); err != nil {
+		return 0, 0, fmt.Errorf("invalid timeout setting: %w", err)
+	}
+	if runTimeout, err = ParseTimeout(firstNonEmpty(instance.RunTimeout, c.RunTimeout)); err != nil {
+		return 0, 0, fmt.Errorf("invalid run-timeout setting: %w", err)
+	}
+	return timeout, runTimeout, nil
+}
+
+// AuditLogPath returns the path of the audit log, or "" if auditing is off
+func (c *Config) AuditLogPath() (string, error) {
+	if c.AuditLog == "" || filepath.IsAbs(c.AuditLog) {
+		return c.AuditLog, nil
+	}
+	dir, err := readDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, c.AuditLog), nil
+}
+
+// ConflictMode decides what Import does with an instance whose name is
+// already taken
+type ConflictMode string
+
+const (
+	ConflictSkip      ConflictMode = "skip"
+	ConflictOverwrite ConflictMode = "overwrite"
+	ConflictRename    ConflictMode = "rename"
+)
+
+// ImportResult reports what Import did with each instance
+type ImportResult struct {
+	Added       []string `json:"added"`
+	Overwritten []string `json:"overwritten"`
+	Skipped     []string `json:"skipped"`
+	// Renamed maps imported names to the names they were stored under
+	Renamed map[string]string `json:"renamed"`
+	// Activated is the instance made current, if any
+	Activated string `json:"activated,omitempty"`
+}
+
+// APIKeyCommands returns the helper commands that instances of c run to
+// get their API keys, by instance name
+func (c *Config) APIKeyCommands() map[string]string {
+	commands := make(map[string]string)
+	for name, instance := range c.Instances {
+		if strings.HasPrefix(instance.APIKey, APIKeyCommandPrefix) {
+			commands[name] = strings.TrimPrefix(instance.APIKey, APIKeyCommandPrefix)
+		}
+	}
+	return commands
+}
+
+// Import merges the instances of from into c. Clashing names are handled
+// according to mode; renamed instances get the first free "<name>-<n>".
+// With activate set, the current instance of from becomes current, unless
+// it was skipped. Other settings of from are ignored.
+func (c *Config) Import(from *Config, mode ConflictMode, activate bool) *ImportResult {
+	result := &ImportResult{
+		Added:       []string{},
+		Overwritten: []string{},
+		Skipped:     []string{},
+		Renamed:     map[string]string{},
+	}
+	if c.Instances == nil {
+		c.Instances = make(map[string]Instance)
+	}
+
+	names := make([]string, 0, len(from.Instances))
+	for name := range from.Instances {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stored := make(map[string]string, len(names))
+	for _, name := range names {
+		instance := from.Instances[name]
+		target := name
+		if _, exists := c.Instances[name]; exists {
+			switch mode {
+			case ConflictOverwrite:
+				result.Overwritten = append(result.Overwritten, name)
+			case ConflictRename:
+				for n := 2; ; n++ {
+					target = fmt.Sprintf("%s-%d", name, n)
+					if _, taken := c.Instances[target]; !taken {
+						break
+					}
+				}
+				result.Renamed[name] = target
+			default:
+				result.Skipped = append(result.Skipped, name)
+				continue
+			}
+		} else {
+			result.Added = append(result.Added, name)
+		}
+
+		instance.Name = target
+		c.Instances[target] = instance
+		stored[name] = target
+	}
+
+	if target, ok := stored[from.CurrentInstance]; ok && activate {
+		c.CurrentInstance = target
+		result.Activated = target
+	}
+	return result
+}
+
+// ParseTimeout parses a timeout setting. An empty string is zero (unset).
+func ParseTimeout(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %s", s)
+	}
+	return d, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// appSupportEnv switches the configuration directory on macOS to
+// ~/Library/Application Support/n8n-cli when set to a true value
+const appSupportEnv = "N8NCTL_APP_SUPPORT"
+
+// configDir returns the configuration directory path: $XDG_CONFIG_HOME/n8n-cli
+// if XDG_CONFIG_HOME is set to an absolute path, ~/.config/n8n-cli
+// otherwise. On macOS, N8NCTL_APP_SUPPORT selects the Application Support
+// directory instead.
 func configDir() (string, error) {
+	if runtime.GOOS == "darwin" {
+		if on, _ := strconv.ParseBool(os.Getenv(appSupportEnv)); on {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to get home directory: %w", err)
+			}
+			return filepath.Join(home, "Library", "Application Support", "n8n-cli"), nil
+		}
+	}
+
+	// The XDG spec says relative paths are invalid and must be ignored
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "n8n-cli"), nil
+	}
+
+	return legacyConfigDir()
+}
+
+// legacyConfigDir returns ~/.config/n8n-cli, where the configuration lived
+// before XDG_CONFIG_HOME was honored
+func legacyConfigDir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", fmt.Errorf("failed to get home directory: %w", err)
@@ -44,9 +388,9 @@ func configDir() (string, error) {
 	return filepath.Join(home, ".config", "n8n-cli"), nil
 }
 
-// configPath returns the configuration file path
+// configPath returns the path the configuration is read from
 func configPath() (string, error) {
-	dir, err := configDir()
+	dir, err := readDir()
 	if err != nil {
 		return "", err
 	}
@@ -54,6 +398,186 @@ func configPath() (string, error) {
 	return filepath.Join(dir, "config.json"), nil
 }
 
+// readDir returns the directory the configuration is read from, which
+// relative settings such as the audit log are resolved against. That is
+// configDir, or the legacy directory as long as the configuration there
+// hasn't been moved yet. Reading never moves anything; Save does.
+func readDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	legacyDir, err := legacyConfigDir()
+	if err != nil || legacyDir == dir {
+		return dir, nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.json")); !os.IsNotExist(err) {
+		return dir, nil
+	}
+	if _, err := os.Stat(filepath.Join(legacyDir, "config.json")); err != nil {
+		return dir, nil
+	}
+	return legacyDir, nil
+}
+
+// migrateLegacyDir moves the files of the legacy directory, such as a
+// relative audit log, to dir once the configuration was saved there, and
+// removes the legacy configuration file. Files that already exist in dir
+// are left where they are.
+func migrateLegacyDir(legacyDir, dir string) error {
+	err := filepath.WalkDir(legacyDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(legacyDir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "config.json" || rel == "config.json.lock" {
+			return nil
+		}
+		target := filepath.Join(dir, rel)
+		if _, err := os.Lstat(target); !os.IsNotExist(err) {
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+			return err
+		}
+		if err := moveFile(path, target); err != nil {
+			return fmt.Errorf("failed to move %s: %w", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(filepath.Join(legacyDir, "config.json")); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	logging.Info(fmt.Sprintf("Moved configuration from %s to %s", legacyDir, dir), "from", legacyDir, "to", dir)
+	return nil
+}
+
+// moveFile renames src to dst, copying it where renaming fails, e.g.
+// across file systems
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
+// lockPath returns the path of the advisory lock file
+func lockPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "config.json.lock"), nil
+}
+
+// lock acquires an exclusive advisory lock on the configuration by locking
+// a file next to it. The operating system drops the lock when the holder
+// exits, so a crashed process never leaves the configuration locked. The
+// returned function releases the lock.
+func lock() (func(), error) {
+	dir, err := configDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	path, err := lockPath()
+	if err != nil {
+		return nil, err
+	}
+
+	// The file is never removed: another process may already have it open
+	// and would otherwise lock an unlinked file
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("timed out waiting for config lock %s", path)
+		}
+		time.Sleep(lockRetryDelay)
+	}
+
+	return func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// Update loads the configuration while holding an exclusive lock, applies
+// fn, and saves the result. Concurrent updates are serialized so no change
+// is lost. If no configuration exists yet, fn receives an empty one.
+func Update(fn func(cfg *Config) error) error {
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cfg, err := Load()
+	if err != nil {
+		if Exists() {
+			return err
+		}
+		cfg = &Config{}
+	}
+	if cfg.Instances == nil {
+		cfg.Instances = make(map[string]Instance)
+	}
+
+	if err := fn(cfg); err != nil {
+		return err
+	}
+
+	return Save(cfg)
+}
+
 // Load loads the configuration from disk
 func Load() (*Config, error) {
 	path, err := configPath()
@@ -77,7 +601,10 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
-// Save saves the configuration to disk
+// Save saves the configuration to disk. The file is written to a temporary
+// file and renamed into place, so readers never observe a partial write.
+// Callers that modify an existing configuration should use Update instead,
+// which also guards against concurrent writers.
 func Save(cfg *Config) error {
 	dir, err := configDir()
 	if err != nil {
@@ -89,10 +616,13 @@ func Save(cfg *Config) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	path, err := configPath()
+	// A configuration still read from the legacy directory moves along
+	// with this write
+	from, err := readDir()
 	if err != nil {
 		return err
 	}
+	path := filepath.Join(dir, "config.json")
 
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
@@ -100,13 +630,56 @@ func Save(cfg *Config) error {
 	}
 
 	// Write with restricted permissions (owner read/write only)
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := writeFileAtomic(path, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
 	}
 
+	if from != dir {
+		if err := migrateLegacyDir(from, dir); err != nil {
+			logging.Warn(fmt.Sprintf("failed to move the files in %s to %s: %v", from, dir, err), "from", from, "to", dir, "error", err)
+		}
+	}
+
 	return nil
 }
 
+// writeFileAtomic writes data to a temporary file in the same directory as
+// path, flushes it to disk, and renames it over path. A crash at any point
+// leaves either the previous file or the complete new one, never a
+// truncated mix of both. The temporary file is removed on failure.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	// CreateTemp uses 0600; apply the requested mode explicitly so it
+	// doesn't depend on the umask
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	// Flush before renaming, otherwise the rename may reach the disk
+	// before the data does and a crash would leave an empty file
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, path)
+}
+
 // Exists checks if a configuration file exists
 func Exists() bool {
 	path, err := configPath()
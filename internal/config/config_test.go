@@ -0,0 +1,334 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestAPIKeyCommands(t *testing.T) {
+	cfg := &Config{Instances: map[string]Instance{
+		"plain":   {APIKey: "n8n_api_123"},
+		"file":    {APIKey: "file:/run/secrets/n8n"},
+		"command": {APIKey: "cmd:pass show n8n/prod"},
+		"empty":   {APIKey: "cmd:"},
+	}}
+
+	want := map[string]string{"command": "pass show n8n/prod", "empty": ""}
+	if got := cfg.APIKeyCommands(); !reflect.DeepEqual(got, want) {
+		t.Errorf("APIKeyCommands() = %v, want %v", got, want)
+	}
+}
+
+func TestInstancePullDir(t *testing.T) {
+	cfg := &Config{
+		CurrentInstance: "dev",
+		DefaultPullDir:  "./workflows",
+		Instances: map[string]Instance{
+			"dev":     {DefaultPullDir: "./dev"},
+			"prod":    {DefaultPullDir: "./prod"},
+			"staging": {},
+		},
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "dev", want: "./dev"},
+		{name: "prod", want: "./prod"},
+		{name: "staging", want: "./workflows"},
+		{name: "unknown", want: "./workflows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.InstancePullDir(tt.name); got != tt.want {
+				t.Errorf("InstancePullDir(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+	if got := cfg.PullDir(); got != "./dev" {
+		t.Errorf("PullDir() = %q, want the current instance's ./dev", got)
+	}
+}
+
+func TestConfigDir(t *testing.T) {
+	home := t.TempDir()
+	xdg := filepath.Join(home, "xdg")
+
+	// N8NCTL_APP_SUPPORT only has an effect on macOS
+	appSupport := filepath.Join(xdg, "n8n-cli")
+	if runtime.GOOS == "darwin" {
+		appSupport = filepath.Join(home, "Library", "Application Support", "n8n-cli")
+	}
+
+	tests := []struct {
+		name       string
+		xdg        string
+		appSupport string
+		want       string
+	}{
+		{name: "default", want: filepath.Join(home, ".config", "n8n-cli")},
+		{name: "XDG_CONFIG_HOME", xdg: xdg, want: filepath.Join(xdg, "n8n-cli")},
+		{name: "relative XDG_CONFIG_HOME ignored", xdg: "relative/xdg", want: filepath.Join(home, ".config", "n8n-cli")},
+		{name: "app support off", xdg: xdg, appSupport: "0", want: filepath.Join(xdg, "n8n-cli")},
+		{name: "app support", xdg: xdg, appSupport: "1", want: appSupport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", home)
+			t.Setenv("XDG_CONFIG_HOME", tt.xdg)
+			t.Setenv(appSupportEnv, tt.appSupport)
+
+			got, err := configDir()
+			if err != nil {
+				t.Fatalf("configDir() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("configDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLegacyConfigMigration(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	t.Setenv(appSupportEnv, "")
+
+	legacyDir := filepath.Join(home, ".config", "n8n-cli")
+	newDir := filepath.Join(home, "xdg", "n8n-cli")
+	writeFile(t, filepath.Join(legacyDir, "config.json"), `{"currentInstance":"dev","auditLog":"audit.log"}`)
+	writeFile(t, filepath.Join(legacyDir, "audit.log"), "entry\n")
+	writeFile(t, filepath.Join(legacyDir, "state", "cursor.json"), "{}")
+
+	// Reading uses the legacy files in place
+	if !Exists() {
+		t.Fatal("Exists() = false for a legacy configuration")
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.CurrentInstance != "dev" {
+		t.Errorf("CurrentInstance = %q, want dev", cfg.CurrentInstance)
+	}
+	if got, _ := cfg.AuditLogPath(); got != filepath.Join(legacyDir, "audit.log") {
+		t.Errorf("AuditLogPath() = %q before the move, want the legacy file", got)
+	}
+	if _, err := os.Stat(newDir); !os.IsNotExist(err) {
+		t.Fatalf("reading created %s", newDir)
+	}
+
+	// Changing the configuration moves it with the other files
+	if err := Update(func(cfg *Config) error {
+		cfg.CurrentInstance = "prod"
+		return nil
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	for rel, want := range map[string]string{"audit.log": "entry\n", filepath.Join("state", "cursor.json"): "{}"} {
+		data, err := os.ReadFile(filepath.Join(newDir, rel))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", rel, data, err, want)
+		}
+		if _, err := os.Stat(filepath.Join(legacyDir, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s was left in the legacy directory", rel)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(legacyDir, "config.json")); !os.IsNotExist(err) {
+		t.Error("legacy config.json was not removed")
+	}
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() after the move error = %v", err)
+	}
+	if cfg.CurrentInstance != "prod" {
+		t.Errorf("CurrentInstance = %q, want prod", cfg.CurrentInstance)
+	}
+	if got, _ := cfg.AuditLogPath(); got != filepath.Join(newDir, "audit.log") {
+		t.Errorf("AuditLogPath() = %q after the move, want the moved file", got)
+	}
+}
+
+func TestLegacyConfigMigrationKeepsExistingFiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "xdg"))
+	t.Setenv(appSupportEnv, "")
+
+	legacyDir := filepath.Join(home, ".config", "n8n-cli")
+	newDir := filepath.Join(home, "xdg", "n8n-cli")
+	writeFile(t, filepath.Join(legacyDir, "config.json"), `{"currentInstance":"old"}`)
+	writeFile(t, filepath.Join(newDir, "config.json"), `{"currentInstance":"new"}`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.CurrentInstance != "new" {
+		t.Errorf("CurrentInstance = %q, want the configuration in the new directory", cfg.CurrentInstance)
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(legacyDir, "config.json")); err != nil {
+		t.Errorf("legacy config.json was touched although the new one exists: %v", err)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// useTempConfigDir points the configuration directory at a new temporary
+// directory and returns it
+func useTempConfigDir(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(appSupportEnv, "")
+	return filepath.Join(home, ".config", "n8n-cli")
+}
+
+func TestUpdateConcurrent(t *testing.T) {
+	useTempConfigDir(t)
+
+	const writers = 20
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			errs <- Update(func(cfg *Config) error {
+				cfg.Instances[fmt.Sprintf("instance-%d", i)] = Instance{URL: fmt.Sprintf("https://n8n-%d.example.com", i)}
+				return nil
+			})
+		}(i)
+	}
+	for i := 0; i < writers; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Instances) != writers {
+		t.Errorf("got %d instances, want %d; concurrent updates were lost", len(cfg.Instances), writers)
+	}
+}
+
+func TestUpdateFailureKeepsConfig(t *testing.T) {
+	dir := useTempConfigDir(t)
+	path := filepath.Join(dir, "config.json")
+	if err := Save(&Config{CurrentInstance: "dev", Instances: map[string]Instance{"dev": {URL: "https://dev.example.com"}}}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("failed halfway")
+	err = Update(func(cfg *Config) error {
+		cfg.CurrentInstance = "prod"
+		delete(cfg.Instances, "dev")
+		return failure
+	})
+	if err != failure {
+		t.Fatalf("Update() error = %v, want %v", err, failure)
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Errorf("config changed by a failed update:\n%s", after)
+	}
+}
+
+func TestLock(t *testing.T) {
+	dir := useTempConfigDir(t)
+	// A lock file left behind by a crashed process must not block anyone
+	writeFile(t, filepath.Join(dir, "config.json.lock"), "12345")
+
+	unlock, err := lock()
+	if err != nil {
+		t.Fatalf("lock() with a leftover lock file: %v", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, "config.json.lock"), os.O_RDWR, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if locked, err := tryLockFile(f); err != nil || locked {
+		t.Fatalf("tryLockFile() while held = %v, %v, want false", locked, err)
+	}
+
+	unlock()
+	locked, err := tryLockFile(f)
+	if err != nil || !locked {
+		t.Fatalf("tryLockFile() after unlock = %v, %v, want true", locked, err)
+	}
+	_ = unlockFile(f)
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomic(path, []byte("new"), 0600); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Errorf("content = %q, %v; want new", data, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	// Renaming over a directory fails after the temporary file was
+	// written; it must not be left behind
+	target := filepath.Join(dir, "occupied")
+	if err := os.MkdirAll(filepath.Join(target, "keep"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(target, []byte("new"), 0600); err == nil {
+		t.Fatal("writeFileAtomic() over a directory succeeded")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != "config.json" && e.Name() != "occupied" {
+			t.Errorf("left %s behind after a failed write", e.Name())
+		}
+	}
+	if _, err := os.Stat(filepath.Join(target, "keep")); err != nil {
+		t.Errorf("failed write changed the target: %v", err)
+	}
+}
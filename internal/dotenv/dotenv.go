@@ -0,0 +1,168 @@
+// Package dotenv reads KEY=VALUE files as used by dotenv-based tooling
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Var is one assignment of an env file
+type Var struct {
+	Key   string
+	Value string
+}
+
+// Parse reads assignments from the content of an env file. The syntax is
+// the common dotenv one:
+//
+//	# comment
+//	KEY=value            # unquoted, trailing comments are dropped
+//	export KEY=value     # export prefix is ignored
+//	KEY='literal $value' # single quotes keep everything as written
+//	KEY="a\nb"           # double quotes understand \n, \r, \t, \" and \\
+//
+// Quoted values may span lines. Variables are not expanded.
+func Parse(data string) ([]Var, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	lines := strings.Split(data, "\n")
+
+	var vars []Var
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+		key := strings.TrimSpace(line[:eq])
+		if !validKey(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNo, key)
+		}
+		rest := strings.TrimLeft(line[eq+1:], " \t")
+
+		if rest == "" || (rest[0] != '"' && rest[0] != '\'') {
+			vars = append(vars, Var{Key: key, Value: unquoted(rest)})
+			continue
+		}
+
+		// Quoted values continue on the following lines until the
+		// closing quote
+		quote := rest[0]
+		text := rest[1:]
+		for {
+			value, after, ok := closeQuote(text, quote)
+			if ok {
+				after = strings.TrimSpace(after)
+				if after != "" && !strings.HasPrefix(after, "#") {
+					return nil, fmt.Errorf("line %d: unexpected text after closing quote", lineNo)
+				}
+				vars = append(vars, Var{Key: key, Value: value})
+				break
+			}
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("line %d: missing closing %c", lineNo, quote)
+			}
+			text += "\n" + lines[i]
+		}
+	}
+	return vars, nil
+}
+
+// unquoted returns an unquoted value without a trailing comment, which
+// starts at a # preceded by whitespace
+func unquoted(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// closeQuote looks for the quote closing text and returns the value
+// before it, with escapes resolved for double quotes, and the text after
+// it
+func closeQuote(text string, quote byte) (string, string, bool) {
+	if quote == '\'' {
+		end := strings.IndexByte(text, '\'')
+		if end < 0 {
+			return "", "", false
+		}
+		return text[:end], text[end+1:], true
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"':
+			return b.String(), text[i+1:], true
+		case c == '\\' && i+1 < len(text):
+			i++
+			switch text[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(text[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(text[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", false
+}
+
+// validKey reports whether key is a usable variable name: letters,
+// digits, underscores and dots, not starting with a digit or dot
+func validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z'):
+		case i > 0 && ((c >= '0' && c <= '9') || c == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Load reads the env file at path and sets its variables in the process
+// environment. Variables that are already set keep their value unless
+// override is true.
+func Load(path string, override bool) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read env file: %w", err)
+	}
+	vars, err := Parse(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, v := range vars {
+		if _, set := os.LookupEnv(v.Key); set && !override {
+			continue
+		}
+		if err := os.Setenv(v.Key, v.Value); err != nil {
+			return fmt.Errorf("%s: failed to set %s: %w", path, v.Key, err)
+		}
+	}
+	return nil
+}
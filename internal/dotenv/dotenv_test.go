@@ -0,0 +1,105 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Var
+	}{
+		{name: "plain", data: "N8N_URL=https://n8n.example.com\n", want: []Var{{"N8N_URL", "https://n8n.example.com"}}},
+		{name: "spaces around", data: "  KEY = value  \n", want: []Var{{"KEY", "value"}}},
+		{name: "empty value", data: "KEY=\n", want: []Var{{"KEY", ""}}},
+		{name: "export", data: "export N8N_API_KEY=abc\n", want: []Var{{"N8N_API_KEY", "abc"}}},
+		{name: "comments and blank lines", data: "# settings\n\nA=1\n   # indented\nB=2\n", want: []Var{{"A", "1"}, {"B", "2"}}},
+		{name: "trailing comment", data: "A=1 # one\nB=2\t# two\n", want: []Var{{"A", "1"}, {"B", "2"}}},
+		{name: "hash inside value", data: "URL=https://example.com/#frag\n", want: []Var{{"URL", "https://example.com/#frag"}}},
+		{name: "single quotes are literal", data: `A='x $HOME \n # y'`, want: []Var{{"A", `x $HOME \n # y`}}},
+		{name: "double quote escapes", data: `A="tab\there \"q\" back\\slash \$x"`, want: []Var{{"A", "tab\there \"q\" back\\slash $x"}}},
+		{name: "double quote newline escape", data: `A="a\nb"`, want: []Var{{"A", "a\nb"}}},
+		{name: "unknown escape kept", data: `A="\d"`, want: []Var{{"A", `\d`}}},
+		{name: "comment after quotes", data: `A="v" # note`, want: []Var{{"A", "v"}}},
+		{name: "multiline double quotes", data: "KEY=\"-----BEGIN-----\nabc\n-----END-----\"\nNEXT=1\n", want: []Var{{"KEY", "-----BEGIN-----\nabc\n-----END-----"}, {"NEXT", "1"}}},
+		{name: "multiline single quotes", data: "KEY='a\n  b'\n", want: []Var{{"KEY", "a\n  b"}}},
+		{name: "CRLF", data: "A=1\r\nB=\"x\r\ny\"\r\n", want: []Var{{"A", "1"}, {"B", "x\ny"}}},
+		{name: "dotted key", data: "app.name=n8n\n", want: []Var{{"app.name", "n8n"}}},
+		{name: "repeated key", data: "A=1\nA=2\n", want: []Var{{"A", "1"}, {"A", "2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.data)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "no equals", data: "A=1\nJUSTTEXT\n", want: "line 2: expected KEY=VALUE"},
+		{name: "invalid key", data: "1A=x\n", want: `line 1: invalid variable name "1A"`},
+		{name: "key with dash", data: "MY-KEY=x\n", want: `invalid variable name "MY-KEY"`},
+		{name: "unterminated double quote", data: "A=1\nB=\"open\nstill open\n", want: "line 2: missing closing \""},
+		{name: "unterminated single quote", data: "A='open", want: "line 1: missing closing '"},
+		{name: "text after quote", data: `A="v" extra`, want: "line 1: unexpected text after closing quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("N8NCTL_TEST_SET=from-file\nN8NCTL_TEST_NEW=new\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		override bool
+		wantSet  string
+	}{
+		{name: "keeps set variables", override: false, wantSet: "from-env"},
+		{name: "override", override: true, wantSet: "from-file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("N8NCTL_TEST_SET", "from-env")
+			t.Setenv("N8NCTL_TEST_NEW", "")
+			_ = os.Unsetenv("N8NCTL_TEST_NEW")
+
+			if err := Load(path, tt.override); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if got := os.Getenv("N8NCTL_TEST_SET"); got != tt.wantSet {
+				t.Errorf("N8NCTL_TEST_SET = %q, want %q", got, tt.wantSet)
+			}
+			if got := os.Getenv("N8NCTL_TEST_NEW"); got != "new" {
+				t.Errorf("N8NCTL_TEST_NEW = %q, want new", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package logging writes progress notes and warnings to stderr, either as
+// plain messages or as JSON lines for tools running the CLI, so command
+// results on stdout stay free of them
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Log formats accepted by Setup
+const (
+	Text = "text"
+	JSON = "json"
+)
+
+var logger = slog.New(newTextHandler(os.Stderr, slog.LevelInfo))
+
+// Setup replaces the logger with one writing to w in format, dropping
+// messages below level (debug, info, warn, or error)
+func Setup(w io.Writer, format, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: use debug, info, warn, or error", level)
+	}
+
+	switch format {
+	case Text:
+		logger = slog.New(newTextHandler(w, lvl))
+	case JSON:
+		logger = slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl}))
+	default:
+		return fmt.Errorf("invalid log format %q: use text or json", format)
+	}
+	return nil
+}
+
+// Debug logs details only shown with --log-level debug
+func Debug(msg string, args ...interface{}) {
+	logger.Debug(msg, args...)
+}
+
+// Info logs progress, such as each workflow pushed
+func Info(msg string, args ...interface{}) {
+	logger.Info(msg, args...)
+}
+
+// Warn logs problems that don't stop the command
+func Warn(msg string, args ...interface{}) {
+	logger.Warn(msg, args...)
+}
+
+// Error logs failures of single items, such as one of several workflows
+// to delete, that the command reports in its result
+func Error(msg string, args ...interface{}) {
+	logger.Error(msg, args...)
+}
+
+// textHandler prints messages the way the CLI always has: the message
+// alone, prefixed for levels other than info. Messages are complete
+// sentences, so the attributes are only written in JSON.
+type textHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Level
+}
+
+func newTextHandler(w io.Writer, level slog.Level) *textHandler {
+	return &textHandler{mu: &sync.Mutex{}, w: w, level: level}
+}
+
+func (h *textHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *textHandler) Handle(_ context.Context, r slog.Record) error {
+	prefix := ""
+	switch {
+	case r.Level >= slog.LevelError:
+		prefix = "Error: "
+	case r.Level >= slog.LevelWarn:
+		prefix = "Warning: "
+	case r.Level < slog.LevelInfo:
+		prefix = "Debug: "
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := fmt.Fprintf(h.w, "%s%s\n", prefix, r.Message)
+	return err
+}
+
+func (h *textHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *textHandler) WithGroup(string) slog.Handler      { return h }
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTextFormat(t *testing.T) {
+	tests := []struct {
+		level string
+		want  string
+	}{
+		{level: "debug", want: "Debug: d\ni\nWarning: w\nError: e\n"},
+		{level: "info", want: "i\nWarning: w\nError: e\n"},
+		{level: "warn", want: "Warning: w\nError: e\n"},
+		{level: "error", want: "Error: e\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Setup(&buf, Text, tt.level); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = Setup(&bytes.Buffer{}, Text, "info") })
+
+			Debug("d", "k", 1)
+			Info("i", "k", 1)
+			Warn("w", "k", 1)
+			Error("e", "k", 1)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Setup(&buf, JSON, "info"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Setup(&bytes.Buffer{}, Text, "info") })
+
+	Error("failed to delete execution 7", "execution", "7")
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("output %q is not JSON: %v", buf.String(), err)
+	}
+	if record["level"] != "ERROR" || record["msg"] != "failed to delete execution 7" || record["execution"] != "7" {
+		t.Errorf("record = %v", record)
+	}
+}
+
+func TestSetupRejectsUnknownValues(t *testing.T) {
+	tests := []struct {
+		format, level, want string
+	}{
+		{format: "xml", level: "info", want: "invalid log format"},
+		{format: Text, level: "loud", want: "invalid log level"},
+	}
+	for _, tt := range tests {
+		err := Setup(&bytes.Buffer{}, tt.format, tt.level)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Setup(%q, %q) error = %v, want %q", tt.format, tt.level, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// Format is an output format selected with --output
+type Format string
+
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	YAML  Format = "yaml"
+	JSONL Format = "jsonl"
+)
+
+// Formats lists all supported output formats
+var Formats = []Format{Table, JSON, YAML, JSONL}
+
+// Parse validates an output format name
+func Parse(s string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("invalid output format %q (expected one of: %s)", s, strings.Join(names, ", "))
+}
+
+// FromCmd returns the output format requested for a command through the
+// global --output flag. The deprecated --json flag maps to JSON.
+func FromCmd(cmd *cobra.Command) Format {
+	if jsonFlag, _ := cmd.Flags().GetBool("json"); jsonFlag {
+		return JSON
+	}
+	value, _ := cmd.Flags().GetString("output")
+	if f, err := Parse(value); err == nil {
+		return f
+	}
+	return Table
+}
+
+// IsStructured reports whether the command should print machine-readable
+// output instead of a human-readable table
+func IsStructured(cmd *cobra.Command) bool {
+	return FromCmd(cmd) != Table
+}
+
+// Print writes v to stdout in the command's output format. In table mode,
+// values that have no human-readable rendering are printed as JSON.
+func Print(cmd *cobra.Command, v interface{}) error {
+	return Write(os.Stdout, FromCmd(cmd), v)
+}
+
+// Write encodes v to w in the given format
+func Write(w io.Writer, format Format, v interface{}) error {
+	switch format {
+	case YAML:
+		return writeYAML(w, v)
+	case JSONL:
+		return writeJSONL(w, v)
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
+
+// writeYAML converts v to YAML by way of JSON, so the field names match
+// the json struct tags used throughout the API types
+func writeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeJSONL writes one compact JSON document per line. Slices are split
+// into their elements, as are list results with a Data slice; any other
+// value is written as a single line.
+func writeJSONL(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+
+	items := reflect.ValueOf(v)
+	for items.Kind() == reflect.Pointer && !items.IsNil() {
+		items = items.Elem()
+	}
+	if items.Kind() == reflect.Struct {
+		if data := items.FieldByName("Data"); data.IsValid() && data.Kind() == reflect.Slice {
+			items = data
+		}
+	}
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		return enc.Encode(v)
+	}
+
+	for i := 0; i < items.Len(); i++ {
+		if err := enc.Encode(items.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
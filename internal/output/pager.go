@@ -0,0 +1,96 @@
+package output
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/enthus-appdev/n8n-cli/internal/progress"
+)
+
+// pagerAnnotation marks commands whose table output may be paged
+const pagerAnnotation = "pager"
+
+// Pageable marks cmd as one whose human-readable output StartPager may
+// page. Commands that prompt on the terminal must not be marked.
+func Pageable(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[pagerAnnotation] = "true"
+	return cmd
+}
+
+// Pager feeds stdout to a pager process until Close
+type Pager struct {
+	process *exec.Cmd
+	stdout  *os.File
+	pipe    *os.File
+}
+
+// StartPager redirects os.Stdout to $PAGER (default less) like git does:
+// only for pageable commands printing tables to a terminal, and not with
+// --no-pager, --select, or PAGER set to "" or "cat". Unless LESS is set,
+// less is told to exit right away if the output fits on one screen. It
+// returns nil when nothing is paged, including when the pager fails to
+// start.
+func StartPager(cmd *cobra.Command) *Pager {
+	if cmd.Annotations[pagerAnnotation] != "true" || IsStructured(cmd) || !progress.IsTTY(os.Stdout) {
+		return nil
+	}
+	if noPager, _ := cmd.Flags().GetBool("no-pager"); noPager {
+		return nil
+	}
+	if selecting, _ := cmd.Flags().GetBool("select"); selecting {
+		return nil
+	}
+
+	command, ok := os.LookupEnv("PAGER")
+	if !ok {
+		command = "less"
+	}
+	if command == "" || command == "cat" {
+		return nil
+	}
+
+	var process *exec.Cmd
+	if runtime.GOOS == "windows" {
+		process = exec.Command("cmd", "/C", command)
+	} else {
+		process = exec.Command("sh", "-c", command)
+	}
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		process.Env = append(os.Environ(), "LESS=FRX")
+	}
+	process.Stdout = os.Stdout
+	process.Stderr = os.Stderr
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+	process.Stdin = r
+	if err := process.Start(); err != nil {
+		r.Close()
+		w.Close()
+		return nil
+	}
+	r.Close()
+
+	p := &Pager{process: process, stdout: os.Stdout, pipe: w}
+	os.Stdout = w
+	return p
+}
+
+// Close restores stdout and waits for the user to quit the pager. It is
+// safe to call on a nil Pager.
+func (p *Pager) Close() {
+	if p == nil {
+		return
+	}
+	os.Stdout = p.stdout
+	p.pipe.Close()
+	_ = p.process.Wait()
+}
@@ -0,0 +1,219 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// TableWriter prints rows with columns sized to their widest cell.
+// Widths are measured in terminal cells, so names with CJK characters or
+// emoji line up. With --plain, rows are printed tab-separated without the
+// header, for scripts.
+type TableWriter struct {
+	headers []string
+	rows    [][]string
+	plain   bool
+}
+
+// plainReplacer keeps cells of plain output on one line and in one field
+var plainReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+// NewTable creates a table with the given column headers
+func NewTable(cmd *cobra.Command, headers ...string) *TableWriter {
+	plain, _ := cmd.Flags().GetBool("plain")
+	return &TableWriter{headers: headers, plain: plain}
+}
+
+// AddRow appends a row. Missing cells are left empty, extra ones dropped.
+func (t *TableWriter) AddRow(cells ...string) {
+	row := make([]string, len(t.headers))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+}
+
+// Print writes the table to stdout
+func (t *TableWriter) Print() {
+	t.Render(os.Stdout)
+}
+
+// Render writes the table to w
+func (t *TableWriter) Render(w io.Writer) {
+	if t.plain {
+		for _, row := range t.rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = plainReplacer.Replace(cell)
+			}
+			fmt.Fprintln(w, strings.Join(cells, "\t"))
+		}
+		return
+	}
+
+	widths := make([]int, len(t.headers))
+	for i, header := range t.headers {
+		widths[i] = Width(header)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if width := Width(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("-", width)
+	}
+	t.renderRow(w, t.headers, widths)
+	t.renderRow(w, separators, widths)
+	for _, row := range t.rows {
+		t.renderRow(w, row, widths)
+	}
+}
+
+// Footer prints a summary line below the table, separated by a blank
+// line. With --plain it goes to stderr instead, so scripts reading the
+// rows from stdout don't have to filter it out.
+func (t *TableWriter) Footer(format string, args ...interface{}) {
+	if t.plain {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+		return
+	}
+	fmt.Printf("\n"+format+"\n", args...)
+}
+
+// renderRow pads all cells but the last to their column width
+func (t *TableWriter) renderRow(w io.Writer, cells []string, widths []int) {
+	var b strings.Builder
+	for i, cell := range cells {
+		if i > 0 {
+			b.WriteString("  ")
+		}
+		b.WriteString(cell)
+		if i < len(cells)-1 {
+			b.WriteString(strings.Repeat(" ", widths[i]-Width(cell)))
+		}
+	}
+	fmt.Fprintln(w, b.String())
+}
+
+// Width returns the number of terminal cells s takes up. East Asian wide
+// characters and most emoji take two, combining marks and other zero
+// width characters none.
+func Width(s string) int {
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// Truncate shortens s to at most width terminal cells, marking the cut
+// with "..."
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	used := 0
+	for _, r := range s {
+		w := runeWidth(r)
+		if used+w > width-3 {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	return b.String() + "..."
+}
+
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), r >= 0xfe00 && r <= 0xfe0f:
+		return 0
+	}
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+	if i < len(wideRanges) && wideRanges[i][0] <= r {
+		return 2
+	}
+	return 1
+}
+
+// wideRanges are the sorted code point ranges displayed two cells wide:
+// the East Asian wide and fullwidth blocks and the emoji most commonly
+// shown with emoji presentation
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f},   // Hangul Jamo initials
+	{0x231a, 0x231b},   // watch, hourglass
+	{0x23e9, 0x23ec},   // media buttons
+	{0x23f0, 0x23f0},   // alarm clock
+	{0x23f3, 0x23f3},   // hourglass with flowing sand
+	{0x25fd, 0x25fe},   // small squares
+	{0x2614, 0x2615},   // umbrella, hot beverage
+	{0x2648, 0x2653},   // zodiac
+	{0x267f, 0x267f},   // wheelchair
+	{0x2693, 0x2693},   // anchor
+	{0x26a1, 0x26a1},   // high voltage
+	{0x26aa, 0x26ab},   // circles
+	{0x26bd, 0x26be},   // soccer, baseball
+	{0x26c4, 0x26c5},   // snowman, sun behind cloud
+	{0x26ce, 0x26ce},   // ophiuchus
+	{0x26d4, 0x26d4},   // no entry
+	{0x26ea, 0x26ea},   // church
+	{0x26f2, 0x26f3},   // fountain, golf
+	{0x26f5, 0x26f5},   // sailboat
+	{0x26fa, 0x26fa},   // tent
+	{0x26fd, 0x26fd},   // fuel pump
+	{0x2705, 0x2705},   // check mark button
+	{0x270a, 0x270b},   // raised fist, hand
+	{0x2728, 0x2728},   // sparkles
+	{0x274c, 0x274c},   // cross mark
+	{0x274e, 0x274e},   // cross mark button
+	{0x2753, 0x2755},   // question and exclamation marks
+	{0x2757, 0x2757},   // exclamation mark
+	{0x2795, 0x2797},   // plus, minus, divide
+	{0x27b0, 0x27b0},   // curly loop
+	{0x27bf, 0x27bf},   // double curly loop
+	{0x2b1b, 0x2b1c},   // large squares
+	{0x2b50, 0x2b50},   // star
+	{0x2b55, 0x2b55},   // hollow red circle
+	{0x2e80, 0x303e},   // CJK radicals, symbols and punctuation
+	{0x3041, 0x33ff},   // Hiragana, Katakana, CJK compatibility
+	{0x3400, 0x4dbf},   // CJK extension A
+	{0x4e00, 0x9fff},   // CJK unified ideographs
+	{0xa000, 0xa4cf},   // Yi
+	{0xa960, 0xa97f},   // Hangul Jamo extended A
+	{0xac00, 0xd7a3},   // Hangul syllables
+	{0xf900, 0xfaff},   // CJK compatibility ideographs
+	{0xfe10, 0xfe19},   // vertical forms
+	{0xfe30, 0xfe6f},   // CJK compatibility forms, small forms
+	{0xff00, 0xff60},   // fullwidth forms
+	{0xffe0, 0xffe6},   // fullwidth signs
+	{0x16fe0, 0x16fe4}, // ideographic symbols
+	{0x17000, 0x18cff}, // Tangut
+	{0x1b000, 0x1b2ff}, // Kana supplement, Nushu
+	{0x1f004, 0x1f004}, // mahjong tile
+	{0x1f0cf, 0x1f0cf}, // joker
+	{0x1f18e, 0x1f18e}, // AB button
+	{0x1f191, 0x1f19a}, // squared words
+	{0x1f1e6, 0x1f1ff}, // regional indicators (flags)
+	{0x1f200, 0x1f2ff}, // enclosed ideographic supplement
+	{0x1f300, 0x1f64f}, // pictographs, emoticons
+	{0x1f680, 0x1f6ff}, // transport and map symbols
+	{0x1f7e0, 0x1f7eb}, // colored circles and squares
+	{0x1f90c, 0x1f9ff}, // supplemental symbols and pictographs
+	{0x1fa70, 0x1faff}, // symbols and pictographs extended A
+	{0x20000, 0x2fffd}, // CJK extensions B to F
+	{0x30000, 0x3fffd}, // CJK extension G and later
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "Daily report", 12},
+		{"latin accents", "Größe", 5},
+		{"combining mark", "é", 1},
+		{"cjk", "日本語", 6},
+		{"hangul", "한국", 4},
+		{"fullwidth", "ＡＢ", 4},
+		{"emoji", "🚀 launch", 9},
+		{"emoji with variation selector", "✅️", 2},
+		{"flag", "🇩🇪", 4},
+		{"control characters", "a\tb", 2},
+		{"zero width joiner", "a‍b", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Width(tt.in); got != tt.want {
+				t.Errorf("Width(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{"fits", "short", 10, "short"},
+		{"exact", "0123456789", 10, "0123456789"},
+		{"ascii", "0123456789abc", 10, "0123456..."},
+		{"cjk", "日本語のワークフロー", 10, "日本語..."},
+		{"cjk cut inside a wide character", "日本語のワークフロー", 11, "日本語の..."},
+		{"emoji", "🚀🚀🚀🚀🚀🚀", 8, "🚀🚀..."},
+		{"multibyte not cut", "Größenänderung", 8, "Größe..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.in, tt.width)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+			}
+			if Width(got) > tt.width {
+				t.Errorf("Truncate(%q, %d) is %d cells wide", tt.in, tt.width, Width(got))
+			}
+		})
+	}
+}
+
+func TestTableRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		plain bool
+		want  string
+	}{
+		{
+			name: "aligned",
+			want: "ID  NAME\n" +
+				"--  ------\n" +
+				"1   日本語\n" +
+				"22  a\tb\n",
+		},
+		{
+			name:  "plain",
+			plain: true,
+			want:  "1\t日本語\n22\ta b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table := &TableWriter{headers: []string{"ID", "NAME"}, plain: tt.plain}
+			table.AddRow("1", "日本語")
+			table.AddRow("22", "a\tb", "dropped")
+
+			var buf bytes.Buffer
+			table.Render(&buf)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package pagestate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+)
+
+// State is the pagination position of a list command, saved between runs
+// so processing can continue where the previous run stopped
+type State struct {
+	Command string `json:"command"`
+	// FilterHash identifies the filters the cursor was obtained with
+	FilterHash string `json:"filterHash"`
+	// Cursor is the next page to fetch; empty once all pages were read
+	Cursor  string    `json:"cursor"`
+	SavedAt time.Time `json:"savedAt"`
+}
+
+// FilterHash returns a stable hash of a command's filter values
+func FilterHash(filters interface{}) string {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Load reads a saved state. A missing file yields nil without error, so
+// the first run of a pipeline starts from the beginning.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cursor file: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse cursor file %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// Save writes the state to path
+func Save(path string, state *State) error {
+	state.SavedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cursor state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cursor file: %w", err)
+	}
+	return nil
+}
+
+// Resume returns the cursor to continue from, or "" to start from the
+// beginning. complete reports that the saved run already read the last
+// page. It warns on stderr if the saved state belongs to another command
+// or was obtained with different filters.
+func Resume(path, command, filterHash string) (cursor string, complete bool, err error) {
+	state, err := Load(path)
+	if err != nil || state == nil {
+		return "", false, err
+	}
+
+	if state.Command != command {
+		logging.Warn(fmt.Sprintf("cursor file %s was saved by '%s', not '%s'", path, state.Command, command), "file", path, "savedBy", state.Command)
+	} else if state.FilterHash != filterHash {
+		logging.Warn(fmt.Sprintf("cursor file %s was saved with different filters; results may be inconsistent", path), "file", path)
+	}
+	return state.Cursor, state.Cursor == "", nil
+}
+
+// Flags are the pagination flags of a list command
+type Flags struct {
+	Limit int
+	// LimitSet reports whether --limit was given rather than defaulted
+	LimitSet   bool
+	Cursor     string
+	Resume     string
+	SaveCursor string
+}
+
+// Check rejects pagination flags that contradict each other. --cursor
+// and --resume both choose where to start, and either fetches a single
+// page, so they can't be combined with each other or with an explicit
+// --limit 0 (everything). --save-cursor needs a limit to stop at: an
+// unlimited listing reads every page and leaves no cursor to save.
+func (f Flags) Check() error {
+	switch {
+	case f.Cursor != "" && f.Resume != "":
+		return fmt.Errorf("--resume and --cursor cannot be combined")
+	case f.LimitSet && f.Limit == 0 && (f.Cursor != "" || f.Resume != ""):
+		return fmt.Errorf("--limit 0 lists everything, but --cursor and --resume fetch a single page; give a page size with --limit instead")
+	case f.SaveCursor != "" && f.Limit == 0:
+		return fmt.Errorf("--save-cursor needs a --limit greater than 0; without one every page is read and no cursor remains")
+	case f.Limit < 0:
+		return fmt.Errorf("--limit cannot be negative")
+	}
+	return nil
+}
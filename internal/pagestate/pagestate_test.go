@@ -0,0 +1,60 @@
+package pagestate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlagsCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   Flags
+		wantErr string
+	}{
+		{name: "defaults", flags: Flags{Limit: 100}},
+		{name: "no limit", flags: Flags{Limit: 0, LimitSet: true}},
+		{name: "cursor", flags: Flags{Limit: 100, Cursor: "abc"}},
+		{name: "cursor with page size", flags: Flags{Limit: 50, LimitSet: true, Cursor: "abc"}},
+		{name: "resume with save", flags: Flags{Limit: 100, Resume: "state.json", SaveCursor: "state.json"}},
+		{
+			name:    "cursor and resume",
+			flags:   Flags{Limit: 100, Cursor: "abc", Resume: "state.json"},
+			wantErr: "--resume and --cursor cannot be combined",
+		},
+		{
+			name:    "cursor with limit 0",
+			flags:   Flags{Limit: 0, LimitSet: true, Cursor: "abc"},
+			wantErr: "--limit 0 lists everything",
+		},
+		{
+			name:    "resume with limit 0",
+			flags:   Flags{Limit: 0, LimitSet: true, Resume: "state.json"},
+			wantErr: "--limit 0 lists everything",
+		},
+		{
+			name:    "save cursor without limit",
+			flags:   Flags{Limit: 0, SaveCursor: "state.json"},
+			wantErr: "--save-cursor needs a --limit greater than 0",
+		},
+		{
+			name:    "negative limit",
+			flags:   Flags{Limit: -1, LimitSet: true},
+			wantErr: "--limit cannot be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.flags.Check()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Check() error = %v, want none", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Check() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,368 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	// defaultPollConcurrency bounds the status requests of one poll that
+	// are in flight at the same time
+	defaultPollConcurrency = 8
+	// maxPollErrors is how often in a row fetching an execution may fail
+	// before the tracker gives up on it
+	maxPollErrors = 5
+)
+
+// StatusWaiting is the status of executions paused by a Wait node or
+// waiting for a webhook call to resume them
+const StatusWaiting = "waiting"
+
+// WaitingError is returned by Wait when the only unfinished executions
+// have been paused for longer than the waiting timeout
+type WaitingError struct {
+	IDs     []string
+	Timeout time.Duration
+}
+
+func (e *WaitingError) Error() string {
+	return fmt.Sprintf("%d execution(s) paused awaiting external input for more than %s: %s",
+		len(e.IDs), e.Timeout, strings.Join(e.IDs, ", "))
+}
+
+// IsTerminal reports whether an execution status is final
+func IsTerminal(status string) bool {
+	switch status {
+	case "success", "error", "crashed", "canceled":
+		return true
+	}
+	return false
+}
+
+// IsTTY reports whether f is attached to a terminal
+func IsTTY(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// row tracks the latest known state of a single execution
+type row struct {
+	id        string
+	workflow  string
+	status    string
+	startedAt *time.Time
+	stoppedAt *time.Time
+	exec      *api.Execution
+	err       error
+	printed   string
+	// waitingSince is when the execution was first seen waiting
+	waitingSince time.Time
+	// errors counts the failed fetches since the last successful one
+	errors int
+	// failed is set once the tracker gave up fetching the execution
+	failed bool
+}
+
+// finished reports whether the row needs no further polling
+func (r *row) finished() bool {
+	return r.failed || IsTerminal(r.status)
+}
+
+// Tracker polls a set of executions concurrently and renders their status
+// until all of them reach a terminal state. On a TTY the status table is
+// redrawn in place; otherwise a line is printed for every status change.
+type Tracker struct {
+	client   *api.Client
+	rows     []*row
+	names    map[string]string
+	interval time.Duration
+	// concurrency bounds the requests in flight during a poll
+	concurrency int
+	out         io.Writer
+	tty         bool
+	drawn       int
+	started     time.Time
+	// waitingTimeout stops Wait once only waiting executions are left
+	// and all of them have waited this long (0 = no limit)
+	waitingTimeout time.Duration
+}
+
+// NewTracker creates a tracker for the given execution IDs
+func NewTracker(client *api.Client, ids []string) *Tracker {
+	rows := make([]*row, len(ids))
+	for i, id := range ids {
+		rows[i] = &row{id: id}
+	}
+	return &Tracker{
+		client:      client,
+		rows:        rows,
+		names:       make(map[string]string),
+		interval:    defaultPollInterval,
+		concurrency: defaultPollConcurrency,
+		out:         os.Stdout,
+		tty:         IsTTY(os.Stdout),
+	}
+}
+
+// Quiet disables status rendering, e.g. when the caller prints JSON
+func (t *Tracker) Quiet() {
+	t.out = io.Discard
+	t.tty = false
+}
+
+// SetConcurrency limits how many executions are fetched at the same time
+// in each poll, e.g. to the concurrency the executions were started with.
+// Values below 1 are ignored.
+func (t *Tracker) SetConcurrency(n int) {
+	if n > 0 {
+		t.concurrency = n
+	}
+}
+
+// SetWaitingTimeout makes Wait give up on executions that stay in the
+// waiting status, e.g. at a Wait node, for longer than d. Once only such
+// executions are left, Wait returns a *WaitingError. Zero waits for them
+// like for running executions.
+func (t *Tracker) SetWaitingTimeout(d time.Duration) {
+	t.waitingTimeout = d
+}
+
+// Wait polls until every execution is finished or the timeout elapses.
+// A zero timeout waits indefinitely. Executions that can't be fetched,
+// because they don't exist (anymore) or after repeated errors, count as
+// finished, and Wait returns an error naming them. The latest known state
+// of each execution is returned, even when the wait fails.
+func (t *Tracker) Wait(timeout time.Duration) ([]*api.Execution, error) {
+	t.started = time.Now()
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = t.started.Add(timeout)
+	}
+
+	for {
+		t.poll()
+		t.render()
+
+		if t.done() {
+			return t.executions(), t.failures()
+		}
+		if ids := t.stalled(); ids != nil {
+			return t.executions(), &WaitingError{IDs: ids, Timeout: t.waitingTimeout}
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return t.executions(), fmt.Errorf("timed out after %s waiting for %d execution(s)", timeout, t.pending())
+		}
+
+		time.Sleep(t.interval)
+	}
+}
+
+// poll fetches the current state of all unfinished executions, at most
+// concurrency at a time
+func (t *Tracker) poll() {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, t.concurrency)
+	for _, r := range t.rows {
+		if r.finished() {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *row) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			exec, err := t.client.GetExecution(r.id, false)
+			if err != nil {
+				r.err = err
+				r.errors++
+				// A missing execution won't turn up later, e.g. when the
+				// instance doesn't save executions or pruned it
+				if api.IsNotFound(err) || r.errors >= maxPollErrors {
+					r.failed = true
+				}
+				return
+			}
+			r.err = nil
+			r.errors = 0
+			r.exec = exec
+			r.startedAt = exec.StartedAt
+			r.stoppedAt = exec.StoppedAt
+			r.status = exec.Status
+			if r.status == "" && exec.Finished {
+				r.status = "success"
+			}
+			if r.status != StatusWaiting {
+				r.waitingSince = time.Time{}
+			} else if r.waitingSince.IsZero() {
+				r.waitingSince = time.Now()
+			}
+			if r.workflow == "" {
+				r.workflow = exec.WorkflowID
+			}
+		}(r)
+	}
+	wg.Wait()
+
+	t.resolveNames()
+}
+
+// resolveNames looks up each workflow name once and caches it
+func (t *Tracker) resolveNames() {
+	for _, r := range t.rows {
+		if r.exec == nil {
+			continue
+		}
+		wfID := r.exec.WorkflowID
+		if _, ok := t.names[wfID]; ok {
+			continue
+		}
+		t.names[wfID] = wfID
+		if wf, err := t.client.GetWorkflow(wfID); err == nil {
+			t.names[wfID] = wf.Name
+		}
+	}
+	for _, r := range t.rows {
+		if r.exec != nil {
+			r.workflow = t.names[r.exec.WorkflowID]
+		}
+	}
+}
+
+func (t *Tracker) render() {
+	if !t.tty {
+		t.renderLines()
+		return
+	}
+
+	// Move the cursor back to the top of the previous table and redraw
+	if t.drawn > 0 {
+		fmt.Fprintf(t.out, "\033[%dA", t.drawn)
+	}
+
+	lines := []string{
+		fmt.Sprintf("%-10s  %-30s  %-10s  %s", "ID", "WORKFLOW", "STATUS", "ELAPSED"),
+		fmt.Sprintf("%-10s  %-30s  %-10s  %s", strings.Repeat("-", 10), strings.Repeat("-", 30), strings.Repeat("-", 10), strings.Repeat("-", 10)),
+	}
+	for _, r := range t.rows {
+		lines = append(lines, fmt.Sprintf("%-10s  %-30s  %-10s  %s", r.id, truncate(r.workflow, 30), t.statusText(r), t.elapsed(r)))
+	}
+	for _, line := range lines {
+		fmt.Fprintf(t.out, "\033[2K%s\n", line)
+	}
+	t.drawn = len(lines)
+}
+
+// renderLines prints one line per execution whose status changed since the
+// last render
+func (t *Tracker) renderLines() {
+	for _, r := range t.rows {
+		status := t.statusText(r)
+		if r.printed == status {
+			continue
+		}
+		r.printed = status
+		fmt.Fprintf(t.out, "%s  %s  %s  %s\n", r.id, r.workflow, status, t.elapsed(r))
+	}
+}
+
+func (t *Tracker) statusText(r *row) string {
+	if r.failed {
+		return "failed"
+	}
+	if r.err != nil && r.status == "" {
+		return "unknown"
+	}
+	if r.status == "" {
+		return "pending"
+	}
+	return r.status
+}
+
+func (t *Tracker) elapsed(r *row) string {
+	start := t.started
+	if r.startedAt != nil {
+		start = *r.startedAt
+	}
+	end := time.Now()
+	if r.stoppedAt != nil && IsTerminal(r.status) {
+		end = *r.stoppedAt
+	}
+	return end.Sub(start).Round(time.Second).String()
+}
+
+func (t *Tracker) done() bool {
+	return t.pending() == 0
+}
+
+func (t *Tracker) pending() int {
+	n := 0
+	for _, r := range t.rows {
+		if !r.finished() {
+			n++
+		}
+	}
+	return n
+}
+
+// failures returns an error naming the executions the tracker gave up
+// fetching, or nil if there are none
+func (t *Tracker) failures() error {
+	var failed []string
+	for _, r := range t.rows {
+		if r.failed {
+			failed = append(failed, fmt.Sprintf("%s: %v", r.id, r.err))
+		}
+	}
+	if failed == nil {
+		return nil
+	}
+	return fmt.Errorf("failed to get the status of %d execution(s): %s", len(failed), strings.Join(failed, "; "))
+}
+
+// stalled returns the IDs of the unfinished executions if all of them have
+// been waiting for longer than the waiting timeout, and nil otherwise
+func (t *Tracker) stalled() []string {
+	if t.waitingTimeout <= 0 {
+		return nil
+	}
+	var ids []string
+	for _, r := range t.rows {
+		if r.finished() {
+			continue
+		}
+		if r.status != StatusWaiting || time.Since(r.waitingSince) < t.waitingTimeout {
+			return nil
+		}
+		ids = append(ids, r.id)
+	}
+	return ids
+}
+
+func (t *Tracker) executions() []*api.Execution {
+	execs := make([]*api.Execution, 0, len(t.rows))
+	for _, r := range t.rows {
+		if r.exec != nil {
+			execs = append(execs, r.exec)
+		} else {
+			execs = append(execs, &api.Execution{ID: r.id, Status: t.statusText(r)})
+		}
+	}
+	return execs
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,170 @@
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+)
+
+// newTestTracker returns a quiet tracker polling srv without pauses
+func newTestTracker(srv *apitest.Server, ids ...string) *Tracker {
+	tracker := NewTracker(api.NewClient(srv.URL, "key"), ids)
+	tracker.Quiet()
+	tracker.interval = time.Millisecond
+	return tracker
+}
+
+// waitWithin runs tracker.Wait and fails the test if it doesn't return
+// within a few seconds
+func waitWithin(t *testing.T, tracker *Tracker, timeout time.Duration) ([]*api.Execution, error) {
+	t.Helper()
+	type result struct {
+		execs []*api.Execution
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		execs, err := tracker.Wait(timeout)
+		done <- result{execs, err}
+	}()
+	select {
+	case r := <-done:
+		return r.execs, r.err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return")
+		return nil, nil
+	}
+}
+
+func TestTrackerWait(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		body           interface{}
+		timeout        time.Duration
+		waitingTimeout time.Duration
+		wantStatus     string
+		wantErr        string
+		wantWaiting    bool
+		wantRequests   int
+	}{
+		{name: "done", body: map[string]string{"id": "1", "workflowId": "w1", "status": "success"}, wantStatus: "success", wantRequests: 1},
+		{name: "stalled", body: map[string]string{"id": "1", "workflowId": "w1", "status": "waiting"}, waitingTimeout: time.Millisecond, wantStatus: "waiting", wantWaiting: true},
+		{name: "timeout", body: map[string]string{"id": "1", "workflowId": "w1", "status": "running"}, timeout: 20 * time.Millisecond, wantStatus: "running", wantErr: "timed out"},
+		{name: "not found", status: http.StatusNotFound, body: map[string]string{"message": "Not Found"}, wantStatus: "failed", wantErr: "failed to get the status of 1 execution(s): 1:", wantRequests: 1},
+		{name: "persistent server error", status: http.StatusBadGateway, body: "bad gateway", wantStatus: "failed", wantErr: "502", wantRequests: maxPollErrors},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			srv.Handle(http.MethodGet, "/executions/1", apitest.Response{Status: tt.status, Body: tt.body})
+			srv.Handle(http.MethodGet, "/workflows/w1", apitest.Response{Body: map[string]string{"id": "w1", "name": "Flow"}})
+
+			tracker := newTestTracker(srv, "1")
+			tracker.SetWaitingTimeout(tt.waitingTimeout)
+			execs, err := waitWithin(t, tracker, tt.timeout)
+
+			var waiting *WaitingError
+			switch {
+			case tt.wantWaiting:
+				if !errors.As(err, &waiting) || len(waiting.IDs) != 1 || waiting.IDs[0] != "1" {
+					t.Errorf("error = %v, want a WaitingError for execution 1", err)
+				}
+			case tt.wantErr == "":
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			case err == nil || !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if len(execs) != 1 || execs[0].Status != tt.wantStatus {
+				t.Fatalf("executions = %+v, want one with status %s", execs, tt.wantStatus)
+			}
+
+			if tt.wantRequests > 0 {
+				polls := 0
+				for _, req := range srv.Requests() {
+					if req.Path == "/executions/1" {
+						polls++
+					}
+				}
+				if polls != tt.wantRequests {
+					t.Errorf("polled %d times, want %d", polls, tt.wantRequests)
+				}
+			}
+		})
+	}
+}
+
+func TestTrackerRecoversFromTransientErrors(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		n := calls
+		mu.Unlock()
+		// Fail just below the limit, then succeed
+		if n < maxPollErrors {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": "1", "status": "success"})
+	})
+
+	execs, err := waitWithin(t, newTestTracker(srv, "1"), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if execs[0].Status != "success" {
+		t.Errorf("status = %s, want success", execs[0].Status)
+	}
+}
+
+func TestTrackerPollConcurrency(t *testing.T) {
+	const executions, limit = 20, 3
+	var (
+		mu       sync.Mutex
+		inFlight int
+		peak     int
+	)
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		_ = json.NewEncoder(w).Encode(map[string]string{"id": id, "status": "success"})
+	})
+
+	ids := make([]string, executions)
+	for i := range ids {
+		ids[i] = string(rune('a' + i))
+	}
+	tracker := newTestTracker(srv, ids...)
+	tracker.SetConcurrency(limit)
+	if _, err := waitWithin(t, tracker, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if peak > limit {
+		t.Errorf("%d requests in flight at once, want at most %d", peak, limit)
+	}
+}
@@ -0,0 +1,143 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/enthus-appdev/n8n-cli/internal/progress"
+)
+
+// Confirm asks the user to confirm a destructive operation. It returns nil
+// right away if the global --yes flag is set. Without an interactive
+// terminal to ask on, it refuses instead of proceeding silently.
+func Confirm(cmd *cobra.Command, question string) error {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes {
+		return nil
+	}
+
+	if !progress.IsTTY(os.Stdin) {
+		return fmt.Errorf("confirmation required but stdin is not a terminal (use --yes to proceed)")
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		return fmt.Errorf("aborted (use --yes to skip confirmation)")
+	}
+	return nil
+}
+
+// maxShown limits how many entries Select lists at once
+const maxShown = 20
+
+// Option is an entry offered by Select
+type Option struct {
+	ID    string
+	Label string
+}
+
+// Select lets the user pick one of options on the terminal and returns its
+// ID. The list and prompt go to stderr, so that stdout only carries the
+// result, e.g. inside $(...). Typing text narrows the list to entries that
+// contain its characters in order; typing a number picks that entry.
+func Select(options []Option) (string, error) {
+	if !progress.IsTTY(os.Stdin) || !progress.IsTTY(os.Stderr) {
+		return "", fmt.Errorf("--select requires an interactive terminal")
+	}
+	if len(options) == 0 {
+		return "", fmt.Errorf("nothing to select")
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	matches := options
+	for {
+		for i, o := range matches {
+			if i == maxShown {
+				fmt.Fprintf(os.Stderr, "  ... and %d more, type to filter\n", len(matches)-maxShown)
+				break
+			}
+			fmt.Fprintf(os.Stderr, "%3d) %s\n", i+1, o.Label)
+		}
+		fmt.Fprintf(os.Stderr, "Filter or number (Enter with one match selects it): ")
+
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+			return "", fmt.Errorf("selection cancelled")
+		}
+		input = strings.TrimSpace(input)
+
+		switch n, err := strconv.Atoi(input); {
+		case input == "" && len(matches) == 1:
+			return matches[0].ID, nil
+		case input == "":
+			matches = options
+		case err == nil && n >= 1 && n <= len(matches) && n <= maxShown:
+			return matches[n-1].ID, nil
+		default:
+			filtered := filterOptions(options, input)
+			if len(filtered) == 0 {
+				fmt.Fprintf(os.Stderr, "No matches for %q\n", input)
+				continue
+			}
+			matches = filtered
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// filterOptions returns the options whose ID or label contains the
+// characters of query in order, ignoring case
+func filterOptions(options []Option, query string) []Option {
+	query = strings.ToLower(query)
+	var matches []Option
+	for _, o := range options {
+		if fuzzyMatch(strings.ToLower(o.ID+" "+o.Label), query) {
+			matches = append(matches, o)
+		}
+	}
+	return matches
+}
+
+func fuzzyMatch(s, query string) bool {
+	for _, r := range query {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
+// StdinIDs reads IDs piped into a batch command, one per line. Blank lines
+// and lines starting with # are skipped. If stdin is a terminal there is
+// nothing piped in, so it fails instead of waiting for input.
+func StdinIDs() ([]string, error) {
+	if progress.IsTTY(os.Stdin) {
+		return nil, fmt.Errorf("no IDs given: pass them as arguments or pipe them in, one per line")
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read IDs from stdin: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no IDs read from stdin")
+	}
+	return ids, nil
+}
@@ -0,0 +1,170 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/config"
+)
+
+// Mask replaces redacted values
+const Mask = "[REDACTED]"
+
+// DefaultProfile is the name of the built-in profile. A profile of the
+// same name in the config replaces it.
+const DefaultProfile = "default"
+
+// Pattern prefixes. Patterns without a prefix match keys containing them.
+const (
+	// RegexPrefix marks a Go regular expression matched against the key
+	RegexPrefix = "re:"
+	// ExactPrefix marks a key that must match exactly
+	ExactPrefix = "="
+)
+
+// DefaultKeys are the key fragments treated as sensitive by Default. Keys
+// are compared case-insensitively with '-' and '_' removed.
+var DefaultKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"apikey",
+	"authorization",
+	"cookie",
+	"privatekey",
+	"accesskey",
+}
+
+// Redactor masks values stored under sensitive keys
+type Redactor struct {
+	fragments []string
+	exact     map[string]bool
+	patterns  []*regexp.Regexp
+}
+
+// New creates a redactor from key patterns. A pattern is a key fragment
+// ("token" matches "accessToken"), an exact key prefixed with "="
+// ("=pin"), or a regular expression prefixed with "re:" ("re:^x-.*-id$").
+// Fragments and exact keys ignore case, '-', and '_'; regular expressions
+// see the key as is, so use (?i) to ignore case.
+func New(patterns []string) (*Redactor, error) {
+	r := &Redactor{exact: make(map[string]bool)}
+	for _, p := range patterns {
+		switch {
+		case strings.HasPrefix(p, RegexPrefix):
+			re, err := regexp.Compile(strings.TrimPrefix(p, RegexPrefix))
+			if err != nil {
+				return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+			}
+			r.patterns = append(r.patterns, re)
+		case strings.HasPrefix(p, ExactPrefix):
+			r.exact[normalizeKey(strings.TrimPrefix(p, ExactPrefix))] = true
+		case p != "":
+			r.fragments = append(r.fragments, normalizeKey(p))
+		}
+	}
+	return r, nil
+}
+
+// Default returns a redactor using DefaultKeys
+func Default() *Redactor {
+	r, _ := New(DefaultKeys)
+	return r
+}
+
+// ForProfile returns the redactor for a named profile from profiles, as
+// configured in the config file. An empty name selects DefaultProfile,
+// which falls back to DefaultKeys if profiles doesn't define it.
+func ForProfile(name string, profiles map[string][]string) (*Redactor, error) {
+	if name == "" {
+		name = DefaultProfile
+	}
+	if patterns, ok := profiles[name]; ok {
+		return New(patterns)
+	}
+	if name == DefaultProfile {
+		return Default(), nil
+	}
+
+	names := []string{DefaultProfile}
+	for n := range profiles {
+		if n != DefaultProfile {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names[1:])
+	return nil, fmt.Errorf("unknown redaction profile %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ForConfiguredProfile returns the redactor for a named profile from the
+// config file's redactProfiles, see ForProfile. Without a config file only
+// the default profile is available; a config file that can't be read is an
+// error rather than a silent fallback to DefaultKeys.
+func ForConfiguredProfile(name string) (*Redactor, error) {
+	var profiles map[string][]string
+	if config.Exists() {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, err
+		}
+		profiles = cfg.RedactProfiles
+	}
+	return ForProfile(name, profiles)
+}
+
+// Apply masks, in place, every value in v whose map key is sensitive,
+// descending into nested maps and arrays. It returns the number of values
+// masked.
+func (r *Redactor) Apply(v interface{}) int {
+	count := 0
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if r.IsSensitive(key) {
+				if item != nil && item != Mask {
+					value[key] = Mask
+					count++
+				}
+				continue
+			}
+			count += r.Apply(item)
+		}
+	case []interface{}:
+		for _, item := range value {
+			count += r.Apply(item)
+		}
+	case []map[string]interface{}:
+		for _, item := range value {
+			count += r.Apply(item)
+		}
+	}
+	return count
+}
+
+// IsSensitive reports whether values under key are masked
+func (r *Redactor) IsSensitive(key string) bool {
+	for _, re := range r.patterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	normalized := normalizeKey(key)
+	if r.exact[normalized] {
+		return true
+	}
+	for _, f := range r.fragments {
+		if strings.Contains(normalized, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.ReplaceAll(key, "_", "")
+	return strings.ReplaceAll(key, "-", "")
+}
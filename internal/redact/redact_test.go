@@ -0,0 +1,170 @@
+package redact
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIsSensitive(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		key      string
+		want     bool
+	}{
+		{name: "fragment", patterns: []string{"token"}, key: "accessToken", want: true},
+		{name: "fragment ignores case", patterns: []string{"TOKEN"}, key: "refresh_token", want: true},
+		{name: "fragment ignores separators", patterns: []string{"api_key"}, key: "X-API-Key", want: true},
+		{name: "fragment miss", patterns: []string{"token"}, key: "tokamak", want: false},
+		{name: "exact", patterns: []string{"=pin"}, key: "PIN", want: true},
+		{name: "exact ignores separators", patterns: []string{"=client-id"}, key: "client_id", want: true},
+		{name: "exact is not a fragment", patterns: []string{"=pin"}, key: "pinCode", want: false},
+		{name: "regex", patterns: []string{"re:^x-.*-id$"}, key: "x-tenant-id", want: true},
+		{name: "regex is case-sensitive", patterns: []string{"re:^x-.*-id$"}, key: "X-Tenant-Id", want: false},
+		{name: "regex with (?i)", patterns: []string{"re:(?i)^x-.*-id$"}, key: "X-Tenant-Id", want: true},
+		{name: "regex sees separators", patterns: []string{"re:^apikey$"}, key: "api_key", want: false},
+		{name: "empty pattern ignored", patterns: []string{""}, key: "anything", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(tt.patterns)
+			if err != nil {
+				t.Fatalf("New() error: %v", err)
+			}
+			if got := r.IsSensitive(tt.key); got != tt.want {
+				t.Errorf("IsSensitive(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewInvalidRegex(t *testing.T) {
+	if _, err := New([]string{"re:("}); err == nil || !strings.Contains(err.Error(), `"re:("`) {
+		t.Errorf("error = %v, want it to name the invalid pattern", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	v := map[string]interface{}{
+		"name":     "Flow",
+		"password": "hunter2",
+		"empty":    nil,
+		"headers": []interface{}{
+			map[string]interface{}{"Authorization": "Bearer abc", "Accept": "json"},
+		},
+		"nodes": []map[string]interface{}{
+			{"parameters": map[string]interface{}{"apiKey": "k", "url": "https://example.com"}},
+		},
+		"token":  Mask,
+		"secret": nil,
+	}
+	want := map[string]interface{}{
+		"name":     "Flow",
+		"password": Mask,
+		"empty":    nil,
+		"headers": []interface{}{
+			map[string]interface{}{"Authorization": Mask, "Accept": "json"},
+		},
+		"nodes": []map[string]interface{}{
+			{"parameters": map[string]interface{}{"apiKey": Mask, "url": "https://example.com"}},
+		},
+		"token":  Mask,
+		"secret": nil,
+	}
+
+	if got := Default().Apply(v); got != 3 {
+		t.Errorf("Apply() = %d, want 3", got)
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("Apply() result = %v, want %v", v, want)
+	}
+}
+
+func TestForProfile(t *testing.T) {
+	profiles := map[string][]string{
+		"strict": {"=pin"},
+		"loose":  {"password"},
+	}
+	overridden := map[string][]string{DefaultProfile: {"=pin"}}
+
+	tests := []struct {
+		name     string
+		profile  string
+		profiles map[string][]string
+		key      string
+		want     bool
+		wantErr  string
+	}{
+		{name: "empty name uses default keys", key: "apiKey", want: true},
+		{name: "default without config", profile: DefaultProfile, key: "token", want: true},
+		{name: "named profile", profile: "strict", profiles: profiles, key: "pin", want: true},
+		{name: "named profile replaces default keys", profile: "strict", profiles: profiles, key: "token", want: false},
+		{name: "config overrides default", profiles: overridden, key: "token", want: false},
+		{name: "config default applies", profiles: overridden, key: "pin", want: true},
+		{name: "unknown profile", profile: "nope", profiles: profiles, wantErr: `unknown redaction profile "nope" (available: default, loose, strict)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ForProfile(tt.profile, tt.profiles)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := r.IsSensitive(tt.key); got != tt.want {
+				t.Errorf("IsSensitive(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForConfiguredProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		profile string
+		wantErr string
+	}{
+		{name: "no config file", profile: DefaultProfile},
+		{name: "configured profile", config: `{"redactProfiles": {"strict": ["=pin"]}}`, profile: "strict"},
+		{name: "unknown profile", config: `{}`, profile: "strict", wantErr: "unknown redaction profile"},
+		{name: "unreadable config", config: `{not json`, profile: DefaultProfile, wantErr: "failed to parse config"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", dir)
+			t.Setenv("N8NCTL_APP_SUPPORT", "")
+			if tt.config != "" {
+				path := filepath.Join(dir, "n8n-cli", "config.json")
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.config), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			r, err := ForConfiguredProfile(tt.profile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || r == nil {
+				t.Fatalf("ForConfiguredProfile() = %v, %v", r, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package workflow
+
+import (
+	"sort"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// InventoryEntry is a workflow of one or both compared instances
+type InventoryEntry struct {
+	Name   string `json:"name"`
+	FromID string `json:"fromId,omitempty"`
+	ToID   string `json:"toId,omitempty"`
+	// Diff counts the differing nodes of a changed workflow
+	Diff *NodeDiffStat `json:"diff,omitempty"`
+}
+
+// InventoryDiff is the result of comparing the workflows of two instances
+type InventoryDiff struct {
+	// Added lists workflows that only exist on the source instance
+	Added []InventoryEntry `json:"added"`
+	// Removed lists workflows that only exist on the target instance
+	Removed []InventoryEntry `json:"removed"`
+	// Changed lists workflows whose definitions differ
+	Changed   []InventoryEntry `json:"changed"`
+	Unchanged int              `json:"unchanged"`
+	// Duplicates lists names used by several workflows on one instance.
+	// These can't be matched and are left out of the other lists.
+	Duplicates []string `json:"duplicates,omitempty"`
+}
+
+// HasDrift reports whether the instances differ
+func (d *InventoryDiff) HasDrift() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Changed) > 0 || len(d.Duplicates) > 0
+}
+
+// CompareInventories matches the workflows of two instances by name and
+// compares the normalized definitions of those on both. Credentials are
+// compared by name, since their IDs differ between instances, and so are
+// the workflows called by Execute Workflow nodes.
+func CompareInventories(from, to []api.Workflow) (*InventoryDiff, error) {
+	diff := &InventoryDiff{
+		Added:   []InventoryEntry{},
+		Removed: []InventoryEntry{},
+		Changed: []InventoryEntry{},
+	}
+
+	fromByName, fromDups := byName(from)
+	toByName, toDups := byName(to)
+	fromNames, toNames := namesByID(from), namesByID(to)
+	duplicates := make(map[string]bool)
+	for name := range fromDups {
+		duplicates[name] = true
+	}
+	for name := range toDups {
+		duplicates[name] = true
+	}
+
+	for name, wf := range fromByName {
+		if duplicates[name] {
+			continue
+		}
+		other, ok := toByName[name]
+		if !ok {
+			diff.Added = append(diff.Added, InventoryEntry{Name: name, FromID: wf.ID})
+			continue
+		}
+
+		a := withSubWorkflowNames(withoutCredentialIDs(wf), fromNames)
+		b := withSubWorkflowNames(withoutCredentialIDs(other), toNames)
+		equal, err := Equal(a, b)
+		if err != nil {
+			return nil, err
+		}
+		if equal {
+			diff.Unchanged++
+			continue
+		}
+		// Like workflows, nodes only on the source instance count as added
+		stat := DiffNodes(b, a, NodeFilter{})
+		diff.Changed = append(diff.Changed, InventoryEntry{Name: name, FromID: wf.ID, ToID: other.ID, Diff: &stat})
+	}
+	for name, wf := range toByName {
+		if _, ok := fromByName[name]; !ok && !duplicates[name] {
+			diff.Removed = append(diff.Removed, InventoryEntry{Name: name, ToID: wf.ID})
+		}
+	}
+
+	for name := range duplicates {
+		diff.Duplicates = append(diff.Duplicates, name)
+	}
+	sort.Strings(diff.Duplicates)
+	for _, entries := range [][]InventoryEntry{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	}
+	return diff, nil
+}
+
+// byName indexes workflows by name and returns the names used more than
+// once separately
+func byName(workflows []api.Workflow) (map[string]*api.Workflow, map[string]bool) {
+	index := make(map[string]*api.Workflow, len(workflows))
+	dups := make(map[string]bool)
+	for i := range workflows {
+		name := workflows[i].Name
+		if _, exists := index[name]; exists {
+			dups[name] = true
+		}
+		index[name] = &workflows[i]
+	}
+	return index, dups
+}
+
+// namesByID maps workflow IDs to names
+func namesByID(workflows []api.Workflow) map[string]string {
+	names := make(map[string]string, len(workflows))
+	for _, wf := range workflows {
+		names[wf.ID] = wf.Name
+	}
+	return names
+}
+
+// withSubWorkflowNames returns a copy of wf whose Execute Workflow nodes
+// refer to the called workflows by name rather than ID, using names.
+// IDs missing from names are kept. The cached URL of a resource locator
+// contains the ID and is dropped. wf itself is not modified.
+func withSubWorkflowNames(wf *api.Workflow, names map[string]string) *api.Workflow {
+	byName := func(id string) string {
+		if name, ok := names[id]; ok {
+			return "name:" + name
+		}
+		return id
+	}
+
+	copied := *wf
+	copied.Nodes = make([]map[string]interface{}, len(wf.Nodes))
+	for i, node := range wf.Nodes {
+		copied.Nodes[i] = node
+		if nodeType, _ := node["type"].(string); nodeType != "n8n-nodes-base.executeWorkflow" {
+			continue
+		}
+		params, ok := node["parameters"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		renamed := make(map[string]interface{}, len(params))
+		for key, value := range params {
+			renamed[key] = value
+		}
+		switch ref := params["workflowId"].(type) {
+		case string:
+			renamed["workflowId"] = byName(ref)
+		case map[string]interface{}:
+			locator := make(map[string]interface{}, len(ref))
+			for key, value := range ref {
+				locator[key] = value
+			}
+			if id, ok := locator["value"].(string); ok {
+				locator["value"] = byName(id)
+			}
+			delete(locator, "cachedResultUrl")
+			renamed["workflowId"] = locator
+		}
+		if ref, ok := params["workflow"].(map[string]interface{}); ok {
+			obj := make(map[string]interface{}, len(ref))
+			for key, value := range ref {
+				obj[key] = value
+			}
+			if id, ok := obj["id"].(string); ok {
+				obj["id"] = byName(id)
+			}
+			renamed["workflow"] = obj
+		}
+
+		n := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			n[key] = value
+		}
+		n["parameters"] = renamed
+		copied.Nodes[i] = n
+	}
+	return &copied
+}
+
+// withoutCredentialIDs returns a copy of wf whose node credential
+// references only carry names. wf itself is not modified.
+func withoutCredentialIDs(wf *api.Workflow) *api.Workflow {
+	copied := *wf
+	copied.Nodes = make([]map[string]interface{}, len(wf.Nodes))
+	for i, node := range wf.Nodes {
+		creds, ok := node["credentials"].(map[string]interface{})
+		if !ok {
+			copied.Nodes[i] = node
+			continue
+		}
+
+		stripped := make(map[string]interface{}, len(creds))
+		for credType, ref := range creds {
+			if r, ok := ref.(map[string]interface{}); ok {
+				stripped[credType] = map[string]interface{}{"name": r["name"]}
+			} else {
+				stripped[credType] = ref
+			}
+		}
+		n := make(map[string]interface{}, len(node))
+		for key, value := range node {
+			n[key] = value
+		}
+		n["credentials"] = stripped
+		copied.Nodes[i] = n
+	}
+	return &copied
+}
@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+func executeNode(workflowID interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":       "Call",
+		"type":       "n8n-nodes-base.executeWorkflow",
+		"parameters": map[string]interface{}{"workflowId": workflowID},
+	}
+}
+
+func locator(id string) map[string]interface{} {
+	return map[string]interface{}{"__rl": true, "mode": "list", "value": id, "cachedResultName": "Child", "cachedResultUrl": "/workflow/" + id}
+}
+
+func TestCompareInventoriesSubWorkflowRefs(t *testing.T) {
+	tests := []struct {
+		name      string
+		fromRef   interface{}
+		toRef     interface{}
+		toChild   string
+		unchanged bool
+	}{
+		{name: "plain IDs of the same workflow", fromRef: "c1", toRef: "c2", toChild: "Child", unchanged: true},
+		{name: "resource locators of the same workflow", fromRef: locator("c1"), toRef: locator("c2"), toChild: "Child", unchanged: true},
+		{name: "different workflows", fromRef: "c1", toRef: "other", toChild: "Child", unchanged: false},
+		{name: "unknown IDs are compared as they are", fromRef: "x1", toRef: "x2", toChild: "Child", unchanged: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from := []api.Workflow{
+				{ID: "p1", Name: "Parent", Nodes: []map[string]interface{}{executeNode(tt.fromRef)}},
+				{ID: "c1", Name: "Child"},
+			}
+			to := []api.Workflow{
+				{ID: "p2", Name: "Parent", Nodes: []map[string]interface{}{executeNode(tt.toRef)}},
+				{ID: "c2", Name: tt.toChild},
+				{ID: "other", Name: "Other"},
+			}
+			before := executeNode(tt.fromRef)
+
+			diff, err := CompareInventories(from, to)
+			if err != nil {
+				t.Fatalf("CompareInventories: %v", err)
+			}
+			changed := false
+			for _, entry := range diff.Changed {
+				if entry.Name == "Parent" {
+					changed = true
+				}
+			}
+			if changed == tt.unchanged {
+				t.Errorf("Parent changed = %v, want %v", changed, !tt.unchanged)
+			}
+			if !reflect.DeepEqual(from[0].Nodes[0], before) {
+				t.Errorf("source node was modified: %v", from[0].Nodes[0])
+			}
+		})
+	}
+}
+
+func TestCompareInventoriesCredentialIDs(t *testing.T) {
+	node := func(id string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":        "HTTP",
+			"type":        "n8n-nodes-base.httpRequest",
+			"credentials": map[string]interface{}{"httpBasicAuth": map[string]interface{}{"id": id, "name": "API"}},
+		}
+	}
+	from := []api.Workflow{{ID: "a", Name: "WF", Nodes: []map[string]interface{}{node("1")}}}
+	to := []api.Workflow{{ID: "b", Name: "WF", Nodes: []map[string]interface{}{node("9")}}}
+
+	diff, err := CompareInventories(from, to)
+	if err != nil {
+		t.Fatalf("CompareInventories: %v", err)
+	}
+	if diff.Unchanged != 1 || diff.HasDrift() {
+		t.Errorf("diff = %+v, want the workflow unchanged", diff)
+	}
+}
@@ -0,0 +1,83 @@
+package workflow
+
+import (
+	"sort"
+)
+
+// Connection is a single edge between two nodes of a workflow
+type Connection struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	// Type is the connection type, e.g. "main" or "ai_tool"
+	Type string `json:"type"`
+	// Output is the index of the source node's output branch
+	Output int `json:"output"`
+	// Input is the index of the target node's input
+	Input int `json:"input"`
+}
+
+// ParseConnections flattens a workflow's connections map into a list of
+// edges. The map has the shape:
+//
+//	connections[sourceName][type][outputIndex] -> [{node, type, index}]
+//
+// Edges are returned sorted by source, type, and output so the result is
+// stable across runs.
+func ParseConnections(connections map[string]interface{}) []Connection {
+	var edges []Connection
+
+	sources := make([]string, 0, len(connections))
+	for source := range connections {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		byType, ok := connections[source].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		types := make([]string, 0, len(byType))
+		for t := range byType {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+
+		for _, connType := range types {
+			outputs, ok := byType[connType].([]interface{})
+			if !ok {
+				continue
+			}
+			for outputIndex, output := range outputs {
+				targets, ok := output.([]interface{})
+				if !ok {
+					continue
+				}
+				for _, t := range targets {
+					target, ok := t.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					name, _ := target["node"].(string)
+					if name == "" {
+						continue
+					}
+					inputIndex := 0
+					if idx, ok := target["index"].(float64); ok {
+						inputIndex = int(idx)
+					}
+					edges = append(edges, Connection{
+						Source: source,
+						Target: name,
+						Type:   connType,
+						Output: outputIndex,
+						Input:  inputIndex,
+					})
+				}
+			}
+		}
+	}
+
+	return edges
+}
@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+)
+
+// CopyEntry is the outcome of copying one workflow to another instance
+type CopyEntry struct {
+	Name     string `json:"name"`
+	SourceID string `json:"sourceId"`
+	TargetID string `json:"targetId,omitempty"`
+	Action   Action `json:"action"`
+	// Diff compares the copy with the target's previous version. Created
+	// workflows count all their nodes as added.
+	Diff *NodeDiffStat `json:"diff,omitempty"`
+}
+
+// Copy creates or updates pulled workflows on the pusher's instance,
+// sub-workflows first. IDs differ between instances, so each workflow is
+// matched to the target by name: without a match it is created, a single
+// match is updated unless unchanged (or with Force), and several matches
+// stop the copy. References to sub-workflows are rewritten to their IDs
+// on the target, credential IDs according to CredentialMapping.
+//
+// Updated workflows keep the target's staticData. Created ones start
+// without any, since the source's trigger state doesn't apply there.
+//
+// Workflows calling each other in a cycle have no order in which each
+// sub-workflow exists before its callers, so they stop the copy before
+// anything is written. Credentials the mapping doesn't cover are
+// reported, as they keep their source IDs.
+func (p *Pusher) Copy(pulled *PullResult) ([]CopyEntry, error) {
+	order := pulled.Manifest.GetPushOrder()
+	if len(order) < len(pulled.Workflows) {
+		ordered := make(map[string]bool, len(order))
+		for _, id := range order {
+			ordered[id] = true
+		}
+		var cyclic []string
+		for id, wf := range pulled.Workflows {
+			if !ordered[id] {
+				cyclic = append(cyclic, fmt.Sprintf("%s (%s)", wf.Name, id))
+			}
+		}
+		sort.Strings(cyclic)
+		return nil, fmt.Errorf("workflows calling each other in a cycle can't be copied in order: %s", strings.Join(cyclic, ", "))
+	}
+
+	for _, id := range order {
+		if wf, ok := pulled.Workflows[id]; ok {
+			for _, cred := range UnmappedCredentials(wf, p.CredentialMapping) {
+				logging.Warn(fmt.Sprintf("workflow %s uses credential %s, which the credential map doesn't cover; it keeps its source ID on the target", wf.Name, cred),
+					"workflow", id, "name", wf.Name, "credential", cred)
+			}
+		}
+	}
+
+	var entries []CopyEntry
+	for _, id := range order {
+		wf, ok := pulled.Workflows[id]
+		if !ok {
+			continue
+		}
+		entry, err := p.copyWorkflow(id, wf)
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// copyWorkflow creates or updates the target's copy of the source
+// workflow with ID sourceID
+func (p *Pusher) copyWorkflow(sourceID string, wf *api.Workflow) (CopyEntry, error) {
+	entry := CopyEntry{Name: wf.Name, SourceID: sourceID}
+
+	matches, err := FindByName(p.client, wf.Name)
+	if err != nil {
+		return entry, fmt.Errorf("failed to look up workflow %q on the target: %w", wf.Name, err)
+	}
+	if len(matches) > 1 {
+		ids := make([]string, len(matches))
+		for i, match := range matches {
+			ids[i] = match.ID
+		}
+		return entry, fmt.Errorf("several workflows on the target are named %q (IDs: %s)", wf.Name, strings.Join(ids, ", "))
+	}
+
+	p.updateSubWorkflowReferences(wf)
+	if len(p.CredentialMapping) > 0 {
+		RewriteCredentialReferences(wf, p.CredentialMapping)
+	}
+
+	if len(matches) == 0 {
+		wf.ID = ""
+		StaticDataReset.Apply(wf, nil)
+		created, err := p.client.CreateWorkflow(wf)
+		if err != nil {
+			return entry, fmt.Errorf("failed to create workflow %s: %w", wf.Name, err)
+		}
+		p.idMapping[sourceID] = created.ID
+		entry.TargetID = created.ID
+		entry.Action = ActionCreated
+		entry.Diff = &NodeDiffStat{Added: len(wf.Nodes)}
+		logging.Info(fmt.Sprintf("Created: %s (ID: %s)", created.Name, created.ID),
+			"action", ActionCreated, "workflow", created.ID, "name", created.Name, "sourceId", sourceID)
+		return entry, nil
+	}
+
+	// The list endpoint may leave out fields, so fetch the full copy
+	remote, err := p.client.GetWorkflow(matches[0].ID)
+	if err != nil {
+		return entry, fmt.Errorf("failed to get workflow %s from the target: %w", wf.Name, err)
+	}
+	p.idMapping[sourceID] = remote.ID
+	entry.TargetID = remote.ID
+	wf.ID = remote.ID
+	StaticDataPreserve.Apply(wf, remote)
+
+	if !p.Force {
+		unchanged, _, err := CompareWith(wf, remote, p.NodeFilter)
+		if err != nil {
+			return entry, fmt.Errorf("failed to compare workflow %s: %w", wf.Name, err)
+		}
+		if unchanged {
+			entry.Action = ActionUnchanged
+			entry.Diff = &NodeDiffStat{}
+			logging.Info(fmt.Sprintf("Unchanged: %s (ID: %s)", wf.Name, remote.ID),
+				"action", ActionUnchanged, "workflow", remote.ID, "name", wf.Name, "sourceId", sourceID)
+			return entry, nil
+		}
+	}
+
+	diff := DiffNodes(remote, wf, p.NodeFilter)
+	if _, err := p.client.UpdateWorkflow(remote.ID, wf); err != nil {
+		return entry, fmt.Errorf("failed to update workflow %s: %w", wf.Name, err)
+	}
+	entry.Action = ActionUpdated
+	entry.Diff = &diff
+	logging.Info(fmt.Sprintf("Updated: %s (ID: %s)", wf.Name, remote.ID),
+		"action", ActionUpdated, "workflow", remote.ID, "name", wf.Name, "sourceId", sourceID)
+	return entry, nil
+}
@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
+)
+
+func credentialNode(id, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name": "HTTP " + id,
+		"type": "n8n-nodes-base.httpRequest",
+		"credentials": map[string]interface{}{
+			"httpBasicAuth": map[string]interface{}{"id": id, "name": name},
+		},
+	}
+}
+
+func TestCopyRejectsCycles(t *testing.T) {
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+
+	pulled := &PullResult{
+		Workflows: map[string]*api.Workflow{
+			"a": {ID: "a", Name: "A"},
+			"b": {ID: "b", Name: "B"},
+			"c": {ID: "c", Name: "C"},
+		},
+		Manifest: &Manifest{
+			Workflows:    map[string]WorkflowMeta{"a": {Name: "A"}, "b": {Name: "B"}, "c": {Name: "C"}},
+			Dependencies: map[string][]string{"a": {"b"}, "b": {"a"}},
+		},
+	}
+
+	entries, err := NewPusher(api.NewClient(srv.URL, "key"), "").Copy(pulled)
+	if err == nil {
+		t.Fatal("expected an error for the cycle")
+	}
+	for _, want := range []string{"A (a)", "B (b)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q doesn't name %s", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "C (c)") {
+		t.Errorf("error %q names C, which is not in the cycle", err)
+	}
+	if len(entries) != 0 || len(srv.Requests()) != 0 {
+		t.Errorf("copied %d workflow(s) with %d request(s), want nothing", len(entries), len(srv.Requests()))
+	}
+}
+
+func TestUnmappedCredentials(t *testing.T) {
+	wf := &api.Workflow{Nodes: []map[string]interface{}{
+		credentialNode("1", "Prod DB"),
+		credentialNode("2", "Mail"),
+		credentialNode("1", "Prod DB"),
+		{"name": "No credentials"},
+	}}
+
+	tests := []struct {
+		name    string
+		mapping map[string]string
+		want    string
+	}{
+		{name: "no mapping", want: "Mail (2),Prod DB (1)"},
+		{name: "partial mapping", mapping: map[string]string{"1": "10"}, want: "Mail (2)"},
+		{name: "full mapping", mapping: map[string]string{"1": "10", "2": "20"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(UnmappedCredentials(wf, tt.mapping), ","); got != tt.want {
+				t.Errorf("UnmappedCredentials() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyWarnsAboutUnmappedCredentials(t *testing.T) {
+	var logs bytes.Buffer
+	if err := logging.Setup(&logs, logging.Text, "info"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = logging.Setup(&bytes.Buffer{}, logging.Text, "info") })
+
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Handle(http.MethodGet, "/workflows", apitest.Response{Body: map[string]interface{}{"data": []interface{}{}}})
+	srv.Handle(http.MethodPost, "/workflows", apitest.Response{Body: map[string]string{"id": "new", "name": "A"}})
+
+	pulled := &PullResult{
+		Workflows: map[string]*api.Workflow{
+			"a": {ID: "a", Name: "A", Nodes: []map[string]interface{}{credentialNode("1", "Prod DB"), credentialNode("2", "Mail")}},
+		},
+		Manifest: &Manifest{Workflows: map[string]WorkflowMeta{"a": {Name: "A"}}},
+	}
+	pusher := NewPusher(api.NewClient(srv.URL, "key"), "")
+	pusher.CredentialMapping = map[string]string{"1": "10"}
+	entries, err := pusher.Copy(pulled)
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != ActionCreated {
+		t.Fatalf("entries = %+v, want one created", entries)
+	}
+	if !strings.Contains(logs.String(), "Warning: workflow A uses credential Mail (2)") {
+		t.Errorf("log %q lacks the unmapped credential warning", logs.String())
+	}
+	if strings.Contains(logs.String(), "Prod DB") {
+		t.Errorf("log %q warns about a mapped credential", logs.String())
+	}
+}
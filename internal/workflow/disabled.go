@@ -0,0 +1,30 @@
+package workflow
+
+import (
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// DisabledNode describes a node that is switched off in the editor
+type DisabledNode struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Trigger bool   `json:"trigger,omitempty"`
+}
+
+// DisabledNodes returns the nodes of wf that have "disabled": true, in
+// workflow order
+func DisabledNodes(wf *api.Workflow) []DisabledNode {
+	var disabled []DisabledNode
+	for _, node := range wf.Nodes {
+		if off, _ := node["disabled"].(bool); !off {
+			continue
+		}
+		nodeType, _ := node["type"].(string)
+		disabled = append(disabled, DisabledNode{
+			Name:    nodeName(node),
+			Type:    nodeType,
+			Trigger: IsTriggerType(nodeType),
+		})
+	}
+	return disabled
+}
@@ -0,0 +1,80 @@
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// DefaultFilenameTemplate names files after the workflow, e.g. My_Workflow.json
+const DefaultFilenameTemplate = "{{.Name}}"
+
+var slugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// FilenameTemplate renders the file name a pulled workflow is written to
+type FilenameTemplate struct {
+	tmpl *template.Template
+}
+
+// filenameData is the data available to filename templates
+type filenameData struct {
+	ID   string
+	Name string
+	Slug string
+}
+
+// ParseFilenameTemplate parses a Go text/template using the fields .ID,
+// .Name, and .Slug. The template is test-rendered so that unknown fields
+// and templates producing empty names are reported up front.
+func ParseFilenameTemplate(text string) (*FilenameTemplate, error) {
+	tmpl, err := template.New("filename").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filename template: %w", err)
+	}
+
+	t := &FilenameTemplate{tmpl: tmpl}
+	rendered, err := t.render(&api.Workflow{ID: "abc123", Name: "Example Workflow"})
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(rendered, ".json") == "" {
+		return nil, fmt.Errorf("invalid filename template %q: produces an empty filename", text)
+	}
+
+	return t, nil
+}
+
+// Filename returns the sanitized file name for a workflow, always ending
+// in .json
+func (t *FilenameTemplate) Filename(wf *api.Workflow) (string, error) {
+	if t == nil {
+		return SanitizeFilename(wf.Name) + ".json", nil
+	}
+	rendered, err := t.render(wf)
+	if err != nil {
+		return "", err
+	}
+	return SanitizeFilename(strings.TrimSuffix(rendered, ".json")) + ".json", nil
+}
+
+func (t *FilenameTemplate) render(wf *api.Workflow) (string, error) {
+	var b strings.Builder
+	data := filenameData{ID: wf.ID, Name: wf.Name, Slug: Slugify(wf.Name)}
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render filename template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Slugify converts a workflow name to a lowercase, dash-separated slug
+func Slugify(name string) string {
+	slug := slugUnsafe.ReplaceAllString(strings.ToLower(name), "-")
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		slug = "workflow"
+	}
+	return slug
+}
@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ContentHash returns the hex encoded SHA-256 of a workflow file's content
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// FileStatus is the result of checking one workflow file against the
+// hash recorded in the manifest
+type FileStatus string
+
+const (
+	FileOK       FileStatus = "ok"
+	FileModified FileStatus = "modified"
+	FileMissing  FileStatus = "missing"
+	FileNoHash   FileStatus = "no hash"
+)
+
+// FileCheck reports the state of one workflow file
+type FileCheck struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name"`
+	Filename string     `json:"filename"`
+	Status   FileStatus `json:"status"`
+}
+
+// VerifyFiles recomputes the hash of every workflow file listed in the
+// manifest and compares it with the recorded one. Results are sorted by
+// file name.
+func (m *Manifest) VerifyFiles(dir string) []FileCheck {
+	checks := make([]FileCheck, 0, len(m.Workflows))
+	for id, meta := range m.Workflows {
+		check := FileCheck{ID: id, Name: meta.Name, Filename: meta.Filename}
+		data, err := os.ReadFile(filepath.Join(dir, meta.Filename))
+		switch {
+		case err != nil:
+			check.Status = FileMissing
+		case meta.SHA256 == "":
+			check.Status = FileNoHash
+		case ContentHash(data) != meta.SHA256:
+			check.Status = FileModified
+		default:
+			check.Status = FileOK
+		}
+		checks = append(checks, check)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Filename < checks[j].Filename })
+	return checks
+}
+
+// hashSet returns the canonical text the signature is computed over: one
+// "<id> <sha256>" line per workflow, sorted by ID
+func (m *Manifest) hashSet() []byte {
+	lines := make([]string, 0, len(m.Workflows))
+	for id, meta := range m.Workflows {
+		lines = append(lines, id+" "+meta.SHA256)
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// Sign records an HMAC-SHA256 of the manifest's hash set, keyed with key
+func (m *Manifest) Sign(key []byte) {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(m.hashSet())
+	m.Signature = hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the manifest's signature against key
+func (m *Manifest) VerifySignature(key []byte) error {
+	if m.Signature == "" {
+		return fmt.Errorf("manifest is not signed")
+	}
+	signature, err := hex.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("manifest signature is malformed")
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(m.hashSet())
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return fmt.Errorf("manifest signature does not match")
+	}
+	return nil
+}
+
+// ReadSigningKey reads an HMAC key from a file, ignoring surrounding
+// whitespace
+func ReadSigningKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	key := []byte(strings.TrimSpace(string(data)))
+	if len(key) == 0 {
+		return nil, fmt.Errorf("signing key file %s is empty", path)
+	}
+	return key, nil
+}
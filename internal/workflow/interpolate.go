@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Interpolator expands ${NAME} placeholders in workflow files before they
+// are parsed. Values come from Vars first, then from the process
+// environment. "$$" is an escape for a literal "$".
+type Interpolator struct {
+	// Vars take precedence over environment variables
+	Vars map[string]string
+	// AllowUnset leaves unresolved placeholders as they are instead of
+	// failing
+	AllowUnset bool
+}
+
+// ParseVars parses key=value pairs as given to --var
+func ParseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || !isVarName(key) {
+			return nil, fmt.Errorf("invalid variable %q (expected NAME=value)", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// Expand returns data with all placeholders replaced. Values are escaped
+// for use inside JSON strings, which is where placeholders are expected.
+func (in *Interpolator) Expand(data []byte) ([]byte, error) {
+	var out strings.Builder
+	out.Grow(len(data))
+	src := string(data)
+	unresolved := map[string]bool{}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if c != '$' || i+1 >= len(src) {
+			out.WriteByte(c)
+			continue
+		}
+
+		switch src[i+1] {
+		case '$':
+			out.WriteByte('$')
+			i++
+			continue
+		case '{':
+		default:
+			out.WriteByte(c)
+			continue
+		}
+
+		end := strings.IndexByte(src[i+2:], '}')
+		if end < 0 || !isVarName(src[i+2:i+2+end]) {
+			out.WriteByte(c)
+			continue
+		}
+		name := src[i+2 : i+2+end]
+		placeholder := src[i : i+3+end]
+		i += 2 + end
+
+		value, ok := in.lookup(name)
+		if !ok {
+			unresolved[name] = true
+			out.WriteString(placeholder)
+			continue
+		}
+		out.WriteString(jsonEscape(value))
+	}
+
+	if len(unresolved) > 0 && !in.AllowUnset {
+		names := make([]string, 0, len(unresolved))
+		for name := range unresolved {
+			names = append(names, "${"+name+"}")
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unresolved placeholder(s): %s (set them in the environment or with --var, or use --allow-unset)", strings.Join(names, ", "))
+	}
+
+	return []byte(out.String()), nil
+}
+
+func (in *Interpolator) lookup(name string) (string, bool) {
+	if value, ok := in.Vars[name]; ok {
+		return value, true
+	}
+	return os.LookupEnv(name)
+}
+
+// isVarName reports whether s is a valid placeholder name: a letter or
+// underscore followed by letters, digits, or underscores
+func isVarName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case i > 0 && r >= '0' && r <= '9':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// jsonEscape escapes s for insertion between the quotes of a JSON string
+func jsonEscape(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data[1 : len(data)-1])
+}
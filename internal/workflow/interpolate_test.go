@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolatorExpand(t *testing.T) {
+	t.Setenv("N8NCTL_TEST_HOST", "env.example.com")
+	t.Setenv("N8NCTL_TEST_SHADOWED", "from-env")
+
+	vars := map[string]string{
+		"TOKEN":                "abc123",
+		"N8NCTL_TEST_SHADOWED": "from-var",
+		"QUOTED":               `say "hi"` + "\n",
+		"EMPTY":                "",
+	}
+
+	tests := []struct {
+		name       string
+		in         string
+		allowUnset bool
+		want       string
+		wantErr    string
+	}{
+		{name: "var", in: `{"token":"${TOKEN}"}`, want: `{"token":"abc123"}`},
+		{name: "environment", in: `{"url":"https://${N8NCTL_TEST_HOST}/hook"}`, want: `{"url":"https://env.example.com/hook"}`},
+		{name: "vars before environment", in: `"${N8NCTL_TEST_SHADOWED}"`, want: `"from-var"`},
+		{name: "escaped for JSON", in: `"${QUOTED}"`, want: `"say \"hi\"\n"`},
+		{name: "empty value", in: `"${EMPTY}"`, want: `""`},
+		{name: "dollar escape", in: `"$${TOKEN}"`, want: `"${TOKEN}"`},
+		{name: "double dollar", in: `"price: $$5"`, want: `"price: $5"`},
+		{name: "n8n expression untouched", in: `"={{ $json.id }}"`, want: `"={{ $json.id }}"`},
+		{name: "trailing dollar", in: `"cost$"`, want: `"cost$"`},
+		{name: "unclosed placeholder", in: `"${TOKEN"`, want: `"${TOKEN"`},
+		{name: "invalid name", in: `"${1ABC}"`, want: `"${1ABC}"`},
+		{
+			name:    "unresolved",
+			in:      `{"a":"${N8NCTL_TEST_MISSING_B}","b":"${N8NCTL_TEST_MISSING_A}","c":"${TOKEN}"}`,
+			wantErr: "unresolved placeholder(s): ${N8NCTL_TEST_MISSING_A}, ${N8NCTL_TEST_MISSING_B}",
+		},
+		{
+			name:       "unresolved allowed",
+			in:         `{"a":"${N8NCTL_TEST_MISSING_A}","c":"${TOKEN}"}`,
+			allowUnset: true,
+			want:       `{"a":"${N8NCTL_TEST_MISSING_A}","c":"abc123"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &Interpolator{Vars: vars, AllowUnset: tt.allowUnset}
+			got, err := in.Expand([]byte(tt.in))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expand() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Expand() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVars(t *testing.T) {
+	vars, err := ParseVars([]string{"HOST=example.com", "QUERY=a=b", "EMPTY="})
+	if err != nil {
+		t.Fatalf("ParseVars() error = %v", err)
+	}
+	want := map[string]string{"HOST": "example.com", "QUERY": "a=b", "EMPTY": ""}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s = %q, want %q", k, vars[k], v)
+		}
+	}
+
+	for _, bad := range []string{"NOVALUE", "=value", "1ABC=x", "with-dash=x"} {
+		if _, err := ParseVars([]string{bad}); err == nil {
+			t.Errorf("ParseVars(%q) succeeded, want an error", bad)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package workflow
+
+import (
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// Layout spacing, roughly matching the n8n editor's default node grid
+const (
+	layoutOriginX = 250
+	layoutOriginY = 300
+	layoutStepX   = 220
+	layoutStepY   = 160
+)
+
+// AutoLayout assigns grid positions to all nodes of a workflow based on
+// its connection graph. Each node is placed one column to the right of the
+// furthest node feeding into it; nodes in the same column are stacked
+// vertically in their original order. Nodes without connections end up in
+// the first column. Cycles are tolerated. Connections can't tell nodes
+// with an empty or duplicate name apart, so those are laid out as if
+// unconnected; nil nodes are skipped.
+func AutoLayout(wf *api.Workflow) {
+	// index maps unambiguous node names to their position in wf.Nodes
+	index := make(map[string]int, len(wf.Nodes))
+	ambiguous := make(map[string]bool)
+	for i, node := range wf.Nodes {
+		if node == nil {
+			continue
+		}
+		name := nodeName(node)
+		if name == "" {
+			continue
+		}
+		if _, ok := index[name]; ok {
+			ambiguous[name] = true
+			continue
+		}
+		index[name] = i
+	}
+	for name := range ambiguous {
+		delete(index, name)
+	}
+
+	successors := make(map[int][]int)
+	for _, edge := range ParseConnections(wf.Connections) {
+		source, ok := index[edge.Source]
+		if !ok {
+			continue
+		}
+		target, ok := index[edge.Target]
+		if !ok {
+			continue
+		}
+		successors[source] = append(successors[source], target)
+	}
+
+	// Longest-path column assignment. A node's column only ever increases
+	// and is bounded by the node count, which also terminates cycles.
+	n := len(wf.Nodes)
+	column := make([]int, n)
+	for changed, rounds := true, 0; changed && rounds < n; rounds++ {
+		changed = false
+		for source := range wf.Nodes {
+			for _, target := range successors[source] {
+				if column[target] < column[source]+1 && column[source]+1 < n {
+					column[target] = column[source] + 1
+					changed = true
+				}
+			}
+		}
+	}
+
+	rows := make(map[int]int)
+	for i, node := range wf.Nodes {
+		if node == nil {
+			continue
+		}
+		col := column[i]
+		row := rows[col]
+		rows[col]++
+		node["position"] = []interface{}{
+			float64(layoutOriginX + col*layoutStepX),
+			float64(layoutOriginY + row*layoutStepY),
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package workflow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// layoutWorkflow builds a workflow with the named nodes and main
+// connections given as source -> targets
+func layoutWorkflow(names []string, edges map[string][]string) *api.Workflow {
+	wf := &api.Workflow{Connections: map[string]interface{}{}}
+	for _, name := range names {
+		wf.Nodes = append(wf.Nodes, map[string]interface{}{"name": name})
+	}
+	for source, targets := range edges {
+		var outputs []interface{}
+		for _, target := range targets {
+			outputs = append(outputs, map[string]interface{}{"node": target, "type": "main", "index": float64(0)})
+		}
+		wf.Connections[source] = map[string]interface{}{"main": []interface{}{outputs}}
+	}
+	return wf
+}
+
+// gridOf returns the [column, row] AutoLayout assigned to each node, or
+// nil for nodes without a position
+func gridOf(wf *api.Workflow) [][2]int {
+	grid := make([][2]int, 0, len(wf.Nodes))
+	for _, node := range wf.Nodes {
+		pos, _ := node["position"].([]interface{})
+		if len(pos) != 2 {
+			grid = append(grid, [2]int{-1, -1})
+			continue
+		}
+		x, y := pos[0].(float64), pos[1].(float64)
+		grid = append(grid, [2]int{(int(x) - layoutOriginX) / layoutStepX, (int(y) - layoutOriginY) / layoutStepY})
+	}
+	return grid
+}
+
+func TestAutoLayout(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []string
+		edges map[string][]string
+		want  [][2]int
+	}{
+		{
+			name:  "chain",
+			nodes: []string{"A", "B", "C"},
+			edges: map[string][]string{"A": {"B"}, "B": {"C"}},
+			want:  [][2]int{{0, 0}, {1, 0}, {2, 0}},
+		},
+		{
+			name:  "fan-in takes the longest path",
+			nodes: []string{"A", "B", "C", "D"},
+			edges: map[string][]string{"A": {"B", "D"}, "B": {"C"}, "C": {"D"}},
+			want:  [][2]int{{0, 0}, {1, 0}, {2, 0}, {3, 0}},
+		},
+		{
+			name:  "fan-out stacks rows",
+			nodes: []string{"A", "B", "C"},
+			edges: map[string][]string{"A": {"B", "C"}},
+			want:  [][2]int{{0, 0}, {1, 0}, {1, 1}},
+		},
+		{
+			name:  "cycle terminates",
+			nodes: []string{"A", "B"},
+			edges: map[string][]string{"A": {"B"}, "B": {"A"}},
+			want:  [][2]int{{0, 0}, {1, 0}},
+		},
+		{
+			name:  "disconnected nodes share the first column",
+			nodes: []string{"A", "B", "Note"},
+			edges: map[string][]string{"A": {"B"}},
+			want:  [][2]int{{0, 0}, {1, 0}, {0, 1}},
+		},
+		{
+			name:  "unknown targets are ignored",
+			nodes: []string{"A"},
+			edges: map[string][]string{"A": {"Gone"}, "Gone": {"A"}},
+			want:  [][2]int{{0, 0}},
+		},
+		{
+			name:  "duplicate names are unconnected",
+			nodes: []string{"A", "B", "B", "C"},
+			edges: map[string][]string{"A": {"B"}, "B": {"C"}},
+			want:  [][2]int{{0, 0}, {0, 1}, {0, 2}, {0, 3}},
+		},
+		{
+			name:  "empty names are unconnected",
+			nodes: []string{"A", "", ""},
+			edges: map[string][]string{"A": {""}},
+			want:  [][2]int{{0, 0}, {0, 1}, {0, 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := layoutWorkflow(tt.nodes, tt.edges)
+			AutoLayout(wf)
+			if got := gridOf(wf); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("grid = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoLayoutSkipsNilNodes(t *testing.T) {
+	wf := layoutWorkflow([]string{"A", "B"}, map[string][]string{"A": {"B"}})
+	wf.Nodes = append([]map[string]interface{}{nil}, wf.Nodes...)
+
+	AutoLayout(wf)
+
+	if wf.Nodes[0] != nil {
+		t.Errorf("nil node became %v", wf.Nodes[0])
+	}
+	if got, want := gridOf(&api.Workflow{Nodes: wf.Nodes[1:]}), [][2]int{{0, 0}, {1, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("grid = %v, want %v", got, want)
+	}
+}
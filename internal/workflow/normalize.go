@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// Normalize returns a canonical copy of the parts of a workflow that are
+// sent to n8n on push. Server-managed metadata (ID, active state, tags,
+// sharing, timestamps) is dropped and nodes are sorted by name so that two
+// workflows can be compared independently of where they came from.
+func Normalize(wf *api.Workflow) *api.WorkflowUpdateRequest {
+	nodes := make([]map[string]interface{}, len(wf.Nodes))
+	copy(nodes, wf.Nodes)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodeName(nodes[i]) < nodeName(nodes[j])
+	})
+
+	connections := wf.Connections
+	if connections == nil {
+		connections = map[string]interface{}{}
+	}
+
+	settings := wf.Settings
+	if len(settings) == 0 {
+		settings = nil
+	}
+
+	// Cleared staticData is sent as {}, but may come back as null
+	staticData := wf.StaticData
+	if m, ok := staticData.(map[string]interface{}); ok && len(m) == 0 {
+		staticData = nil
+	}
+
+	return &api.WorkflowUpdateRequest{
+		Name:        wf.Name,
+		Nodes:       nodes,
+		Connections: connections,
+		Settings:    settings,
+		StaticData:  staticData,
+	}
+}
+
+// NodeFilter selects nodes by type before workflows are normalized and
+// compared, e.g. to ignore sticky notes that were only moved around
+type NodeFilter struct {
+	// Only keeps nodes of these types (empty = all types)
+	Only []string
+	// Exclude drops nodes of these types
+	Exclude []string
+}
+
+// IsEmpty reports whether the filter keeps every node
+func (f NodeFilter) IsEmpty() bool {
+	return len(f.Only) == 0 && len(f.Exclude) == 0
+}
+
+func (f NodeFilter) keeps(node map[string]interface{}) bool {
+	nodeType, _ := node["type"].(string)
+	for _, t := range f.Exclude {
+		if t == nodeType {
+			return false
+		}
+	}
+	if len(f.Only) == 0 {
+		return true
+	}
+	for _, t := range f.Only {
+		if t == nodeType {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns a copy of wf without the nodes rejected by the filter, along
+// with the number of nodes removed. Connections from or to removed nodes are
+// dropped as well. wf itself is not modified.
+func (f NodeFilter) Apply(wf *api.Workflow) (*api.Workflow, int) {
+	if f.IsEmpty() {
+		return wf, 0
+	}
+
+	filtered := *wf
+	filtered.Nodes = make([]map[string]interface{}, 0, len(wf.Nodes))
+	removed := make(map[string]bool)
+	for _, node := range wf.Nodes {
+		if f.keeps(node) {
+			filtered.Nodes = append(filtered.Nodes, node)
+		} else {
+			removed[nodeName(node)] = true
+		}
+	}
+	if len(removed) == 0 {
+		return wf, 0
+	}
+
+	filtered.Connections = make(map[string]interface{}, len(wf.Connections))
+	for source, byType := range wf.Connections {
+		if removed[source] {
+			continue
+		}
+		filtered.Connections[source] = withoutTargets(byType, removed)
+	}
+
+	return &filtered, len(wf.Nodes) - len(filtered.Nodes)
+}
+
+// withoutTargets copies a node's connection map, leaving out edges that end
+// in one of the removed nodes
+func withoutTargets(byType interface{}, removed map[string]bool) interface{} {
+	types, ok := byType.(map[string]interface{})
+	if !ok {
+		return byType
+	}
+
+	result := make(map[string]interface{}, len(types))
+	for connType, outputs := range types {
+		outputList, ok := outputs.([]interface{})
+		if !ok {
+			result[connType] = outputs
+			continue
+		}
+		newOutputs := make([]interface{}, len(outputList))
+		for i, output := range outputList {
+			targets, ok := output.([]interface{})
+			if !ok {
+				newOutputs[i] = output
+				continue
+			}
+			kept := make([]interface{}, 0, len(targets))
+			for _, t := range targets {
+				if target, ok := t.(map[string]interface{}); ok {
+					if name, _ := target["node"].(string); removed[name] {
+						continue
+					}
+				}
+				kept = append(kept, t)
+			}
+			newOutputs[i] = kept
+		}
+		result[connType] = newOutputs
+	}
+	return result
+}
+
+// Hash returns a hex-encoded SHA-256 of the normalized workflow.
+// Map keys are serialized in sorted order, so equal workflows always
+// produce the same hash.
+func Hash(wf *api.Workflow) (string, error) {
+	data, err := json.Marshal(Normalize(wf))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal workflow: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Equal reports whether two workflows have the same normalized content.
+func Equal(a, b *api.Workflow) (bool, error) {
+	hashA, err := Hash(a)
+	if err != nil {
+		return false, err
+	}
+	hashB, err := Hash(b)
+	if err != nil {
+		return false, err
+	}
+	return hashA == hashB, nil
+}
+
+func nodeName(node map[string]interface{}) string {
+	name, _ := node["name"].(string)
+	return name
+}
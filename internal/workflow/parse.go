@@ -0,0 +1,158 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// maxEnvelopeDepth bounds how many wrapper levels ParseWorkflowJSON unwraps
+const maxEnvelopeDepth = 3
+
+// envelopeKeys are the top-level keys exports use to wrap a workflow
+var envelopeKeys = []string{"workflow", "data"}
+
+// MarshalFile encodes v the way workflow, manifest, and execution files
+// are written: indented JSON ending in a newline
+func MarshalFile(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// ParseWorkflowJSON decodes a workflow file. Besides a plain workflow object
+// it accepts the shapes produced by n8n exports and API responses: a
+// workflow wrapped under a "workflow" or "data" key, or a single-element
+// array.
+func ParseWorkflowJSON(data []byte) (*api.Workflow, error) {
+	// Check the syntax first, so that error offsets refer to data itself
+	var whole json.RawMessage
+	if err := json.Unmarshal(data, &whole); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow JSON: %w", JSONError(data, err))
+	}
+
+	raw, err := unwrapWorkflowJSON(data, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var wf api.Workflow
+	if err := json.Unmarshal(raw, &wf); err != nil {
+		// The offsets of type errors are relative to the unwrapped workflow
+		base := bytes.Index(data, raw)
+		if base < 0 {
+			base = 0
+		}
+		return nil, fmt.Errorf("failed to parse workflow JSON: %w", jsonErrorAt(data, base, err))
+	}
+	return &wf, nil
+}
+
+// snippetWidth is the most characters of a line JSONError shows
+const snippetWidth = 80
+
+// JSONError adds the line and column where decoding data failed to a
+// syntax or type error of encoding/json, along with the offending line
+// and a caret under the position:
+//
+//	line 12, column 15: invalid character '}' looking for beginning of object key string
+//	      "name": "x",}
+//	                  ^
+//
+// Other errors are returned unchanged.
+func JSONError(data []byte, err error) error {
+	return jsonErrorAt(data, 0, err)
+}
+
+// jsonErrorAt is JSONError for an error whose offset is relative to the
+// part of data starting at base
+func jsonErrorAt(data []byte, base int, err error) error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	// The decoder reports the offset after the byte it stopped at
+	pos := base + int(offset) - 1
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > len(data) {
+		pos = len(data)
+	}
+
+	start := bytes.LastIndexByte(data[:pos], '\n') + 1
+	end := len(data)
+	if i := bytes.IndexByte(data[pos:], '\n'); i >= 0 {
+		end = pos + i
+	}
+	line := bytes.Count(data[:pos], []byte{'\n'}) + 1
+	column := pos - start + 1
+
+	// Show a window around the position of long lines, e.g. minified JSON
+	from, to := start, end
+	if to-from > snippetWidth {
+		from = pos - snippetWidth/2
+		if from < start {
+			from = start
+		}
+		to = from + snippetWidth
+		if to > end {
+			to = end
+		}
+	}
+	snippet := strings.ReplaceAll(string(data[from:to]), "\t", " ")
+	caret := strings.Repeat(" ", pos-from) + "^"
+
+	return fmt.Errorf("line %d, column %d: %w\n  %s\n  %s", line, column, err, strings.TrimRight(snippet, "\r"), caret)
+}
+
+func unwrapWorkflowJSON(data []byte, depth int) (json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if depth > maxEnvelopeDepth {
+		return nil, unrecognizedWorkflowError()
+	}
+
+	if len(data) > 0 && data[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, fmt.Errorf("failed to parse workflow JSON: %w", err)
+		}
+		if len(items) != 1 {
+			return nil, fmt.Errorf("file contains an array of %d workflows; expected exactly one", len(items))
+		}
+		return unwrapWorkflowJSON(items[0], depth+1)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow JSON: %w", err)
+	}
+	if _, ok := fields["nodes"]; ok {
+		return data, nil
+	}
+	for _, key := range envelopeKeys {
+		if inner, ok := fields[key]; ok {
+			return unwrapWorkflowJSON(inner, depth+1)
+		}
+	}
+
+	return nil, unrecognizedWorkflowError()
+}
+
+func unrecognizedWorkflowError() error {
+	return fmt.Errorf("unrecognized workflow JSON: expected an object with a \"nodes\" array, optionally wrapped under \"workflow\" or \"data\" or in a single-element array")
+}
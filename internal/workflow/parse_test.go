@@ -0,0 +1,145 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJSONError(t *testing.T) {
+	// A minified file with a missing colon in the middle
+	prefix := `{"nodes":[` + strings.Repeat(`{"name":"node"},`, 10) + `{"name"`
+	long := prefix + ` "x"},` + strings.Repeat(`{"name":"node"},`, 10) + `{}]}`
+	pos := len(prefix) + 1
+
+	tests := []struct {
+		name string
+		data string
+		// into is decoded from data, to produce a type error
+		into interface{}
+		// position is the start of the message, snippet the line shown
+		// below it and the caret line
+		position string
+		snippet  string
+	}{
+		{
+			name:     "syntax error",
+			data:     "{\n  \"name\": \"x\",}\n",
+			position: "line 2, column 15: invalid character '}'",
+			snippet: "    \"name\": \"x\",}\n" +
+				"                ^",
+		},
+		{
+			name:     "first line",
+			data:     `{"name" "x"}`,
+			position: "line 1, column 9: invalid character '\"' after object key",
+			snippet: "  {\"name\" \"x\"}\n" +
+				"          ^",
+		},
+		{
+			name:     "type error",
+			data:     "{\n\t\"name\": 12\n}",
+			into:     &struct{ Name string }{},
+			position: "line 2, column 11: json: cannot unmarshal number",
+			snippet: "   \"name\": 12\n" +
+				"            ^",
+		},
+		{
+			name:     "long line shows a window",
+			data:     long,
+			position: fmt.Sprintf("line 1, column %d: invalid character", pos+1),
+			snippet: "  " + long[pos-snippetWidth/2:pos+snippetWidth/2] + "\n" +
+				"  " + strings.Repeat(" ", snippetWidth/2) + "^",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			into := tt.into
+			if into == nil {
+				into = new(interface{})
+			}
+			err := json.Unmarshal([]byte(tt.data), into)
+			if err == nil {
+				t.Fatal("test data decoded without an error")
+			}
+			got := JSONError([]byte(tt.data), err).Error()
+			if !strings.HasPrefix(got, tt.position) || !strings.HasSuffix(got, "\n"+tt.snippet) {
+				t.Errorf("JSONError() =\n%s\nwant it to start with %q and end with\n%s", got, tt.position, tt.snippet)
+			}
+			if !errors.Is(JSONError([]byte(tt.data), err), err) {
+				t.Error("JSONError() doesn't wrap the decoding error")
+			}
+		})
+	}
+}
+
+func TestJSONErrorOther(t *testing.T) {
+	err := errors.New("read failed")
+	if got := JSONError([]byte("{}"), err); got != err {
+		t.Errorf("JSONError() = %v, want the error unchanged", got)
+	}
+}
+
+func TestParseWorkflowJSONErrorPosition(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "syntax error",
+			data: "{\n  \"nodes\": [],\n  \"name\": \"x\"\n  \"active\": true\n}",
+			want: "line 4, column 3:",
+		},
+		{
+			name: "type error in a wrapped workflow",
+			data: "{\"workflow\": {\n  \"nodes\": [],\n  \"name\": 7\n}}",
+			want: "line 3, column 11:",
+		},
+		{
+			name: "type error in an array",
+			data: "[\n  {\"nodes\": {}}\n]",
+			want: "line 2, column 13:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWorkflowJSON([]byte(tt.data))
+			if err == nil {
+				t.Fatal("ParseWorkflowJSON() succeeded, want an error")
+			}
+			if !strings.HasPrefix(err.Error(), "failed to parse workflow JSON: "+tt.want) {
+				t.Errorf("error = %q, want position %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshalFile(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{name: "object", v: map[string]int{"a": 1}, want: "{\n  \"a\": 1\n}\n"},
+		{name: "array", v: []string{"x"}, want: "[\n  \"x\"\n]\n"},
+		{name: "empty object", v: struct{}{}, want: "{}\n"},
+		{name: "string with newline", v: "line\n", want: "\"line\\n\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := MarshalFile(tt.v)
+			if err != nil {
+				t.Fatalf("MarshalFile() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("MarshalFile() = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// FindNode returns the node with the given name
+func FindNode(wf *api.Workflow, name string) (map[string]interface{}, error) {
+	for _, node := range wf.Nodes {
+		if nodeName(node) == name {
+			return node, nil
+		}
+	}
+	return nil, fmt.Errorf("node %q not found in workflow %s", name, wf.Name)
+}
+
+// SetParameter sets a value below a node's parameters. The path is
+// dot-separated; numeric segments index into arrays, e.g.
+// "assignments.assignments.0.value". Every segment must already exist
+// unless create is set, in which case missing object keys are added.
+func SetParameter(node map[string]interface{}, path string, value interface{}, create bool) error {
+	if path == "" {
+		return fmt.Errorf("empty parameter path")
+	}
+
+	params, ok := node["parameters"].(map[string]interface{})
+	if !ok {
+		if !create {
+			return fmt.Errorf("node %q has no parameters", nodeName(node))
+		}
+		params = map[string]interface{}{}
+		node["parameters"] = params
+	}
+
+	segments := strings.Split(path, ".")
+	var current interface{} = params
+	for i, segment := range segments {
+		last := i == len(segments)-1
+		walked := strings.Join(segments[:i+1], ".")
+
+		switch container := current.(type) {
+		case map[string]interface{}:
+			next, exists := container[segment]
+			if !exists && !create {
+				return fmt.Errorf("parameter %q does not exist on node %q", walked, nodeName(node))
+			}
+			if last {
+				container[segment] = value
+				return nil
+			}
+			if !exists {
+				next = map[string]interface{}{}
+				container[segment] = next
+			}
+			current = next
+
+		case []interface{}:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(container) {
+				return fmt.Errorf("parameter %q: index %q out of range (length %d)", walked, segment, len(container))
+			}
+			if last {
+				container[index] = value
+				return nil
+			}
+			current = container[index]
+
+		default:
+			return fmt.Errorf("parameter %q is not an object or array", strings.Join(segments[:i], "."))
+		}
+	}
+
+	return nil
+}
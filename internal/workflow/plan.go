@@ -0,0 +1,146 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// PlanAction is what a push would do with a workflow
+type PlanAction string
+
+const (
+	PlanCreate    PlanAction = "create"
+	PlanUpdate    PlanAction = "update"
+	PlanUnchanged PlanAction = "unchanged"
+	// PlanConflict marks workflows whose name matches several workflows
+	// on the instance, so it's unclear which one they stand for
+	PlanConflict PlanAction = "conflict"
+)
+
+// Ways a planned workflow was matched to one on the instance
+const (
+	MatchedByID   = "id"
+	MatchedByName = "name"
+)
+
+// PlanEntry describes what pushing one workflow file would do
+type PlanEntry struct {
+	Name     string     `json:"name"`
+	Filename string     `json:"filename,omitempty"`
+	Action   PlanAction `json:"action"`
+	// ID is the ID in the file, RemoteID that of the matching workflow on
+	// the instance
+	ID        string `json:"id,omitempty"`
+	RemoteID  string `json:"remoteId,omitempty"`
+	MatchedBy string `json:"matchedBy,omitempty"`
+	// Diff compares the file's nodes with the remote version. Created
+	// workflows count all their nodes as added.
+	Diff *NodeDiffStat `json:"diff,omitempty"`
+	// Error explains a conflict
+	Error string `json:"error,omitempty"`
+}
+
+// Plan works out what pushing the manifest's workflows would do, without
+// changing anything. Each workflow is matched to the instance by its ID,
+// then by name; workflows matching neither would be created. Files are
+// read and prepared like for Push, so transforms, placeholders, version
+// limits, and the node filter apply; Force and create mode don't.
+func (p *Pusher) Plan(manifest *Manifest) ([]PlanEntry, error) {
+	staticData := p.StaticData
+	if staticData == StaticDataFile && contains(manifest.Stripped, "staticData") {
+		staticData = StaticDataPreserve
+	}
+
+	// readWorkflow records transforms and downgrades here; the plan only
+	// needs the workflows
+	result := &PushResult{handled: make(map[string]bool)}
+	var entries []PlanEntry
+	for _, id := range manifest.GetPushOrder() {
+		meta, exists := manifest.Workflows[id]
+		if !exists {
+			continue
+		}
+		wf, err := p.readWorkflow(meta, result)
+		if err != nil {
+			return entries, err
+		}
+		if len(p.CredentialMapping) > 0 {
+			RewriteCredentialReferences(wf, p.CredentialMapping)
+		}
+		if p.PrunePinData {
+			PrunePinData(wf)
+		}
+
+		entry, err := p.planWorkflow(wf, staticData)
+		if err != nil {
+			return entries, err
+		}
+		entry.Filename = meta.Filename
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// planWorkflow matches wf to the instance and compares it with the copy
+// found there
+func (p *Pusher) planWorkflow(wf *api.Workflow, staticData StaticDataMode) (PlanEntry, error) {
+	entry := PlanEntry{Name: wf.Name, ID: wf.ID}
+
+	var remote *api.Workflow
+	if wf.ID != "" {
+		found, err := p.client.GetWorkflow(wf.ID)
+		if err != nil && !api.IsNotFound(err) {
+			return entry, fmt.Errorf("failed to get workflow %s: %w", wf.Name, err)
+		}
+		if err == nil {
+			remote = found
+			entry.MatchedBy = MatchedByID
+		}
+	}
+	if remote == nil {
+		matches, err := FindByName(p.client, wf.Name)
+		if err != nil {
+			return entry, fmt.Errorf("failed to look up workflow %q: %w", wf.Name, err)
+		}
+		switch len(matches) {
+		case 0:
+			entry.Action = PlanCreate
+			entry.Diff = &NodeDiffStat{Added: len(wf.Nodes)}
+			return entry, nil
+		case 1:
+			// The list endpoint may leave out fields, so fetch the full copy
+			if remote, err = p.client.GetWorkflow(matches[0].ID); err != nil {
+				return entry, fmt.Errorf("failed to get workflow %s: %w", wf.Name, err)
+			}
+			entry.MatchedBy = MatchedByName
+		default:
+			ids := make([]string, len(matches))
+			for i, match := range matches {
+				ids[i] = match.ID
+			}
+			entry.Action = PlanConflict
+			entry.Error = fmt.Sprintf("several workflows are named %q (IDs: %s)", wf.Name, strings.Join(ids, ", "))
+			return entry, nil
+		}
+	}
+	entry.RemoteID = remote.ID
+
+	// Compare what would be sent, with the remote ID so a name match
+	// isn't reported as changed because of it
+	local := *wf
+	local.ID = remote.ID
+	staticData.Apply(&local, remote)
+	unchanged, _, err := CompareWith(&local, remote, p.NodeFilter)
+	if err != nil {
+		return entry, fmt.Errorf("failed to compare workflow %s: %w", wf.Name, err)
+	}
+	diff := DiffNodes(remote, &local, p.NodeFilter)
+	entry.Diff = &diff
+	entry.Action = PlanUpdate
+	if unchanged {
+		entry.Action = PlanUnchanged
+	}
+	return entry, nil
+}
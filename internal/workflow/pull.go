@@ -1,11 +1,22 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
 )
 
+// ErrFilenameConflict is returned when two pulled workflows map to the
+// same file name
+var ErrFilenameConflict = errors.New("filename conflict")
+
 // Manifest tracks workflow relationships for a pull/push operation
 type Manifest struct {
 	// RootWorkflow is the main workflow that was pulled
@@ -17,22 +28,99 @@ type Manifest struct {
 	// Dependencies maps workflow ID to IDs of sub-workflows it depends on
 	Dependencies map[string][]string `json:"dependencies"`
 
+	// Callers maps workflow ID to IDs of the workflows that call it. It is
+	// only recorded when pulling a workflow's parents.
+	Callers map[string][]string `json:"callers,omitempty"`
+
+	// Credentials lists every credential referenced by the pulled workflows
+	Credentials []CredentialRef `json:"credentials,omitempty"`
+
+	// Cycles lists sub-workflow call cycles found while pulling. Each entry
+	// is the chain of workflow IDs, starting and ending with the same ID.
+	Cycles [][]string `json:"cycles,omitempty"`
+
+	// Skipped lists sub-workflow references that were not followed
+	// because of the puller's FollowTypes or IgnoreIDs
+	Skipped []SkippedRef `json:"skipped,omitempty"`
+
+	// Stripped lists the top-level fields left out of the workflow files on
+	// purpose (see Strip)
+	Stripped []string `json:"stripped,omitempty"`
+
 	// Instance information
 	Instance string `json:"instance,omitempty"`
+
+	// Signature is an HMAC-SHA256 over the workflow file hashes, set when
+	// pulling with a signing key
+	Signature string `json:"signature,omitempty"`
+}
+
+// SkippedRef is a sub-workflow reference a recursive pull didn't follow
+type SkippedRef struct {
+	// Workflow is the ID of the referencing workflow
+	Workflow string `json:"workflow"`
+	Node     string `json:"node"`
+	NodeType string `json:"nodeType"`
+	// Target is the ID of the referenced workflow
+	Target string `json:"target"`
+	// Reason is "type" for node types not followed, "ignored" for IDs
+	// excluded explicitly
+	Reason string `json:"reason"`
 }
 
+// Reasons for skipping a sub-workflow reference
+const (
+	SkipReasonType    = "type"
+	SkipReasonIgnored = "ignored"
+)
+
 // WorkflowMeta contains metadata about a pulled workflow
 type WorkflowMeta struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	Filename string `json:"filename"`
 	Active   bool   `json:"active"`
+	// Credentials referenced by this workflow's nodes
+	Credentials []CredentialRef `json:"credentials,omitempty"`
+	// Executions lists saved execution snapshots, relative to the manifest
+	Executions []string `json:"executions,omitempty"`
+	// SHA256 is the hash of the workflow file as written by pull
+	SHA256 string `json:"sha256,omitempty"`
+	// Project that owns the workflow
+	ProjectID   string `json:"projectId,omitempty"`
+	ProjectName string `json:"projectName,omitempty"`
+	// UpdatedAt is the workflow's last change on the instance when it was
+	// pulled
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// OwnerProject returns the ID and name of the project owning wf, taken
+// from its sharing info. Both are empty if the API didn't include it.
+func OwnerProject(wf *api.Workflow) (id, name string) {
+	// n8n returns exactly one owner entry per workflow
+	if len(wf.Shared) == 0 {
+		return "", ""
+	}
+	s := wf.Shared[0]
+	if s.Project != nil {
+		return s.ProjectID, s.Project.Name
+	}
+	return s.ProjectID, ""
 }
 
 // PullResult contains the results of a recursive pull operation
 type PullResult struct {
 	Workflows map[string]*api.Workflow
 	Manifest  *Manifest
+	// Transformed counts the changes made by transforms
+	Transformed int
+	// Unchanged lists the workflows taken over from the previous manifest
+	// without downloading them. They are in the manifest but not in
+	// Workflows.
+	Unchanged []string
+	// Resumed lists the workflows taken over from an interrupted pull, see
+	// RecursivePuller.Resume. Like Unchanged, they are not in Workflows.
+	Resumed []string
 }
 
 // RecursivePuller handles recursive workflow pulling
@@ -40,6 +128,56 @@ type RecursivePuller struct {
 	client   *api.Client
 	pulled   map[string]*api.Workflow
 	manifest *Manifest
+	// stack holds the IDs currently being traversed, root first
+	stack []string
+
+	// Filenames names the workflow files recorded in the manifest
+	// (nil = DefaultFilenameTemplate)
+	Filenames *FilenameTemplate
+
+	// Transforms run on each workflow right after it is fetched
+	Transforms  []Transform
+	transformed int
+
+	// ByTag places each workflow file in a subdirectory named after its
+	// first tag, or UntaggedDir if it has none
+	ByTag bool
+
+	// FollowTypes limits the node types whose sub-workflow references are
+	// followed (empty = all)
+	FollowTypes []string
+	// IgnoreIDs are sub-workflows that are never followed, e.g. large
+	// shared libraries
+	IgnoreIDs []string
+	// NoFollow pulls only the requested workflow, without its
+	// sub-workflows, e.g. to write a manifest for a single workflow
+	NoFollow bool
+
+	// Previous is the manifest of an earlier pull into the same directory.
+	// PullAll doesn't download workflows that haven't been updated on the
+	// instance since then and keeps their manifest entries instead.
+	Previous *Manifest
+	// listed holds the workflows listed by PullAll, to check them against
+	// Previous before fetching them
+	listed    map[string]*api.Workflow
+	unchanged []string
+
+	// Resume is the partial manifest of an interrupted pull. Its workflows
+	// were already written and are taken over without fetching them.
+	Resume  *Manifest
+	resumed []string
+	// AfterPull is called with each fetched workflow once its manifest
+	// entry is complete, e.g. to write it out right away. An error stops
+	// the pull.
+	AfterPull func(wf *api.Workflow, manifest *Manifest) error
+}
+
+// UntaggedDir holds workflows without tags when pulling by tag
+const UntaggedDir = "untagged"
+
+// TagDir returns the subdirectory name used for a tag
+func TagDir(tag string) string {
+	return SanitizeFilename(tag)
 }
 
 // NewRecursivePuller creates a new recursive puller
@@ -63,51 +201,315 @@ func (p *RecursivePuller) Pull(workflowID string) (*PullResult, error) {
 	}
 
 	return &PullResult{
-		Workflows: p.pulled,
-		Manifest:  p.manifest,
+		Workflows:   p.pulled,
+		Manifest:    p.manifest,
+		Transformed: p.transformed,
+	}, nil
+}
+
+// PullAll pulls every workflow on the instance, together with the
+// sub-workflows they reference. The manifest has no root workflow.
+func (p *RecursivePuller) PullAll() (*PullResult, error) {
+	list, err := p.client.ListWorkflows(api.ListWorkflowsOptions{ExcludePinnedData: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows: %w", err)
+	}
+
+	ids := make([]string, len(list.Data))
+	p.listed = make(map[string]*api.Workflow, len(list.Data))
+	for i := range list.Data {
+		ids[i] = list.Data[i].ID
+		p.listed[ids[i]] = &list.Data[i]
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := p.pullRecursive(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return &PullResult{
+		Workflows:   p.pulled,
+		Manifest:    p.manifest,
+		Transformed: p.transformed,
+		Unchanged:   p.unchanged,
+		Resumed:     p.resumed,
+	}, nil
+}
+
+// reuse takes a listed workflow's entry over from the previous manifest
+// if the workflow hasn't been updated since and would be written to the
+// same file. It reports whether the entry was reused.
+func (p *RecursivePuller) reuse(listed *api.Workflow) (bool, error) {
+	if p.Previous == nil || listed.UpdatedAt == nil {
+		return false, nil
+	}
+	meta, ok := p.Previous.Workflows[listed.ID]
+	if !ok || meta.UpdatedAt == nil || !meta.UpdatedAt.Equal(*listed.UpdatedAt) {
+		return false, nil
+	}
+	filename, err := p.filename(listed)
+	if err != nil {
+		return false, err
+	}
+	if filename != meta.Filename {
+		return false, nil
+	}
+	if err := p.checkConflict(listed.ID, filename); err != nil {
+		return false, err
+	}
+
+	p.takeOver(meta, p.Previous)
+	p.unchanged = append(p.unchanged, listed.ID)
+	return true, nil
+}
+
+// takeOver copies a workflow's entry, dependencies, and skipped
+// references from another manifest
+func (p *RecursivePuller) takeOver(meta WorkflowMeta, from *Manifest) {
+	p.manifest.Workflows[meta.ID] = meta
+	p.manifest.Credentials = MergeCredentials(p.manifest.Credentials, meta.Credentials)
+	if deps := from.Dependencies[meta.ID]; len(deps) > 0 {
+		p.manifest.Dependencies[meta.ID] = deps
+	}
+	for _, ref := range from.Skipped {
+		if ref.Workflow == meta.ID {
+			p.manifest.Skipped = append(p.manifest.Skipped, ref)
+		}
+	}
+}
+
+// filename returns the manifest path of a workflow's file
+func (p *RecursivePuller) filename(wf *api.Workflow) (string, error) {
+	filename, err := p.Filenames.Filename(wf)
+	if err != nil {
+		return "", err
+	}
+	if p.ByTag {
+		dir := UntaggedDir
+		if len(wf.Tags) > 0 {
+			dir = TagDir(wf.Tags[0].Name)
+		}
+		filename = dir + "/" + filename
+	}
+	return filename, nil
+}
+
+// checkConflict fails if another workflow is already written to filename
+func (p *RecursivePuller) checkConflict(workflowID, filename string) error {
+	for id, meta := range p.manifest.Workflows {
+		if meta.Filename == filename {
+			return fmt.Errorf("%w: workflows %s and %s would both be written to %s; use a filename template that includes {{.ID}}", ErrFilenameConflict, id, workflowID, filename)
+		}
+	}
+	return nil
+}
+
+// PullParents pulls a workflow together with every workflow that calls
+// it through an Execute Workflow node, each with its own sub-workflows.
+// With transitive, callers of the callers are pulled as well, up to the
+// top of the call graph. The manifest records who calls whom in Callers.
+func (p *RecursivePuller) PullParents(workflowID string, transitive bool) (*PullResult, error) {
+	list, err := p.client.ListWorkflows(api.ListWorkflowsOptions{ExcludePinnedData: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows: %w", err)
+	}
+
+	callers := make(map[string][]string)
+	for _, wf := range list.Data {
+		for _, subID := range ExtractSubWorkflowIDs(wf.Nodes) {
+			callers[subID] = append(callers[subID], wf.ID)
+		}
+	}
+
+	p.manifest.RootWorkflow = workflowID
+	p.manifest.Callers = make(map[string][]string)
+	if err := p.pullRecursive(workflowID); err != nil {
+		return nil, err
+	}
+
+	queue := []string{workflowID}
+	visited := map[string]bool{workflowID: true}
+	for len(queue) > 0 {
+		target := queue[0]
+		queue = queue[1:]
+
+		ids := callers[target]
+		sort.Strings(ids)
+		if len(ids) > 0 {
+			p.manifest.Callers[target] = ids
+		}
+		for _, id := range ids {
+			if err := p.pullRecursive(id); err != nil {
+				return nil, err
+			}
+			if transitive && !visited[id] {
+				visited[id] = true
+				queue = append(queue, id)
+			}
+		}
+	}
+
+	return &PullResult{
+		Workflows:   p.pulled,
+		Manifest:    p.manifest,
+		Transformed: p.transformed,
 	}, nil
 }
 
 func (p *RecursivePuller) pullRecursive(workflowID string) error {
-	// Skip if already pulled
-	if _, exists := p.pulled[workflowID]; exists {
+	// A workflow that is still on the traversal stack calls itself,
+	// directly or through other sub-workflows
+	for i, id := range p.stack {
+		if id == workflowID {
+			cycle := append(append([]string{}, p.stack[i:]...), workflowID)
+			p.manifest.Cycles = append(p.manifest.Cycles, cycle)
+			return nil
+		}
+	}
+
+	// Skip if already pulled or reused from the previous manifest
+	if _, exists := p.manifest.Workflows[workflowID]; exists {
 		return nil
 	}
 
+	if p.Resume != nil {
+		if meta, ok := p.Resume.Workflows[workflowID]; ok {
+			if err := p.checkConflict(workflowID, meta.Filename); err != nil {
+				return err
+			}
+			p.takeOver(meta, p.Resume)
+			p.resumed = append(p.resumed, workflowID)
+			return nil
+		}
+	}
+
+	if listed, ok := p.listed[workflowID]; ok {
+		reused, err := p.reuse(listed)
+		if err != nil || reused {
+			return err
+		}
+	}
+
+	p.stack = append(p.stack, workflowID)
+	defer func() { p.stack = p.stack[:len(p.stack)-1] }()
+
 	// Fetch the workflow
 	wf, err := p.client.GetWorkflow(workflowID)
 	if err != nil {
 		return fmt.Errorf("failed to get workflow %s: %w", workflowID, err)
 	}
 
-	p.pulled[workflowID] = wf
+	changes, err := ApplyTransforms(p.Transforms, wf, StagePull)
+	if err != nil {
+		return err
+	}
+	p.transformed += changes
 
 	// Add to manifest
-	filename := SanitizeFilename(wf.Name) + ".json"
+	filename, err := p.filename(wf)
+	if err != nil {
+		return err
+	}
+	if err := p.checkConflict(workflowID, filename); err != nil {
+		return err
+	}
+
+	p.pulled[workflowID] = wf
+	credentials := ExtractCredentials(wf.Nodes)
+	projectID, projectName := OwnerProject(wf)
 	p.manifest.Workflows[workflowID] = WorkflowMeta{
-		ID:       wf.ID,
-		Name:     wf.Name,
-		Filename: filename,
-		Active:   wf.Active,
+		ID:          wf.ID,
+		Name:        wf.Name,
+		Filename:    filename,
+		Active:      wf.Active,
+		Credentials: credentials,
+		ProjectID:   projectID,
+		ProjectName: projectName,
+		UpdatedAt:   wf.UpdatedAt,
 	}
+	p.manifest.Credentials = MergeCredentials(p.manifest.Credentials, credentials)
 
 	// Extract sub-workflow IDs
-	subIDs := ExtractSubWorkflowIDs(wf.Nodes)
+	subIDs := p.followedRefs(wf)
 	if len(subIDs) > 0 {
 		p.manifest.Dependencies[workflowID] = subIDs
 	}
 
+	if p.AfterPull != nil {
+		if err := p.AfterPull(wf, p.manifest); err != nil {
+			return &afterPullError{err: err}
+		}
+	}
+
 	// Recursively pull sub-workflows
 	for _, subID := range subIDs {
 		if err := p.pullRecursive(subID); err != nil {
+			var hookErr *afterPullError
+			if errors.Is(err, ErrFilenameConflict) || errors.As(err, &hookErr) {
+				return err
+			}
 			// Log warning but continue - sub-workflow might be deleted or inaccessible
-			fmt.Printf("Warning: could not pull sub-workflow %s: %v\n", subID, err)
+			logging.Warn(fmt.Sprintf("could not pull sub-workflow %s: %v", subID, err), "workflow", subID, "parent", workflowID, "error", err)
 		}
 	}
 
 	return nil
 }
 
+// afterPullError marks errors of AfterPull, which stop the pull even for
+// sub-workflows
+type afterPullError struct {
+	err error
+}
+
+func (e *afterPullError) Error() string { return e.err.Error() }
+func (e *afterPullError) Unwrap() error { return e.err }
+
+// followedRefs returns the IDs of the sub-workflows of wf to pull, and
+// records the references left out in the manifest
+func (p *RecursivePuller) followedRefs(wf *api.Workflow) []string {
+	if p.NoFollow {
+		return nil
+	}
+	var ids []string
+	seen := make(map[string]bool)
+	for _, ref := range ExtractSubWorkflowRefs(wf.Nodes) {
+		reason := ""
+		switch {
+		case contains(p.IgnoreIDs, ref.ID):
+			reason = SkipReasonIgnored
+		case len(p.FollowTypes) > 0 && !contains(p.FollowTypes, ref.NodeType):
+			reason = SkipReasonType
+		}
+		if reason != "" {
+			p.manifest.Skipped = append(p.manifest.Skipped, SkippedRef{
+				Workflow: wf.ID,
+				Node:     ref.Node,
+				NodeType: ref.NodeType,
+				Target:   ref.ID,
+				Reason:   reason,
+			})
+			continue
+		}
+		if !seen[ref.ID] {
+			ids = append(ids, ref.ID)
+			seen[ref.ID] = true
+		}
+	}
+	return ids
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // GetPushOrder returns workflow IDs in dependency order (dependencies first)
 func (m *Manifest) GetPushOrder() []string {
 	// Build reverse dependency graph
@@ -131,12 +533,17 @@ func (m *Manifest) GetPushOrder() []string {
 	var order []string
 	var queue []string
 
-	// Start with nodes that have no dependencies
+	// Start with nodes that have no dependencies, sorted so that the
+	// order is stable
 	for id, degree := range inDegree {
 		if degree == 0 {
 			queue = append(queue, id)
 		}
 	}
+	sort.Strings(queue)
+	for _, dependents := range dependedBy {
+		sort.Strings(dependents)
+	}
 
 	for len(queue) > 0 {
 		// Pop from queue
@@ -155,3 +562,147 @@ func (m *Manifest) GetPushOrder() []string {
 
 	return order
 }
+
+// Validate checks the manifest for problems that would break a push and
+// returns a description of each. Workflows caught in a dependency cycle
+// are reported because GetPushOrder leaves them out, as are dependencies
+// on workflows the manifest doesn't list. If dir is not empty, every
+// workflow file must exist in it and parse, and a file with an ID must
+// carry the one it is listed under.
+func (m *Manifest) Validate(dir string) []string {
+	var problems []string
+	if m.RootWorkflow != "" {
+		if _, ok := m.Workflows[m.RootWorkflow]; !ok {
+			problems = append(problems, fmt.Sprintf("root workflow %s is not listed in workflows", m.RootWorkflow))
+		}
+	}
+
+	ordered := make(map[string]bool, len(m.Workflows))
+	for _, id := range m.GetPushOrder() {
+		ordered[id] = true
+	}
+
+	ids := make([]string, 0, len(m.Workflows))
+	for id := range m.Workflows {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// filenames maps lowercased file names to the first workflow using
+	// them, since case-insensitive file systems would merge them
+	filenames := make(map[string]string, len(ids))
+	for _, id := range ids {
+		meta := m.Workflows[id]
+		if meta.ID != "" && meta.ID != id {
+			problems = append(problems, fmt.Sprintf("workflow %s is listed with ID %s", id, meta.ID))
+		}
+		if !ordered[id] {
+			problems = append(problems, fmt.Sprintf("workflow %s (%s) is part of a dependency cycle and has no push order", meta.Name, id))
+		}
+		for _, dep := range m.Dependencies[id] {
+			if _, ok := m.Workflows[dep]; !ok {
+				problems = append(problems, fmt.Sprintf("workflow %s (%s) depends on %s, which is not listed in workflows", meta.Name, id, dep))
+			}
+		}
+		if meta.Filename == "" {
+			problems = append(problems, fmt.Sprintf("workflow %s (%s) has no filename", meta.Name, id))
+			continue
+		}
+		key := strings.ToLower(meta.Filename)
+		if other, ok := filenames[key]; ok {
+			problems = append(problems, fmt.Sprintf("workflow %s (%s): file %s is also used by %s", meta.Name, id, meta.Filename, other))
+		} else {
+			filenames[key] = id
+		}
+		if dir != "" {
+			problems = append(problems, checkWorkflowFile(dir, id, meta)...)
+		}
+	}
+
+	var unlisted []string
+	for id := range m.Dependencies {
+		if _, ok := m.Workflows[id]; !ok {
+			unlisted = append(unlisted, id)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, id := range unlisted {
+		problems = append(problems, fmt.Sprintf("dependencies are recorded for workflow %s, which is not listed in workflows", id))
+	}
+	return problems
+}
+
+// checkWorkflowFile reports problems with the file of a manifest entry
+func checkWorkflowFile(dir, id string, meta WorkflowMeta) []string {
+	data, err := os.ReadFile(filepath.Join(dir, meta.Filename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{fmt.Sprintf("workflow %s (%s): file %s not found", meta.Name, id, meta.Filename)}
+		}
+		return []string{fmt.Sprintf("workflow %s (%s): %v", meta.Name, id, err)}
+	}
+	wf, err := ParseWorkflowJSON(data)
+	if err != nil {
+		return []string{fmt.Sprintf("workflow %s (%s): %s: %v", meta.Name, id, meta.Filename, err)}
+	}
+	if wf.ID != "" && wf.ID != id {
+		return []string{fmt.Sprintf("workflow %s (%s): file %s has ID %q", meta.Name, id, meta.Filename, wf.ID)}
+	}
+	return nil
+}
+
+// Remap returns a copy of the manifest with workflow IDs replaced according
+// to mapping, e.g. after the workflows were recreated on another instance.
+// IDs without a mapping are kept as they are.
+func (m *Manifest) Remap(mapping map[string]string) *Manifest {
+	newID := func(id string) string {
+		if mapped, ok := mapping[id]; ok {
+			return mapped
+		}
+		return id
+	}
+
+	remapped := &Manifest{
+		RootWorkflow: newID(m.RootWorkflow),
+		Workflows:    make(map[string]WorkflowMeta, len(m.Workflows)),
+		Dependencies: make(map[string][]string, len(m.Dependencies)),
+		Credentials:  m.Credentials,
+		Instance:     m.Instance,
+	}
+
+	for id, meta := range m.Workflows {
+		meta.ID = newID(id)
+		remapped.Workflows[newID(id)] = meta
+	}
+	for id, deps := range m.Dependencies {
+		mappedDeps := make([]string, len(deps))
+		for i, dep := range deps {
+			mappedDeps[i] = newID(dep)
+		}
+		remapped.Dependencies[newID(id)] = mappedDeps
+	}
+	if m.Callers != nil {
+		remapped.Callers = make(map[string][]string, len(m.Callers))
+		for id, callers := range m.Callers {
+			mappedCallers := make([]string, len(callers))
+			for i, caller := range callers {
+				mappedCallers[i] = newID(caller)
+			}
+			remapped.Callers[newID(id)] = mappedCallers
+		}
+	}
+	for _, ref := range m.Skipped {
+		ref.Workflow = newID(ref.Workflow)
+		ref.Target = newID(ref.Target)
+		remapped.Skipped = append(remapped.Skipped, ref)
+	}
+	for _, cycle := range m.Cycles {
+		mappedCycle := make([]string, len(cycle))
+		for i, id := range cycle {
+			mappedCycle[i] = newID(id)
+		}
+		remapped.Cycles = append(remapped.Cycles, mappedCycle)
+	}
+
+	return remapped
+}
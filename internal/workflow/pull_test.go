@@ -0,0 +1,89 @@
+package workflow
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+)
+
+// callerOf returns a workflow with an Execute Workflow node calling each
+// of the given IDs
+func callerOf(id, name string, calls ...string) *api.Workflow {
+	wf := &api.Workflow{ID: id, Name: name, Nodes: []map[string]interface{}{}}
+	for _, target := range calls {
+		wf.Nodes = append(wf.Nodes, map[string]interface{}{
+			"name":       "Call " + target,
+			"type":       "n8n-nodes-base.executeWorkflow",
+			"parameters": map[string]interface{}{"workflowId": target},
+		})
+	}
+	return wf
+}
+
+func TestRecursivePullCycle(t *testing.T) {
+	tests := []struct {
+		name       string
+		workflows  []*api.Workflow
+		wantCycles [][]string
+	}{
+		{
+			name:       "mutual calls",
+			workflows:  []*api.Workflow{callerOf("a", "A", "b"), callerOf("b", "B", "a")},
+			wantCycles: [][]string{{"a", "b", "a"}},
+		},
+		{
+			name:       "self call",
+			workflows:  []*api.Workflow{callerOf("a", "A", "a")},
+			wantCycles: [][]string{{"a", "a"}},
+		},
+		{
+			name:       "cycle below the root",
+			workflows:  []*api.Workflow{callerOf("a", "A", "b"), callerOf("b", "B", "c"), callerOf("c", "C", "b")},
+			wantCycles: [][]string{{"b", "c", "b"}},
+		},
+		{
+			name:      "shared sub-workflow is no cycle",
+			workflows: []*api.Workflow{callerOf("a", "A", "b", "c"), callerOf("b", "B", "c"), callerOf("c", "C")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			for _, wf := range tt.workflows {
+				srv.Handle(http.MethodGet, "/workflows/"+wf.ID, apitest.Response{Body: wf})
+			}
+
+			done := make(chan struct{})
+			var result *PullResult
+			var err error
+			go func() {
+				defer close(done)
+				result, err = NewRecursivePuller(api.NewClient(srv.URL, "key")).Pull("a")
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Pull() didn't terminate")
+			}
+			if err != nil {
+				t.Fatalf("Pull() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(result.Manifest.Cycles, tt.wantCycles) {
+				t.Errorf("Cycles = %v, want %v", result.Manifest.Cycles, tt.wantCycles)
+			}
+			if len(result.Workflows) != len(tt.workflows) {
+				t.Errorf("pulled %d workflow(s), want %d", len(result.Workflows), len(tt.workflows))
+			}
+			if got := len(srv.Requests()); got != len(tt.workflows) {
+				t.Errorf("sent %d request(s), want each workflow fetched once (%d)", got, len(tt.workflows))
+			}
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package workflow
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/logging"
 )
 
 // Pusher handles pushing workflows to n8n
@@ -15,6 +17,125 @@ type Pusher struct {
 	dir    string
 	// Maps old IDs to new IDs (for create mode)
 	idMapping map[string]string
+
+	// Force updates workflows even when the remote copy is unchanged
+	Force bool
+	// AutoLayout recomputes node positions of created workflows
+	AutoLayout bool
+	// CredentialMapping maps credential IDs in the files to the IDs to use
+	// on the target instance
+	CredentialMapping map[string]string
+	// AllowDuplicates skips the check for existing workflows with the
+	// same name in create mode
+	AllowDuplicates bool
+	// RequireUniqueNames refuses the push if a workflow would end up with
+	// a name that another workflow on the instance, or another file of
+	// the push, already uses. Unlike the create mode check, it also
+	// covers updates, e.g. of workflows renamed in their file.
+	RequireUniqueNames bool
+	// PrunePinData strips pinned test data before uploading
+	PrunePinData bool
+	// NodeFilter limits which nodes are considered when checking
+	// whether a workflow is unchanged
+	NodeFilter NodeFilter
+	// Transforms run on each workflow right after it is read from disk
+	Transforms []Transform
+	// Interpolator expands placeholders in workflow files before they are
+	// parsed (nil = no interpolation)
+	Interpolator *Interpolator
+	// VersionLimits caps node typeVersions; workflows exceeding them are
+	// rejected before anything is pushed, unless Downgrade is set
+	VersionLimits VersionLimits
+	// Downgrade lowers typeVersions above VersionLimits instead of failing
+	Downgrade bool
+	// DiffForced fetches the remote copy of workflows updated with Force,
+	// so that their Change has a node diff
+	DiffForced bool
+	// StaticData selects the staticData sent with updated workflows
+	// ("" = StaticDataPreserve). Only StaticDataReset affects created
+	// workflows.
+	StaticData StaticDataMode
+	// ContinueOnError records workflows that fail to upload in the result
+	// and pushes the rest instead of stopping. In create mode, workflows
+	// calling a failed one are skipped.
+	ContinueOnError bool
+}
+
+// StaticDataMode selects which staticData an update sends. n8n replaces
+// the stored staticData with it, and trigger nodes keep state there, such
+// as the time a polling trigger last checked for new items. Pushing a
+// file with stale staticData therefore makes triggers lose their place.
+type StaticDataMode string
+
+const (
+	// StaticDataPreserve keeps the staticData stored on the instance
+	StaticDataPreserve StaticDataMode = "preserve"
+	// StaticDataReset clears the staticData
+	StaticDataReset StaticDataMode = "reset"
+	// StaticDataFile sends the staticData of the local file
+	StaticDataFile StaticDataMode = "file"
+)
+
+// Apply sets the staticData of wf before it is sent. remote is the
+// instance's copy of wf; it may be nil for StaticDataReset and
+// StaticDataFile.
+func (m StaticDataMode) Apply(wf, remote *api.Workflow) {
+	switch m {
+	case StaticDataReset:
+		wf.StaticData = map[string]interface{}{}
+	case StaticDataFile:
+	default:
+		wf.StaticData = remote.StaticData
+	}
+}
+
+// NeedsRemote reports whether Apply needs the instance's copy
+func (m StaticDataMode) NeedsRemote() bool {
+	return m != StaticDataReset && m != StaticDataFile
+}
+
+// PushResult summarizes the outcome of a push operation
+type PushResult struct {
+	Created   int
+	Updated   int
+	Unchanged int
+	// Failed and Skipped count the workflows that failed to upload, and
+	// those skipped because a sub-workflow failed, with ContinueOnError
+	Failed  int
+	Skipped int
+	// PinDataPruned counts nodes whose pinned data was removed
+	PinDataPruned int
+	// NodesFiltered counts nodes ignored by the node filter during
+	// change detection, local and remote combined
+	NodesFiltered int
+	// Transformed counts the changes made by transforms
+	Transformed int
+	// Downgraded lists nodes whose typeVersion was lowered
+	Downgraded []VersionViolation
+	// IDMapping maps manifest IDs to the IDs of the created workflows
+	IDMapping map[string]string
+	// Workflows holds the server's copy of each created or updated
+	// workflow, keyed by manifest ID
+	Workflows map[string]*api.Workflow
+	// Changes records what was done with each workflow, in push order
+	Changes []Change
+	// handled holds the manifest IDs that have a Change
+	handled map[string]bool
+}
+
+// AddSkipped records a skipped Change for every workflow of manifest the
+// push didn't get to, e.g. because an earlier one failed
+func (r *PushResult) AddSkipped(manifest *Manifest) {
+	for _, id := range manifest.GetPushOrder() {
+		if meta, ok := manifest.Workflows[id]; ok && !r.handled[id] {
+			r.addChange(id, Change{ID: meta.ID, Name: meta.Name, Action: ActionSkipped, Filename: meta.Filename})
+		}
+	}
+}
+
+func (r *PushResult) addChange(manifestID string, change Change) {
+	r.handled[manifestID] = true
+	r.Changes = append(r.Changes, change)
 }
 
 // NewPusher creates a new workflow pusher
@@ -27,55 +148,356 @@ func NewPusher(client *api.Client, dir string) *Pusher {
 }
 
 // Push pushes workflows according to the manifest
-func (p *Pusher) Push(manifest *Manifest, create bool) error {
+func (p *Pusher) Push(manifest *Manifest, create bool) (*PushResult, error) {
+	// Files pulled without their staticData can't provide it, so keep
+	// the instance's copy instead of clearing it
+	staticData := p.StaticData
+	if staticData == StaticDataFile && contains(manifest.Stripped, "staticData") {
+		logging.Info("Note: staticData was stripped on pull; keeping the instance's copy")
+		staticData = StaticDataPreserve
+	}
+
 	// Get push order (dependencies first)
 	order := manifest.GetPushOrder()
+	result := &PushResult{
+		IDMapping: p.idMapping,
+		Workflows: make(map[string]*api.Workflow),
+		handled:   make(map[string]bool),
+	}
 
+	// Check all names up front so nothing is created if any would clash
+	if create && !p.AllowDuplicates && !p.RequireUniqueNames {
+		for _, id := range order {
+			meta, exists := manifest.Workflows[id]
+			if !exists {
+				continue
+			}
+			if err := CheckDuplicateName(p.client, meta.Name); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	// Read all files up front so that invalid files stop the push before
+	// anything is uploaded
+	workflows := make(map[string]*api.Workflow, len(order))
 	for _, id := range order {
 		meta, exists := manifest.Workflows[id]
 		if !exists {
 			continue
 		}
-
-		// Read workflow file
-		filePath := filepath.Join(p.dir, meta.Filename)
-		data, err := os.ReadFile(filePath)
+		wf, err := p.readWorkflow(meta, result)
 		if err != nil {
-			return fmt.Errorf("failed to read %s: %w", meta.Filename, err)
+			return result, err
 		}
+		workflows[id] = wf
+	}
 
-		var wf api.Workflow
-		if err := json.Unmarshal(data, &wf); err != nil {
-			return fmt.Errorf("failed to parse %s: %w", meta.Filename, err)
+	if p.RequireUniqueNames {
+		if err := p.checkUniqueNames(order, workflows, create); err != nil {
+			return result, err
 		}
+	}
 
-		// Update sub-workflow references if we're creating new workflows
-		if create && len(p.idMapping) > 0 {
-			p.updateSubWorkflowReferences(&wf)
+	// failed holds the workflows that failed or were skipped with
+	// ContinueOnError
+	failed := make(map[string]bool)
+	for _, id := range order {
+		meta, exists := manifest.Workflows[id]
+		if !exists {
+			continue
 		}
 
+		// Created dependents would reference a sub-workflow that doesn't
+		// exist on the instance
 		if create {
-			// Remove ID so n8n generates a new one
-			wf.ID = ""
-			created, err := p.client.CreateWorkflow(&wf)
-			if err != nil {
-				return fmt.Errorf("failed to create workflow %s: %w", meta.Name, err)
+			if dep := failedDependency(manifest, id, failed); dep != "" {
+				failed[id] = true
+				result.Skipped++
+				result.addChange(id, Change{
+					ID:       meta.ID,
+					Name:     meta.Name,
+					Action:   ActionSkipped,
+					Filename: meta.Filename,
+					Error:    fmt.Sprintf("sub-workflow %s was not created", workflowLabel(manifest, dep)),
+				})
+				logging.Info(fmt.Sprintf("Skipped: %s (ID: %s)", meta.Name, meta.ID), "action", ActionSkipped, "workflow", meta.ID, "name", meta.Name)
+				continue
 			}
-			// Store ID mapping for dependent workflows
-			p.idMapping[id] = created.ID
-			fmt.Printf("Created: %s (ID: %s)\n", created.Name, created.ID)
-		} else {
-			updated, err := p.client.UpdateWorkflow(wf.ID, &wf)
-			if err != nil {
-				return fmt.Errorf("failed to update workflow %s: %w", meta.Name, err)
+		}
+
+		if err := p.pushWorkflow(id, meta, workflows[id], create, staticData, result); err != nil {
+			if !p.ContinueOnError {
+				return result, err
 			}
-			fmt.Printf("Updated: %s (ID: %s)\n", updated.Name, updated.ID)
+			failed[id] = true
+			result.Failed++
+			result.addChange(id, Change{
+				ID:       meta.ID,
+				Name:     meta.Name,
+				Action:   ActionFailed,
+				Filename: meta.Filename,
+				Error:    err.Error(),
+			})
+			logging.Warn(fmt.Sprintf("Failed: %s (ID: %s)", meta.Name, meta.ID), "action", ActionFailed, "workflow", meta.ID, "name", meta.Name, "error", err)
+		}
+	}
+
+	return result, nil
+}
+
+// failedDependency returns a sub-workflow of id that is in failed, or ""
+func failedDependency(manifest *Manifest, id string, failed map[string]bool) string {
+	for _, dep := range manifest.Dependencies[id] {
+		if failed[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+// workflowLabel returns the name of a manifest workflow, or its ID if the
+// manifest doesn't list it
+func workflowLabel(manifest *Manifest, id string) string {
+	if meta, ok := manifest.Workflows[id]; ok && meta.Name != "" {
+		return meta.Name
+	}
+	return id
+}
+
+// pushWorkflow creates or updates one workflow of the manifest and
+// records the outcome in result
+func (p *Pusher) pushWorkflow(id string, meta WorkflowMeta, wf *api.Workflow, create bool, staticData StaticDataMode, result *PushResult) error {
+	// Update sub-workflow references if we're creating new workflows
+	if create && len(p.idMapping) > 0 {
+		p.updateSubWorkflowReferences(wf)
+	}
+	if len(p.CredentialMapping) > 0 {
+		RewriteCredentialReferences(wf, p.CredentialMapping)
+	}
+	if p.PrunePinData {
+		result.PinDataPruned += PrunePinData(wf)
+	}
+
+	if create {
+		// Remove ID so n8n generates a new one
+		wf.ID = ""
+		if staticData == StaticDataReset {
+			staticData.Apply(wf, nil)
+		}
+		if p.AutoLayout {
+			AutoLayout(wf)
+		}
+		created, err := p.client.CreateWorkflow(wf)
+		if err != nil {
+			return fmt.Errorf("failed to create workflow %s: %w", meta.Name, err)
 		}
+		// Store ID mapping for dependent workflows
+		p.idMapping[id] = created.ID
+		result.Workflows[id] = created
+		result.Created++
+		result.addChange(id, Change{
+			ID:       created.ID,
+			Name:     created.Name,
+			Action:   ActionCreated,
+			Filename: meta.Filename,
+			Diff:     &NodeDiffStat{Added: len(wf.Nodes)},
+		})
+		logging.Info(fmt.Sprintf("Created: %s (ID: %s)", created.Name, created.ID), "action", ActionCreated, "workflow", created.ID, "name", created.Name, "sourceId", id)
+		return nil
 	}
 
+	var remote *api.Workflow
+	if !p.Force || p.DiffForced || staticData.NeedsRemote() {
+		var err error
+		if remote, err = p.client.GetWorkflow(wf.ID); err != nil {
+			return fmt.Errorf("failed to get workflow %s: %w", meta.Name, err)
+		}
+	}
+	staticData.Apply(wf, remote)
+
+	if !p.Force {
+		unchanged, filtered, err := CompareWith(wf, remote, p.NodeFilter)
+		result.NodesFiltered += filtered
+		if err != nil {
+			return fmt.Errorf("failed to compare workflow %s: %w", meta.Name, err)
+		}
+		if unchanged {
+			result.Unchanged++
+			result.addChange(id, Change{
+				ID:       wf.ID,
+				Name:     wf.Name,
+				Action:   ActionUnchanged,
+				Filename: meta.Filename,
+				Diff:     &NodeDiffStat{},
+			})
+			logging.Info(fmt.Sprintf("Unchanged: %s (ID: %s)", wf.Name, wf.ID), "action", ActionUnchanged, "workflow", wf.ID, "name", wf.Name)
+			return nil
+		}
+	}
+
+	updated, err := p.client.UpdateWorkflow(wf.ID, wf)
+	if err != nil {
+		return fmt.Errorf("failed to update workflow %s: %w", meta.Name, err)
+	}
+	result.Workflows[id] = updated
+	result.Updated++
+	change := Change{ID: updated.ID, Name: updated.Name, Action: ActionUpdated, Filename: meta.Filename}
+	if remote != nil {
+		diff := DiffNodes(remote, wf, p.NodeFilter)
+		change.Diff = &diff
+	}
+	result.addChange(id, change)
+	logging.Info(fmt.Sprintf("Updated: %s (ID: %s)", updated.Name, updated.ID), "action", ActionUpdated, "workflow", updated.ID, "name", updated.Name)
 	return nil
 }
 
+// readWorkflow reads a manifest entry's file and prepares it for upload:
+// placeholders are expanded, transforms applied, and version limits
+// checked
+func (p *Pusher) readWorkflow(meta WorkflowMeta, result *PushResult) (*api.Workflow, error) {
+	filePath := filepath.Join(p.dir, meta.Filename)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", meta.Filename, err)
+	}
+
+	if p.Interpolator != nil {
+		if data, err = p.Interpolator.Expand(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", meta.Filename, err)
+		}
+	}
+
+	wf, err := ParseWorkflowJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", meta.Filename, err)
+	}
+
+	changes, err := ApplyTransforms(p.Transforms, wf, StagePush)
+	if err != nil {
+		return nil, err
+	}
+	result.Transformed += changes
+
+	if p.Downgrade {
+		result.Downgraded = append(result.Downgraded, p.VersionLimits.Downgrade(wf)...)
+	} else if violations := p.VersionLimits.Check(wf); len(violations) > 0 {
+		return nil, &VersionError{Workflow: wf.Name, Violations: violations}
+	}
+
+	return wf, nil
+}
+
+// FindByName returns all workflows whose name matches exactly
+func FindByName(client *api.Client, name string) ([]api.Workflow, error) {
+	result, err := client.ListWorkflows(api.ListWorkflowsOptions{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []api.Workflow
+	for _, wf := range result.Data {
+		if wf.Name == name {
+			matches = append(matches, wf)
+		}
+	}
+	return matches, nil
+}
+
+// CheckDuplicateName returns an error if a workflow with the given name
+// already exists, to avoid creating the same workflow twice
+func CheckDuplicateName(client *api.Client, name string) error {
+	existing, err := FindByName(client, name)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing workflow %q: %w", name, err)
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(existing))
+	for i, wf := range existing {
+		ids[i] = wf.ID
+	}
+	return fmt.Errorf("a workflow named %q already exists (ID: %s). Push without --create to update it, or use --allow-duplicates",
+		name, strings.Join(ids, ", "))
+}
+
+// CheckUniqueName returns an error if a workflow other than the one with
+// the given ID is named name. Pass an empty ID for a workflow that is yet
+// to be created.
+func CheckUniqueName(client *api.Client, name, id string) error {
+	existing, err := FindByName(client, name)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing workflow %q: %w", name, err)
+	}
+
+	var ids []string
+	for _, wf := range existing {
+		if wf.ID != id {
+			ids = append(ids, wf.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return fmt.Errorf("another workflow named %q already exists (ID: %s)", name, strings.Join(ids, ", "))
+}
+
+// checkUniqueNames checks the names of all workflows to push before
+// anything is uploaded, both against each other and against the instance
+func (p *Pusher) checkUniqueNames(order []string, workflows map[string]*api.Workflow, create bool) error {
+	seen := make(map[string]string)
+	for _, id := range order {
+		wf, ok := workflows[id]
+		if !ok {
+			continue
+		}
+		if other, dup := seen[wf.Name]; dup {
+			return fmt.Errorf("workflows %s and %s are both named %q", other, id, wf.Name)
+		}
+		seen[wf.Name] = id
+
+		remoteID := wf.ID
+		if create {
+			remoteID = ""
+		}
+		if err := CheckUniqueName(p.client, wf.Name, remoteID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsUnchanged fetches the remote copy of wf and reports whether its
+// normalized content matches the local one, so the update can be skipped.
+// Nodes rejected by filter are left out of the comparison on both sides;
+// their combined count is returned.
+func IsUnchanged(client *api.Client, wf *api.Workflow, filter NodeFilter) (bool, int, error) {
+	_, unchanged, filtered, err := CompareRemote(client, wf, filter)
+	return unchanged, filtered, err
+}
+
+// CompareRemote works like IsUnchanged and also returns the remote copy
+// it compared against
+func CompareRemote(client *api.Client, wf *api.Workflow, filter NodeFilter) (*api.Workflow, bool, int, error) {
+	remote, err := client.GetWorkflow(wf.ID)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	equal, filtered, err := CompareWith(wf, remote, filter)
+	return remote, equal, filtered, err
+}
+
+// CompareWith reports whether wf matches an already fetched remote copy,
+// like IsUnchanged
+func CompareWith(wf, remote *api.Workflow, filter NodeFilter) (bool, int, error) {
+	local, localFiltered := filter.Apply(wf)
+	filteredRemote, remoteFiltered := filter.Apply(remote)
+	equal, err := Equal(local, filteredRemote)
+	return equal, localFiltered + remoteFiltered, err
+}
+
 // updateSubWorkflowReferences updates Execute Workflow node references
 // to use new IDs when creating copies of workflows
 func (p *Pusher) updateSubWorkflowReferences(wf *api.Workflow) {
@@ -102,6 +524,17 @@ func (p *Pusher) updateSubWorkflowReferences(wf *api.Workflow) {
 			}
 		}
 
+		// Update workflowId resource locator ({"__rl": true, "value": "<id>", ...})
+		if locator, ok := params["workflowId"].(map[string]interface{}); ok {
+			if oldID, ok := locator["value"].(string); ok {
+				if newID, exists := p.idMapping[oldID]; exists {
+					locator["value"] = newID
+					params["workflowId"] = locator
+					wf.Nodes[i]["parameters"] = params
+				}
+			}
+		}
+
 		// Update workflow.id if present
 		if wfObj, ok := params["workflow"].(map[string]interface{}); ok {
 			if oldID, ok := wfObj["id"].(string); ok {
@@ -114,3 +547,56 @@ func (p *Pusher) updateSubWorkflowReferences(wf *api.Workflow) {
 		}
 	}
 }
+
+// RewriteCredentialReferences replaces credential IDs in the nodes'
+// credentials blocks according to mapping. References without a mapping
+// are left unchanged.
+func RewriteCredentialReferences(wf *api.Workflow, mapping map[string]string) int {
+	rewritten := 0
+	for _, node := range wf.Nodes {
+		creds, ok := node["credentials"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, cred := range creds {
+			credMap, ok := cred.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			oldID, _ := credMap["id"].(string)
+			if newID, exists := mapping[oldID]; exists && oldID != "" {
+				credMap["id"] = newID
+				rewritten++
+			}
+		}
+	}
+	return rewritten
+}
+
+// UnmappedCredentials returns the credentials used by wf whose IDs the
+// mapping doesn't cover, as "name (ID)", each once and sorted
+func UnmappedCredentials(wf *api.Workflow, mapping map[string]string) []string {
+	seen := make(map[string]bool)
+	var unmapped []string
+	for _, node := range wf.Nodes {
+		creds, ok := node["credentials"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, cred := range creds {
+			credMap, ok := cred.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := credMap["id"].(string)
+			if _, mapped := mapping[id]; id == "" || mapped || seen[id] {
+				continue
+			}
+			seen[id] = true
+			name, _ := credMap["name"].(string)
+			unmapped = append(unmapped, fmt.Sprintf("%s (%s)", name, id))
+		}
+	}
+	sort.Strings(unmapped)
+	return unmapped
+}
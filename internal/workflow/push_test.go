@@ -0,0 +1,167 @@
+package workflow
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+)
+
+func TestPushStaticData(t *testing.T) {
+	remoteData := map[string]interface{}{"node:Poll": map[string]interface{}{"lastTimeChecked": "2026-10-01T00:00:00Z"}}
+	fileData := map[string]interface{}{"node:Poll": map[string]interface{}{"lastTimeChecked": "2026-01-01T00:00:00Z"}}
+
+	tests := []struct {
+		name       string
+		mode       StaticDataMode
+		force      bool
+		wantGet    bool
+		wantStatic interface{}
+	}{
+		{name: "preserved by default", wantGet: true, wantStatic: remoteData},
+		{name: "preserved with force", mode: StaticDataPreserve, force: true, wantGet: true, wantStatic: remoteData},
+		{name: "reset", mode: StaticDataReset, force: true, wantStatic: map[string]interface{}{}},
+		{name: "reset compares to the remote", mode: StaticDataReset, wantGet: true, wantStatic: map[string]interface{}{}},
+		{name: "from file", mode: StaticDataFile, force: true, wantStatic: fileData},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			local := api.Workflow{
+				ID:         "w1",
+				Name:       "Poller",
+				Nodes:      []map[string]interface{}{{"name": "Poll", "type": "n8n-nodes-base.rssFeedReadTrigger"}},
+				StaticData: fileData,
+			}
+			data, err := json.Marshal(local)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "Poller.json"), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			remote := local
+			remote.Nodes = []map[string]interface{}{{"name": "Old", "type": "n8n-nodes-base.rssFeedReadTrigger"}}
+			remote.StaticData = remoteData
+
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			srv.Handle(http.MethodGet, "/workflows/w1", apitest.Response{Body: remote})
+			srv.Handle(http.MethodPut, "/workflows/w1", apitest.Response{Body: local})
+
+			pusher := NewPusher(api.NewClient(srv.URL, "key"), dir)
+			pusher.StaticData = tt.mode
+			pusher.Force = tt.force
+			manifest := &Manifest{Workflows: map[string]WorkflowMeta{"w1": {ID: "w1", Name: "Poller", Filename: "Poller.json"}}}
+			result, err := pusher.Push(manifest, false)
+			if err != nil {
+				t.Fatalf("Push() error = %v", err)
+			}
+			if result.Updated != 1 {
+				t.Fatalf("Updated = %d, want 1", result.Updated)
+			}
+
+			gotGet := false
+			var put *apitest.Request
+			for _, req := range srv.Requests() {
+				switch req.Method {
+				case http.MethodGet:
+					gotGet = true
+				case http.MethodPut:
+					put = &req
+				}
+			}
+			if gotGet != tt.wantGet {
+				t.Errorf("fetched the remote workflow = %v, want %v", gotGet, tt.wantGet)
+			}
+			if put == nil {
+				t.Fatal("no update request sent")
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(put.Body, &body); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(body["staticData"], tt.wantStatic) {
+				t.Errorf("sent staticData = %#v, want %#v", body["staticData"], tt.wantStatic)
+			}
+		})
+	}
+}
+
+func TestPushPinData(t *testing.T) {
+	remote := api.Workflow{
+		ID:      "w1",
+		Name:    "Hook",
+		Nodes:   []map[string]interface{}{{"name": "Webhook", "type": "n8n-nodes-base.webhook"}},
+		PinData: map[string]interface{}{"Webhook": []interface{}{map[string]interface{}{"json": map[string]interface{}{"on": "server"}}}},
+	}
+
+	tests := []struct {
+		name       string
+		pinData    string
+		prune      bool
+		force      bool
+		wantPut    bool
+		wantPruned int
+	}{
+		{name: "empty pinData in file is unchanged", pinData: `{}`},
+		{name: "empty pinData in file", pinData: `{}`, force: true, wantPut: true},
+		{name: "pinned data in file", pinData: `{"Webhook":[{"json":{"on":"file"}}]}`, force: true, wantPut: true},
+		{name: "pruned", pinData: `{"Webhook":[{"json":{"on":"file"}}]}`, prune: true, force: true, wantPut: true, wantPruned: 1},
+		{name: "pruned without changes is unchanged", pinData: `{"Webhook":[{"json":{"on":"file"}}]}`, prune: true, wantPruned: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := `{"id":"w1","name":"Hook","nodes":[{"name":"Webhook","type":"n8n-nodes-base.webhook"}],"connections":{},"pinData":` + tt.pinData + `}`
+			if err := os.WriteFile(filepath.Join(dir, "Hook.json"), []byte(file), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			srv := apitest.NewServer()
+			t.Cleanup(srv.Close)
+			srv.Handle(http.MethodGet, "/workflows/w1", apitest.Response{Body: remote})
+			srv.Handle(http.MethodPut, "/workflows/w1", apitest.Response{Body: remote})
+
+			pusher := NewPusher(api.NewClient(srv.URL, "key"), dir)
+			pusher.PrunePinData = tt.prune
+			pusher.Force = tt.force
+			manifest := &Manifest{Workflows: map[string]WorkflowMeta{"w1": {ID: "w1", Name: "Hook", Filename: "Hook.json"}}}
+			result, err := pusher.Push(manifest, false)
+			if err != nil {
+				t.Fatalf("Push() error = %v", err)
+			}
+			if result.PinDataPruned != tt.wantPruned {
+				t.Errorf("PinDataPruned = %d, want %d", result.PinDataPruned, tt.wantPruned)
+			}
+
+			var put *apitest.Request
+			for _, req := range srv.Requests() {
+				if req.Method == http.MethodPut {
+					put = &req
+				}
+			}
+			if (put != nil) != tt.wantPut {
+				t.Fatalf("sent an update = %v, want %v", put != nil, tt.wantPut)
+			}
+			if put == nil {
+				return
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(put.Body, &body); err != nil {
+				t.Fatal(err)
+			}
+			if pinData, ok := body["pinData"]; ok {
+				t.Errorf("sent pinData %v, want none", pinData)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// maxCandidates limits the workflows listed in an AmbiguousError
+const maxCandidates = 10
+
+// AmbiguousError is returned when a workflow reference matches several
+// workflows
+type AmbiguousError struct {
+	Ref     string
+	Matches []api.Workflow
+}
+
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d workflows:", e.Ref, len(e.Matches))
+	for i, wf := range e.Matches {
+		if i == maxCandidates {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(e.Matches)-maxCandidates)
+			break
+		}
+		fmt.Fprintf(&b, "\n  %s  %s", wf.ID, wf.Name)
+	}
+	return b.String()
+}
+
+// Get fetches the workflow ref refers to. ref is tried as a workflow ID
+// first; if no workflow has that ID, it is taken as the start of one and,
+// if no ID starts with it, as a workflow name. A ref matching several
+// workflows returns an AmbiguousError listing them.
+func Get(client *api.Client, ref string) (*api.Workflow, error) {
+	wf, err := client.GetWorkflow(ref)
+	if err == nil || !api.IsNotFound(err) {
+		return wf, err
+	}
+
+	matches, listErr := matchRef(client, ref)
+	if listErr != nil {
+		return nil, listErr
+	}
+	switch len(matches) {
+	case 0:
+		return nil, err
+	case 1:
+		return client.GetWorkflow(matches[0].ID)
+	default:
+		return nil, &AmbiguousError{Ref: ref, Matches: matches}
+	}
+}
+
+// ResolveID returns the ID of the workflow ref refers to, as Get does
+func ResolveID(client *api.Client, ref string) (string, error) {
+	wf, err := Get(client, ref)
+	if err != nil {
+		return "", err
+	}
+	return wf.ID, nil
+}
+
+// WithID calls fn with the ID of the workflow ref refers to and returns
+// that ID. Unlike ResolveID, it passes ref to fn as is first, so a workflow
+// ID costs no extra request; only if fn fails because nothing was found
+// is ref matched as Get does and fn called again with the match.
+func WithID(client *api.Client, ref string, fn func(id string) error) (string, error) {
+	err := fn(ref)
+	if err == nil || !api.IsNotFound(err) {
+		return ref, err
+	}
+
+	matches, listErr := matchRef(client, ref)
+	if listErr != nil {
+		return ref, listErr
+	}
+	for _, wf := range matches {
+		// The workflow exists, so something else wasn't found
+		if wf.ID == ref {
+			return ref, err
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return ref, err
+	case 1:
+		return matches[0].ID, fn(matches[0].ID)
+	default:
+		return ref, &AmbiguousError{Ref: ref, Matches: matches}
+	}
+}
+
+// matchRef lists the workflows whose ID starts with ref or, if there are
+// none, those named ref, sorted by ID
+func matchRef(client *api.Client, ref string) ([]api.Workflow, error) {
+	list, err := client.ListWorkflows(api.ListWorkflowsOptions{ExcludePinnedData: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workflows: %w", err)
+	}
+
+	var matches []api.Workflow
+	for _, wf := range list.Data {
+		if strings.HasPrefix(wf.ID, ref) {
+			matches = append(matches, wf)
+		}
+	}
+	if len(matches) == 0 {
+		for _, wf := range list.Data {
+			if wf.Name == ref {
+				matches = append(matches, wf)
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches, nil
+}
+
+// ResolveProject finds a project by ID or, failing that, by name. A name
+// used by several projects is an error.
+func ResolveProject(client *api.Client, ref string) (*api.Project, error) {
+	result, err := client.ListProjects(0, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	for i := range result.Data {
+		if result.Data[i].ID == ref {
+			return &result.Data[i], nil
+		}
+	}
+	var matches []*api.Project
+	for i := range result.Data {
+		if result.Data[i].Name == ref {
+			matches = append(matches, &result.Data[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("project %q not found", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("several projects are named %q; use the project ID", ref)
+	}
+}
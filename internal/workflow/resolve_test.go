@@ -0,0 +1,77 @@
+package workflow
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+	"github.com/enthus-appdev/n8n-cli/internal/api/apitest"
+)
+
+func TestWithID(t *testing.T) {
+	srv := apitest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Handle(http.MethodGet, "/workflows", apitest.Response{Body: map[string]interface{}{
+		"data": []map[string]string{
+			{"id": "abc123", "name": "Orders"},
+			{"id": "abd456", "name": "Invoices"},
+			{"id": "xyz789", "name": "Orders"},
+		},
+	}})
+	client := api.NewClient(srv.URL, "key")
+
+	notFound := &api.APIError{StatusCode: http.StatusNotFound, Message: "not found"}
+	otherErr := errors.New("server down")
+
+	tests := []struct {
+		name      string
+		ref       string
+		fail      map[string]error // errors fn returns per ID
+		wantID    string
+		wantCalls []string
+		wantList  bool
+		wantErr   error
+		ambiguous bool
+	}{
+		{name: "ID", ref: "abc123", wantID: "abc123", wantCalls: []string{"abc123"}},
+		{name: "prefix", ref: "abc", fail: map[string]error{"abc": notFound}, wantID: "abc123", wantCalls: []string{"abc", "abc123"}, wantList: true},
+		{name: "name", ref: "Invoices", fail: map[string]error{"Invoices": notFound}, wantID: "abd456", wantCalls: []string{"Invoices", "abd456"}, wantList: true},
+		{name: "other error", ref: "abc", fail: map[string]error{"abc": otherErr}, wantID: "abc", wantCalls: []string{"abc"}, wantErr: otherErr},
+		{name: "no match", ref: "nope", fail: map[string]error{"nope": notFound}, wantID: "nope", wantCalls: []string{"nope"}, wantList: true, wantErr: notFound},
+		{name: "existing ID not found by fn", ref: "abc123", fail: map[string]error{"abc123": notFound}, wantID: "abc123", wantCalls: []string{"abc123"}, wantList: true, wantErr: notFound},
+		{name: "ambiguous prefix", ref: "ab", fail: map[string]error{"ab": notFound}, wantID: "ab", wantCalls: []string{"ab"}, wantList: true, ambiguous: true},
+		{name: "ambiguous name", ref: "Orders", fail: map[string]error{"Orders": notFound}, wantID: "Orders", wantCalls: []string{"Orders"}, wantList: true, ambiguous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(srv.Requests())
+			var calls []string
+			id, err := WithID(client, tt.ref, func(id string) error {
+				calls = append(calls, id)
+				return tt.fail[id]
+			})
+
+			if id != tt.wantID {
+				t.Errorf("ID = %q, want %q", id, tt.wantID)
+			}
+			if !reflect.DeepEqual(calls, tt.wantCalls) {
+				t.Errorf("fn called with %v, want %v", calls, tt.wantCalls)
+			}
+			if listed := len(srv.Requests()) > before; listed != tt.wantList {
+				t.Errorf("listed workflows = %v, want %v", listed, tt.wantList)
+			}
+			var ambiguous *AmbiguousError
+			switch {
+			case tt.ambiguous:
+				if !errors.As(err, &ambiguous) {
+					t.Errorf("error = %v, want an AmbiguousError", err)
+				}
+			case err != tt.wantErr:
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
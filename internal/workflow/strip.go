@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// StrippableFields are the top-level fields Strip can leave out of pulled
+// files. None of them is needed to push a workflow: n8n ignores tags,
+// sharing, and timestamps on upload, and pinned data only matters in the
+// editor.
+var StrippableFields = []string{"staticData", "pinData", "tags", "shared", "createdAt", "updatedAt"}
+
+// ParseStripFields validates field names for Strip and returns them sorted
+// and without duplicates. "timestamps" stands for createdAt and updatedAt.
+func ParseStripFields(values []string) ([]string, error) {
+	set := make(map[string]bool)
+	for _, v := range values {
+		switch {
+		case v == "timestamps":
+			set["createdAt"] = true
+			set["updatedAt"] = true
+		case contains(StrippableFields, v):
+			set[v] = true
+		default:
+			return nil, fmt.Errorf("cannot strip %q (expected %s, or timestamps)", v, strings.Join(StrippableFields, ", "))
+		}
+	}
+
+	fields := make([]string, 0, len(set))
+	for field := range set {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// Strip returns a copy of wf without the given top-level fields. wf itself
+// is not modified.
+func Strip(wf *api.Workflow, fields []string) *api.Workflow {
+	stripped := *wf
+	for _, field := range fields {
+		switch field {
+		case "staticData":
+			stripped.StaticData = nil
+		case "pinData":
+			stripped.PinData = nil
+		case "tags":
+			stripped.Tags = nil
+		case "shared":
+			stripped.Shared = nil
+		case "createdAt":
+			stripped.CreatedAt = nil
+		case "updatedAt":
+			stripped.UpdatedAt = nil
+		}
+	}
+	return &stripped
+}
@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// Action is what a push did with a workflow
+type Action string
+
+const (
+	ActionCreated   Action = "created"
+	ActionUpdated   Action = "updated"
+	ActionUnchanged Action = "unchanged"
+	// ActionSkipped marks workflows a failed push didn't get to
+	ActionSkipped Action = "skipped"
+	// ActionFailed marks workflows that failed to upload while the push
+	// continued
+	ActionFailed Action = "failed"
+)
+
+// NodeDiffStat counts the nodes that differ between two versions of a
+// workflow. Nodes are matched by name.
+type NodeDiffStat struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+func (s NodeDiffStat) String() string {
+	return fmt.Sprintf("+%d -%d ~%d", s.Added, s.Removed, s.Changed)
+}
+
+// DiffNodes compares the nodes of an old and a new version of a workflow.
+// Nodes rejected by filter are left out on both sides.
+func DiffNodes(oldWf, newWf *api.Workflow, filter NodeFilter) NodeDiffStat {
+	oldWf, _ = filter.Apply(oldWf)
+	newWf, _ = filter.Apply(newWf)
+
+	before := make(map[string]string, len(oldWf.Nodes))
+	for _, node := range oldWf.Nodes {
+		before[nodeName(node)] = nodeJSON(node)
+	}
+
+	var stat NodeDiffStat
+	seen := make(map[string]bool, len(newWf.Nodes))
+	for _, node := range newWf.Nodes {
+		name := nodeName(node)
+		seen[name] = true
+		previous, ok := before[name]
+		switch {
+		case !ok:
+			stat.Added++
+		case previous != nodeJSON(node):
+			stat.Changed++
+		}
+	}
+	for name := range before {
+		if !seen[name] {
+			stat.Removed++
+		}
+	}
+	return stat
+}
+
+// nodeJSON returns a canonical encoding of a node for comparison
+func nodeJSON(node map[string]interface{}) string {
+	data, _ := json.Marshal(node)
+	return string(data)
+}
+
+// Change records what a push did with one workflow
+type Change struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Action   Action `json:"action"`
+	Filename string `json:"filename,omitempty"`
+	// Diff compares the pushed nodes with the previous remote version. It
+	// is nil when the remote version wasn't fetched.
+	Diff *NodeDiffStat `json:"diff,omitempty"`
+	// Error says why a workflow failed or was skipped
+	Error string `json:"error,omitempty"`
+}
+
+// ChangeSummary is the report written after a push
+type ChangeSummary struct {
+	Created   int      `json:"created"`
+	Updated   int      `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+	Skipped   int      `json:"skipped"`
+	Failed    int      `json:"failed"`
+	Workflows []Change `json:"workflows"`
+}
+
+// NewChangeSummary counts the actions of changes
+func NewChangeSummary(changes []Change) *ChangeSummary {
+	s := &ChangeSummary{Workflows: changes}
+	if s.Workflows == nil {
+		s.Workflows = []Change{}
+	}
+	for _, c := range changes {
+		switch c.Action {
+		case ActionCreated:
+			s.Created++
+		case ActionUpdated:
+			s.Updated++
+		case ActionUnchanged:
+			s.Unchanged++
+		case ActionSkipped:
+			s.Skipped++
+		case ActionFailed:
+			s.Failed++
+		}
+	}
+	return s
+}
+
+// Markdown renders the summary as a Markdown table, e.g. for a pull
+// request comment
+func (s *ChangeSummary) Markdown() string {
+	var b strings.Builder
+	b.WriteString("### Workflow changes\n\n")
+	fmt.Fprintf(&b, "%d created, %d updated, %d unchanged, %d skipped",
+		s.Created, s.Updated, s.Unchanged, s.Skipped)
+	if s.Failed > 0 {
+		fmt.Fprintf(&b, ", %d failed", s.Failed)
+	}
+	b.WriteString("\n")
+	if len(s.Workflows) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n| Workflow | ID | Action | Nodes (+added -removed ~changed) |\n")
+	b.WriteString("|---|---|---|---|\n")
+	for _, c := range s.Workflows {
+		diff := ""
+		if c.Diff != nil {
+			diff = c.Diff.String()
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", markdownCell(c.Name), c.ID, c.Action, diff)
+	}
+	return b.String()
+}
+
+// markdownCell escapes text for use in a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
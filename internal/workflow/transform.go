@@ -0,0 +1,239 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// Stage identifies when a transform runs
+type Stage string
+
+const (
+	// StagePull runs on workflows fetched from n8n, before they are written
+	StagePull Stage = "pull"
+	// StagePush runs on workflows read from disk, before they are uploaded
+	StagePush Stage = "push"
+)
+
+// Transform modifies a workflow in place on pull or push. Apply returns
+// the number of changes it made.
+type Transform interface {
+	Apply(wf *api.Workflow, stage Stage) (int, error)
+}
+
+// ApplyTransforms runs transforms on wf in order and returns the total
+// number of changes
+func ApplyTransforms(transforms []Transform, wf *api.Workflow, stage Stage) (int, error) {
+	total := 0
+	for _, t := range transforms {
+		n, err := t.Apply(wf, stage)
+		if err != nil {
+			return total, fmt.Errorf("transform failed for workflow %s: %w", wf.Name, err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// TransformRule is a find/replace on string node parameters. The rule file
+// format is:
+//
+//	{
+//	  "rules": [
+//	    {
+//	      "nodeType": "n8n-nodes-base.httpRequest",
+//	      "parameter": "url",
+//	      "find": "https://staging.example.com",
+//	      "replace": "https://api.example.com",
+//	      "regex": false,
+//	      "stages": ["push"]
+//	    }
+//	  ]
+//	}
+type TransformRule struct {
+	// NodeType restricts the rule to nodes of this type (empty = all nodes)
+	NodeType string `json:"nodeType,omitempty"`
+	// Parameter is a dot-separated path below the node's parameters, e.g.
+	// "options.baseUrl" (empty = every string parameter)
+	Parameter string `json:"parameter,omitempty"`
+	// Find is the text to search for, or a Go regular expression if Regex
+	// is set
+	Find string `json:"find"`
+	// Replace is the replacement text; with Regex, $1 etc. expand groups
+	Replace string `json:"replace"`
+	Regex   bool   `json:"regex,omitempty"`
+	// Stages limits the rule to pull or push (empty = both)
+	Stages []Stage `json:"stages,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// RuleSet is a Transform loaded from a rule file
+type RuleSet struct {
+	Source string          `json:"-"`
+	Rules  []TransformRule `json:"rules"`
+}
+
+// LoadTransforms reads transform rules from a JSON file, or from every
+// *.json file in a directory in lexical filename order. Rules run in the
+// order they are listed.
+func LoadTransforms(path string) ([]Transform, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access transform rules: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list transform rules: %w", err)
+		}
+		sort.Strings(files)
+	}
+
+	transforms := make([]Transform, 0, len(files))
+	for _, file := range files {
+		set, err := loadRuleSet(file)
+		if err != nil {
+			return nil, err
+		}
+		transforms = append(transforms, set)
+	}
+	return transforms, nil
+}
+
+func loadRuleSet(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transform rules: %w", err)
+	}
+
+	set := &RuleSet{Source: path}
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("failed to parse transform rules %s: %w", path, err)
+	}
+
+	for i := range set.Rules {
+		rule := &set.Rules[i]
+		if rule.Find == "" {
+			return nil, fmt.Errorf("%s: rule %d has no \"find\" value", path, i+1)
+		}
+		for _, stage := range rule.Stages {
+			if stage != StagePull && stage != StagePush {
+				return nil, fmt.Errorf("%s: rule %d has invalid stage %q (expected pull or push)", path, i+1, stage)
+			}
+		}
+		if rule.Regex {
+			rule.pattern, err = regexp.Compile(rule.Find)
+			if err != nil {
+				return nil, fmt.Errorf("%s: rule %d has an invalid regex: %w", path, i+1, err)
+			}
+		}
+	}
+
+	return set, nil
+}
+
+// Apply runs every rule of the set that is enabled for stage
+func (s *RuleSet) Apply(wf *api.Workflow, stage Stage) (int, error) {
+	changes := 0
+	for i := range s.Rules {
+		rule := &s.Rules[i]
+		if !rule.runsOn(stage) {
+			continue
+		}
+		for _, node := range wf.Nodes {
+			if rule.NodeType != "" {
+				if nodeType, _ := node["type"].(string); nodeType != rule.NodeType {
+					continue
+				}
+			}
+			params, ok := node["parameters"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			if rule.Parameter == "" {
+				changes += rule.replaceAll(params)
+				continue
+			}
+
+			keys := strings.Split(rule.Parameter, ".")
+			parent := params
+			for _, key := range keys[:len(keys)-1] {
+				if parent, ok = parent[key].(map[string]interface{}); !ok {
+					break
+				}
+			}
+			if parent == nil {
+				continue
+			}
+			last := keys[len(keys)-1]
+			if value, ok := parent[last].(string); ok {
+				if replaced := rule.replace(value); replaced != value {
+					parent[last] = replaced
+					changes++
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+func (r *TransformRule) runsOn(stage Stage) bool {
+	if len(r.Stages) == 0 {
+		return true
+	}
+	for _, s := range r.Stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *TransformRule) replace(value string) string {
+	if r.pattern != nil {
+		return r.pattern.ReplaceAllString(value, r.Replace)
+	}
+	return strings.ReplaceAll(value, r.Find, r.Replace)
+}
+
+// replaceAll applies the rule to every string inside a parameter value
+// and returns the number of strings changed
+func (r *TransformRule) replaceAll(value interface{}) int {
+	changes := 0
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if s, ok := item.(string); ok {
+				if replaced := r.replace(s); replaced != s {
+					v[key] = replaced
+					changes++
+				}
+				continue
+			}
+			changes += r.replaceAll(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				if replaced := r.replace(s); replaced != s {
+					v[i] = replaced
+					changes++
+				}
+				continue
+			}
+			changes += r.replaceAll(item)
+		}
+	}
+	return changes
+}
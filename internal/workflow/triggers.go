@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// LegacyExecutionOrder is the execution order of workflows created before
+// n8n 1.0, which have no executionOrder setting
+const LegacyExecutionOrder = "v0"
+
+// IsTriggerType reports whether a node type starts workflow executions
+func IsTriggerType(nodeType string) bool {
+	lower := strings.ToLower(nodeType)
+	return strings.HasSuffix(lower, "trigger") || strings.HasSuffix(lower, ".webhook")
+}
+
+// HasTrigger reports whether wf has an enabled trigger node. Workflows
+// without one can only run as sub-workflows or manually.
+func HasTrigger(wf *api.Workflow) bool {
+	for _, node := range wf.Nodes {
+		nodeType, _ := node["type"].(string)
+		disabled, _ := node["disabled"].(bool)
+		if IsTriggerType(nodeType) && !disabled {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecutionOrder returns the settings.executionOrder of wf, or
+// LegacyExecutionOrder if it isn't set
+func ExecutionOrder(wf *api.Workflow) string {
+	if order, ok := wf.Settings["executionOrder"].(string); ok && order != "" {
+		return order
+	}
+	return LegacyExecutionOrder
+}
+
+// onDemandTriggers only start runs when called and can't keep an active
+// workflow running on their own
+var onDemandTriggers = map[string]bool{
+	"n8n-nodes-base.manualTrigger":          true,
+	"n8n-nodes-base.executeWorkflowTrigger": true,
+}
+
+// CheckActivation looks for problems that make n8n reject activating wf,
+// whose own error messages are terse. It fails if no enabled node can
+// start the workflow, or if two webhooks listen on the same method and
+// path. Several triggers are allowed, but reported as warnings since
+// they are often left over from testing.
+func CheckActivation(wf *api.Workflow) (warnings []string, err error) {
+	var triggers, disabled []string
+	for _, node := range wf.Nodes {
+		nodeType, _ := node["type"].(string)
+		if !IsTriggerType(nodeType) || onDemandTriggers[nodeType] {
+			continue
+		}
+		label := fmt.Sprintf("%s [%s]", nodeName(node), nodeType)
+		if off, _ := node["disabled"].(bool); off {
+			disabled = append(disabled, label)
+			continue
+		}
+		triggers = append(triggers, label)
+	}
+
+	switch {
+	case len(triggers) == 0 && len(disabled) > 0:
+		return nil, fmt.Errorf("no trigger node found: all triggers are disabled (%s)", strings.Join(disabled, ", "))
+	case len(triggers) == 0:
+		return nil, fmt.Errorf("no trigger node found: activation needs an enabled trigger, poller, or webhook node (manual and Execute Workflow triggers don't count)")
+	case len(triggers) > 1:
+		warnings = append(warnings, fmt.Sprintf("multiple triggers: %s", strings.Join(triggers, ", ")))
+	}
+
+	seen := make(map[string]string)
+	for _, hook := range Webhooks(wf, "") {
+		if hook.Disabled {
+			continue
+		}
+		for _, method := range hook.Methods {
+			key := method + " " + hook.Path
+			if other, ok := seen[key]; ok {
+				return warnings, fmt.Errorf("conflicting webhooks: %q and %q both listen on %s /%s", other, hook.Node, method, hook.Path)
+			}
+			seen[key] = hook.Node
+		}
+	}
+	return warnings, nil
+}
@@ -2,7 +2,10 @@ package workflow
 
 import (
 	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
 )
 
 // SanitizeFilename converts a workflow name to a safe filename
@@ -30,10 +33,30 @@ func SanitizeFilename(name string) string {
 	return name
 }
 
+// SubWorkflowRef is a reference from a node to another workflow
+type SubWorkflowRef struct {
+	ID       string
+	Node     string
+	NodeType string
+}
+
 // ExtractSubWorkflowIDs extracts workflow IDs referenced by Execute Workflow nodes
 func ExtractSubWorkflowIDs(nodes []map[string]interface{}) []string {
 	var ids []string
 	seen := make(map[string]bool)
+	for _, ref := range ExtractSubWorkflowRefs(nodes) {
+		if !seen[ref.ID] {
+			ids = append(ids, ref.ID)
+			seen[ref.ID] = true
+		}
+	}
+	return ids
+}
+
+// ExtractSubWorkflowRefs returns the workflow references of Execute
+// Workflow nodes, one per node and referenced ID
+func ExtractSubWorkflowRefs(nodes []map[string]interface{}) []SubWorkflowRef {
+	var refs []SubWorkflowRef
 
 	for _, node := range nodes {
 		nodeType, ok := node["type"].(string)
@@ -51,36 +74,131 @@ func ExtractSubWorkflowIDs(nodes []map[string]interface{}) []string {
 				continue
 			}
 
-			// Check different parameter structures
-			// Direct workflow ID
-			if wfID, ok := params["workflowId"].(string); ok && wfID != "" {
-				if !seen[wfID] {
-					ids = append(ids, wfID)
+			seen := make(map[string]bool)
+			add := func(wfID string) {
+				if wfID != "" && !seen[wfID] {
+					refs = append(refs, SubWorkflowRef{ID: wfID, Node: nodeName(node), NodeType: nodeType})
 					seen[wfID] = true
 				}
 			}
 
+			// Check different parameter structures
+			// Direct workflow ID
+			if wfID, ok := params["workflowId"].(string); ok {
+				add(wfID)
+			}
+
 			// Workflow object with id
 			if wf, ok := params["workflow"].(map[string]interface{}); ok {
-				if wfID, ok := wf["id"].(string); ok && wfID != "" {
-					if !seen[wfID] {
-						ids = append(ids, wfID)
-						seen[wfID] = true
-					}
+				if wfID, ok := wf["id"].(string); ok {
+					add(wfID)
 				}
 			}
 
 			// Check for workflow value (expression or direct)
 			if wfValue, ok := params["workflowId"].(map[string]interface{}); ok {
-				if value, ok := wfValue["value"].(string); ok && value != "" {
-					if !seen[value] {
-						ids = append(ids, value)
-						seen[value] = true
-					}
+				if value, ok := wfValue["value"].(string); ok {
+					add(value)
 				}
 			}
 		}
 	}
 
-	return ids
+	return refs
+}
+
+// CredentialRef identifies a credential referenced by a workflow node
+type CredentialRef struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// key returns a value that uniquely identifies the credential
+func (c CredentialRef) key() string {
+	if c.ID != "" {
+		return c.ID
+	}
+	return c.Type + "/" + c.Name
+}
+
+// ExtractCredentials returns the unique credentials referenced by nodes,
+// sorted by name. Each node's credentials block maps the credential type
+// to its reference:
+//
+//	"credentials": {"slackApi": {"id": "12", "name": "Slack account"}}
+func ExtractCredentials(nodes []map[string]interface{}) []CredentialRef {
+	seen := make(map[string]bool)
+	var refs []CredentialRef
+
+	for _, node := range nodes {
+		creds, ok := node["credentials"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for credType, cred := range creds {
+			credMap, ok := cred.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ref := CredentialRef{Type: credType}
+			ref.ID, _ = credMap["id"].(string)
+			ref.Name, _ = credMap["name"].(string)
+			if ref.ID == "" && ref.Name == "" {
+				continue
+			}
+			if seen[ref.key()] {
+				continue
+			}
+			seen[ref.key()] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Name != refs[j].Name {
+			return refs[i].Name < refs[j].Name
+		}
+		return refs[i].key() < refs[j].key()
+	})
+
+	return refs
+}
+
+// MergeCredentials combines credential lists, dropping duplicates
+func MergeCredentials(lists ...[]CredentialRef) []CredentialRef {
+	seen := make(map[string]bool)
+	var merged []CredentialRef
+	for _, list := range lists {
+		for _, ref := range list {
+			if seen[ref.key()] {
+				continue
+			}
+			seen[ref.key()] = true
+			merged = append(merged, ref)
+		}
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Name != merged[j].Name {
+			return merged[i].Name < merged[j].Name
+		}
+		return merged[i].key() < merged[j].key()
+	})
+
+	return merged
+}
+
+// PrunePinData removes data pinned in the editor during testing from a
+// workflow and returns the number of nodes that had pinned data. Static
+// data is left untouched.
+func PrunePinData(wf *api.Workflow) int {
+	pruned := 0
+	for _, items := range wf.PinData {
+		if items != nil {
+			pruned++
+		}
+	}
+	wf.PinData = nil
+	return pruned
 }
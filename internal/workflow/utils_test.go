@@ -0,0 +1,63 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+func TestPrunePinData(t *testing.T) {
+	tests := []struct {
+		name    string
+		pinData map[string]interface{}
+		want    int
+	}{
+		{name: "none", pinData: nil, want: 0},
+		{name: "empty", pinData: map[string]interface{}{}, want: 0},
+		{name: "pinned", pinData: map[string]interface{}{"Webhook": []interface{}{}, "Set": []interface{}{}, "Gone": nil}, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &api.Workflow{PinData: tt.pinData, StaticData: map[string]interface{}{"kept": true}}
+			if got := PrunePinData(wf); got != tt.want {
+				t.Errorf("PrunePinData() = %d, want %d", got, tt.want)
+			}
+			if wf.PinData != nil {
+				t.Errorf("PinData = %v, want nil", wf.PinData)
+			}
+			if wf.StaticData == nil {
+				t.Error("static data was removed")
+			}
+		})
+	}
+}
+
+func TestCompareWithPinData(t *testing.T) {
+	pinned := map[string]interface{}{"Webhook": []interface{}{}}
+	tests := []struct {
+		name      string
+		local     map[string]interface{}
+		remote    map[string]interface{}
+		unchanged bool
+	}{
+		{name: "neither pinned", unchanged: true},
+		{name: "pinned data is not compared", local: pinned, remote: nil, unchanged: true},
+		{name: "empty, remote clean", local: map[string]interface{}{}, remote: nil, unchanged: true},
+		{name: "empty, remote pinned", local: map[string]interface{}{}, remote: pinned, unchanged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local := &api.Workflow{Name: "WF", PinData: tt.local}
+			remote := &api.Workflow{Name: "WF", PinData: tt.remote}
+			unchanged, _, err := CompareWith(local, remote, NodeFilter{})
+			if err != nil {
+				t.Fatalf("CompareWith: %v", err)
+			}
+			if unchanged != tt.unchanged {
+				t.Errorf("unchanged = %v, want %v", unchanged, tt.unchanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// VersionLimits caps the typeVersion of nodes, e.g. for a target instance
+// running an older n8n release that doesn't know newer node versions
+type VersionLimits struct {
+	// Default applies to node types without their own limit (0 = none)
+	Default float64
+	// ByType holds per node type limits
+	ByType map[string]float64
+}
+
+// VersionViolation is a node whose typeVersion exceeds its limit
+type VersionViolation struct {
+	Name    string  `json:"name"`
+	Type    string  `json:"type"`
+	Version float64 `json:"version"`
+	Max     float64 `json:"max"`
+}
+
+func (v VersionViolation) String() string {
+	return fmt.Sprintf("%s [%s] has typeVersion %s (max %s)", v.Name, v.Type, formatVersion(v.Version), formatVersion(v.Max))
+}
+
+// ParseVersionLimits parses --max-node-version values. A plain number
+// applies to all node types, TYPE=VERSION to a single type.
+func ParseVersionLimits(specs []string) (VersionLimits, error) {
+	limits := VersionLimits{ByType: make(map[string]float64)}
+	for _, spec := range specs {
+		nodeType, value, hasType := strings.Cut(spec, "=")
+		if !hasType {
+			value = spec
+		}
+		version, err := strconv.ParseFloat(value, 64)
+		if err != nil || version <= 0 {
+			return limits, fmt.Errorf("invalid node version limit %q (expected VERSION or TYPE=VERSION)", spec)
+		}
+		if hasType {
+			limits.ByType[nodeType] = version
+		} else {
+			limits.Default = version
+		}
+	}
+	return limits, nil
+}
+
+// IsEmpty reports whether no limit is set
+func (l VersionLimits) IsEmpty() bool {
+	return l.Default == 0 && len(l.ByType) == 0
+}
+
+func (l VersionLimits) limit(nodeType string) float64 {
+	if max, ok := l.ByType[nodeType]; ok {
+		return max
+	}
+	return l.Default
+}
+
+// Check returns the nodes of wf whose typeVersion exceeds its limit
+func (l VersionLimits) Check(wf *api.Workflow) []VersionViolation {
+	var violations []VersionViolation
+	for _, node := range wf.Nodes {
+		nodeType, _ := node["type"].(string)
+		version, ok := node["typeVersion"].(float64)
+		max := l.limit(nodeType)
+		if !ok || max == 0 || version <= max {
+			continue
+		}
+		violations = append(violations, VersionViolation{
+			Name:    nodeName(node),
+			Type:    nodeType,
+			Version: version,
+			Max:     max,
+		})
+	}
+	return violations
+}
+
+// Downgrade lowers the typeVersion of every node exceeding its limit to
+// the limit and returns the nodes it changed. Only the version field is
+// touched; parameters that differ between versions are left as they are,
+// so the result should be reviewed in the editor.
+func (l VersionLimits) Downgrade(wf *api.Workflow) []VersionViolation {
+	violations := l.Check(wf)
+	for _, v := range violations {
+		for _, node := range wf.Nodes {
+			if nodeName(node) == v.Name {
+				node["typeVersion"] = v.Max
+			}
+		}
+	}
+	return violations
+}
+
+// VersionError reports nodes that exceed the version limits
+type VersionError struct {
+	Workflow   string
+	Violations []VersionViolation
+}
+
+func (e *VersionError) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = "  - " + v.String()
+	}
+	return fmt.Sprintf("workflow %s has %d node(s) above the version limit (use --downgrade to lower them):\n%s",
+		e.Workflow, len(e.Violations), strings.Join(lines, "\n"))
+}
+
+func formatVersion(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
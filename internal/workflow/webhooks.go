@@ -0,0 +1,102 @@
+package workflow
+
+import (
+	"strings"
+
+	"github.com/enthus-appdev/n8n-cli/internal/api"
+)
+
+// Webhook is an HTTP endpoint registered by a node of a workflow
+type Webhook struct {
+	Node     string   `json:"node"`
+	NodeType string   `json:"nodeType"`
+	Methods  []string `json:"methods"`
+	Path     string   `json:"path"`
+	// ProductionURL only responds while the workflow is active
+	ProductionURL string `json:"productionUrl"`
+	// TestURL responds while the workflow is listening for a test event
+	// in the editor
+	TestURL  string `json:"testUrl"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// webhookPrefixes are the URL prefixes of node types that serve webhooks:
+// production and test
+var webhookPrefixes = map[string][2]string{
+	"n8n-nodes-base.webhook":     {"webhook", "webhook-test"},
+	"n8n-nodes-base.formTrigger": {"form", "form-test"},
+}
+
+// Webhooks returns the webhook endpoints of wf's Webhook and Form Trigger
+// nodes, with URLs below baseURL, the instance URL. Instances serving
+// webhooks under a different WEBHOOK_URL need that URL as baseURL.
+func Webhooks(wf *api.Workflow, baseURL string) []Webhook {
+	baseURL = strings.TrimRight(baseURL, "/")
+
+	var hooks []Webhook
+	for _, node := range wf.Nodes {
+		nodeType, _ := node["type"].(string)
+		prefixes, ok := webhookPrefixes[nodeType]
+		if !ok {
+			continue
+		}
+		params, _ := node["parameters"].(map[string]interface{})
+		webhookID, _ := node["webhookId"].(string)
+		disabled, _ := node["disabled"].(bool)
+
+		path := webhookPath(params, webhookID)
+		hooks = append(hooks, Webhook{
+			Node:          nodeName(node),
+			NodeType:      nodeType,
+			Methods:       webhookMethods(nodeType, params),
+			Path:          path,
+			ProductionURL: baseURL + "/" + prefixes[0] + "/" + path,
+			TestURL:       baseURL + "/" + prefixes[1] + "/" + path,
+			Disabled:      disabled,
+		})
+	}
+	return hooks
+}
+
+// webhookPath returns the path n8n registers a webhook under: the node's
+// path parameter, the webhook ID if the path is empty, and both if the
+// path has route parameters such as :id
+func webhookPath(params map[string]interface{}, webhookID string) string {
+	path, _ := params["path"].(string)
+	path = strings.Trim(path, "/")
+	switch {
+	case path == "":
+		return webhookID
+	case strings.Contains(path, ":") && webhookID != "":
+		return webhookID + "/" + path
+	default:
+		return path
+	}
+}
+
+// webhookMethods returns the HTTP methods a webhook node accepts
+func webhookMethods(nodeType string, params map[string]interface{}) []string {
+	if nodeType == "n8n-nodes-base.formTrigger" {
+		// The form is shown on GET and submitted with POST
+		return []string{"GET", "POST"}
+	}
+
+	if multiple, _ := params["multipleMethods"].(bool); multiple {
+		list, ok := params["httpMethod"].([]interface{})
+		if !ok {
+			return []string{"GET", "POST"}
+		}
+		methods := make([]string, 0, len(list))
+		for _, m := range list {
+			if s, ok := m.(string); ok {
+				methods = append(methods, s)
+			}
+		}
+		return methods
+	}
+
+	if method, ok := params["httpMethod"].(string); ok && method != "" {
+		return []string{method}
+	}
+	return []string{"GET"}
+}